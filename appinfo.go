@@ -0,0 +1,252 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+const (
+	// AppInfoMagic27 identifies appinfo.vdf version 27.
+	AppInfoMagic27 uint32 = 0x07564427
+	// AppInfoMagic28 identifies appinfo.vdf version 28, which adds a binary payload SHA-1.
+	AppInfoMagic28 uint32 = 0x07564428
+	// AppInfoMagic29 identifies appinfo.vdf version 29, which stores keys in a shared string table.
+	AppInfoMagic29 uint32 = 0x07564429
+)
+
+const (
+	// appInfoFixedSize27 is the byte length of fixed v27 record fields after the size field.
+	appInfoFixedSize27 = 4 + 4 + 8 + 20 + 4
+	// appInfoFixedSize28 is the byte length of fixed v28+ record fields after the size field.
+	appInfoFixedSize28 = appInfoFixedSize27 + 20
+)
+
+// AppInfoHeader is the appinfo.vdf container header.
+type AppInfoHeader struct {
+	// StringTableOffset is the file offset of the shared key table, set from version 29.
+	StringTableOffset int64 `json:"string_table_offset,omitempty" yaml:"string_table_offset,omitempty"`
+	// Magic identifies the container version, see AppInfoMagic27 and later.
+	Magic uint32 `json:"magic" yaml:"magic"`
+	// Universe is the Steam universe the cache belongs to.
+	Universe uint32 `json:"universe" yaml:"universe"`
+}
+
+// Version returns the container version encoded in the low byte of Magic,
+// whose hex digits spell the decimal version (0x28 is version 28).
+func (h AppInfoHeader) Version() int {
+	low := h.Magic & 0xFF
+	return int(low>>4)*10 + int(low&0x0F)
+}
+
+// AppInfoRecord is one application entry of appinfo.vdf.
+type AppInfoRecord struct {
+	// Doc is the decoded binary KeyValues payload.
+	Doc *Document `json:"doc" yaml:"doc"`
+	// PICSToken is the access token used to request the app info.
+	PICSToken uint64 `json:"pics_token" yaml:"pics_token"`
+	// AppID is the Steam application id.
+	AppID uint32 `json:"app_id" yaml:"app_id"`
+	// InfoState is the Steam client info state of the entry.
+	InfoState uint32 `json:"info_state" yaml:"info_state"`
+	// LastUpdated is the Unix time of the last update.
+	LastUpdated uint32 `json:"last_updated" yaml:"last_updated"`
+	// ChangeNumber is the PICS change number of the entry.
+	ChangeNumber uint32 `json:"change_number" yaml:"change_number"`
+	// TextSHA1 is the SHA-1 of the text form of the payload.
+	TextSHA1 [20]byte `json:"text_sha1" yaml:"text_sha1"`
+	// BinarySHA1 is the SHA-1 of the binary payload; it is zero for version 27.
+	BinarySHA1 [20]byte `json:"binary_sha1" yaml:"binary_sha1"`
+}
+
+// AppInfoReader reads application records from an appinfo.vdf stream one at a time,
+// so memory use is bounded by the largest record rather than by the file.
+type AppInfoReader struct {
+	r      io.Reader     // Source stream positioned at the next record.
+	err    error         // First error met by Apps.
+	opts   DecodeOptions // Decode options for record payloads.
+	header AppInfoHeader // Container header.
+	done   bool          // Whether the end marker was read.
+}
+
+// NewAppInfoReader reads the appinfo.vdf header from r and returns a record reader.
+// Record payloads are decoded as binary VDF with opts; opts.Format is ignored.
+// Version 29 keeps keys in a string table at the end of the file, so r must then
+// implement io.Seeker; the table is loaded into opts.StringPool up front.
+// It fails with ErrUnsupportedAppInfoVersion for unknown container versions.
+func NewAppInfoReader(r io.Reader, opts DecodeOptions) (*AppInfoReader, error) {
+	var raw [8]byte
+	if _, err := io.ReadFull(r, raw[:]); err != nil {
+		return nil, fmt.Errorf("%w: header: %w", ErrInvalidAppInfo, err)
+	}
+
+	header := AppInfoHeader{
+		Magic:    binary.LittleEndian.Uint32(raw[0:4]),
+		Universe: binary.LittleEndian.Uint32(raw[4:8]),
+	}
+
+	switch header.Magic {
+	case AppInfoMagic27, AppInfoMagic28:
+	case AppInfoMagic29:
+		seeker, ok := r.(io.ReadSeeker)
+		if !ok {
+			return nil, fmt.Errorf("%w: version 29 requires an io.ReadSeeker", ErrUnsupportedAppInfoVersion)
+		}
+
+		if _, err := io.ReadFull(r, raw[:]); err != nil {
+			return nil, fmt.Errorf("%w: header: %w", ErrInvalidAppInfo, err)
+		}
+
+		header.StringTableOffset = int64(binary.LittleEndian.Uint64(raw[:]))
+		table, err := readAppInfoStringTable(seeker, header.StringTableOffset)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.StringPool = table
+	default:
+		return nil, fmt.Errorf("%w: magic 0x%08x", ErrUnsupportedAppInfoVersion, header.Magic)
+	}
+
+	opts.Format = FormatBinary
+	return &AppInfoReader{r: r, opts: opts, header: header}, nil
+}
+
+// Header returns the container header.
+func (a *AppInfoReader) Header() AppInfoHeader {
+	return a.header
+}
+
+// Next reads and decodes the next record and returns io.EOF after the last one.
+func (a *AppInfoReader) Next() (*AppInfoRecord, error) {
+	if a.done {
+		return nil, io.EOF
+	}
+
+	var prefix [8]byte
+	if _, err := io.ReadFull(a.r, prefix[:4]); err != nil {
+		return nil, fmt.Errorf("%w: record: %w", ErrInvalidAppInfo, err)
+	}
+
+	appID := binary.LittleEndian.Uint32(prefix[:4])
+	if appID == 0 {
+		a.done = true
+		return nil, io.EOF
+	}
+
+	if _, err := io.ReadFull(a.r, prefix[4:]); err != nil {
+		return nil, fmt.Errorf("%w: app %d: %w", ErrInvalidAppInfo, appID, err)
+	}
+
+	// Read through a limit instead of allocating the declared size up front,
+	// so a corrupt size field cannot force a huge allocation.
+	size := int64(binary.LittleEndian.Uint32(prefix[4:]))
+	body, err := io.ReadAll(io.LimitReader(a.r, size))
+	if err != nil {
+		return nil, err
+	}
+
+	fixed := a.fixedSize()
+	if int64(len(body)) != size || len(body) < fixed {
+		return nil, fmt.Errorf("%w: app %d: truncated record", ErrInvalidAppInfo, appID)
+	}
+
+	record := &AppInfoRecord{
+		AppID:        appID,
+		InfoState:    binary.LittleEndian.Uint32(body[0:4]),
+		LastUpdated:  binary.LittleEndian.Uint32(body[4:8]),
+		PICSToken:    binary.LittleEndian.Uint64(body[8:16]),
+		ChangeNumber: binary.LittleEndian.Uint32(body[36:40]),
+	}
+	copy(record.TextSHA1[:], body[16:36])
+	if fixed == appInfoFixedSize28 {
+		copy(record.BinarySHA1[:], body[40:60])
+	}
+
+	record.Doc, err = ParseBytes(body[fixed:], a.opts)
+	if err != nil {
+		return nil, fmt.Errorf("app %d: %w", appID, err)
+	}
+
+	return record, nil
+}
+
+// Apps returns an iterator over application ids and decoded payloads.
+// Iteration stops at the first error, which is then reported by Err.
+func (a *AppInfoReader) Apps() iter.Seq2[uint32, *Document] {
+	return func(yield func(uint32, *Document) bool) {
+		for {
+			record, err := a.Next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if err != nil {
+				a.err = err
+				return
+			}
+
+			if !yield(record.AppID, record.Doc) {
+				return
+			}
+		}
+	}
+}
+
+// Err returns the first error met while iterating Apps.
+func (a *AppInfoReader) Err() error {
+	return a.err
+}
+
+// fixedSize returns the byte length of fixed record fields for the container version.
+func (a *AppInfoReader) fixedSize() int {
+	if a.header.Magic == AppInfoMagic27 {
+		return appInfoFixedSize27
+	}
+
+	return appInfoFixedSize28
+}
+
+// readAppInfoStringTable loads the version 29 key table at offset
+// and restores the stream position afterwards.
+func readAppInfoStringTable(r io.ReadSeeker, offset int64) ([]string, error) {
+	position, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("%w: string table offset %d: %w", ErrInvalidAppInfo, offset, err)
+	}
+
+	br := bufio.NewReader(r)
+	var raw [4]byte
+	if _, err := io.ReadFull(br, raw[:]); err != nil {
+		return nil, fmt.Errorf("%w: string table: %w", ErrInvalidAppInfo, err)
+	}
+
+	// The count is untrusted, so the capacity hint is capped.
+	count := binary.LittleEndian.Uint32(raw[:])
+	table := make([]string, 0, min(count, 1<<16))
+	for range count {
+		value, err := br.ReadString(0)
+		if err != nil {
+			return nil, fmt.Errorf("%w: string table entry %d: %w", ErrInvalidAppInfo, len(table), err)
+		}
+
+		table = append(table, value[:len(value)-1])
+	}
+
+	if _, err := r.Seek(position, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
@@ -0,0 +1,172 @@
+package vdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+// appInfoTestRecord builds one v28 appinfo record around a binary payload.
+func appInfoTestRecord(tb testing.TB, appID, changeNumber uint32, payload []byte) []byte {
+	tb.Helper()
+
+	var body []byte
+	body = binary.LittleEndian.AppendUint32(body, 2)          // info state
+	body = binary.LittleEndian.AppendUint32(body, 1700000000) // last updated
+	body = binary.LittleEndian.AppendUint64(body, 0)          // PICS token
+	body = append(body, bytes.Repeat([]byte{0xAA}, 20)...)
+	body = binary.LittleEndian.AppendUint32(body, changeNumber)
+	body = append(body, bytes.Repeat([]byte{0xBB}, 20)...)
+	body = append(body, payload...)
+
+	var record []byte
+	record = binary.LittleEndian.AppendUint32(record, appID)
+	record = binary.LittleEndian.AppendUint32(record, uint32(len(body)))
+	return append(record, body...)
+}
+
+// appInfoTestPayload encodes a minimal appinfo KeyValues payload.
+func appInfoTestPayload(tb testing.TB, appID uint32, name string) []byte {
+	tb.Helper()
+
+	doc := NewDocumentWithFormat(FormatBinary)
+	root := NewObjectNode("appinfo")
+	root.Add(NewUint32Node("appid", appID))
+	common := NewObjectNode("common")
+	common.Add(NewStringNode("name", name))
+	root.Add(common)
+	doc.AddRoot(root)
+
+	data, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		tb.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	return data
+}
+
+func TestAppInfoReader(t *testing.T) {
+	t.Parallel()
+
+	var input []byte
+	input = binary.LittleEndian.AppendUint32(input, AppInfoMagic28)
+	input = binary.LittleEndian.AppendUint32(input, 1)
+	input = append(input, appInfoTestRecord(t, 10, 100, appInfoTestPayload(t, 10, "Counter-Strike"))...)
+	input = append(input, appInfoTestRecord(t, 440, 200, appInfoTestPayload(t, 440, "Team Fortress 2"))...)
+	input = binary.LittleEndian.AppendUint32(input, 0)
+
+	reader, err := NewAppInfoReader(bytes.NewReader(input), DecodeOptions{})
+	if err != nil {
+		t.Fatalf("NewAppInfoReader() returned error: %v", err)
+	}
+
+	if header := reader.Header(); header.Version() != 28 || header.Universe != 1 {
+		t.Fatalf("Header() = %+v", header)
+	}
+
+	names := map[uint32]string{}
+	for appID, doc := range reader.Apps() {
+		name := doc.Roots[0].First("common").First("name")
+		if name == nil {
+			t.Fatalf("app %d: missing common/name", appID)
+		}
+
+		names[appID] = *name.StringValue
+	}
+
+	if err := reader.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	if len(names) != 2 || names[10] != "Counter-Strike" || names[440] != "Team Fortress 2" {
+		t.Fatalf("apps = %v", names)
+	}
+
+	reader, err = NewAppInfoReader(bytes.NewReader(input), DecodeOptions{})
+	if err != nil {
+		t.Fatalf("NewAppInfoReader() returned error: %v", err)
+	}
+
+	record, err := reader.Next()
+	if err != nil {
+		t.Fatalf("Next() returned error: %v", err)
+	}
+
+	if record.AppID != 10 || record.ChangeNumber != 100 || record.InfoState != 2 || record.BinarySHA1[0] != 0xBB {
+		t.Fatalf("Next() = %+v", record)
+	}
+
+	truncated := input[:len(input)-12]
+	reader, err = NewAppInfoReader(bytes.NewReader(truncated), DecodeOptions{})
+	if err != nil {
+		t.Fatalf("NewAppInfoReader() returned error: %v", err)
+	}
+
+	for range reader.Apps() {
+	}
+
+	if !errors.Is(reader.Err(), ErrInvalidAppInfo) {
+		t.Fatalf("Err() = %v, want ErrInvalidAppInfo", reader.Err())
+	}
+
+	bad := binary.LittleEndian.AppendUint32(nil, 0x07564420)
+	bad = binary.LittleEndian.AppendUint32(bad, 1)
+	if _, err := NewAppInfoReader(bytes.NewReader(bad), DecodeOptions{}); !errors.Is(err, ErrUnsupportedAppInfoVersion) {
+		t.Fatalf("NewAppInfoReader(bad magic) error = %v, want ErrUnsupportedAppInfoVersion", err)
+	}
+}
+
+func TestAppInfoReaderStringPool(t *testing.T) {
+	t.Parallel()
+
+	key := func(b []byte, typeByte byte, index uint32) []byte {
+		return binary.LittleEndian.AppendUint32(append(b, typeByte), index)
+	}
+
+	var payload []byte
+	payload = key(payload, binaryTypeMapStart, 0)
+	payload = binary.LittleEndian.AppendUint32(key(payload, binaryTypeNumber, 1), 730)
+	payload = key(payload, binaryTypeMapStart, 2)
+	payload = append(key(payload, binaryTypeString, 3), "Counter-Strike 2\x00"...)
+	payload = append(payload, binaryTypeMapEnd, binaryTypeMapEnd, binaryTypeMapEnd)
+
+	var input []byte
+	input = binary.LittleEndian.AppendUint32(input, AppInfoMagic29)
+	input = binary.LittleEndian.AppendUint32(input, 1)
+	input = binary.LittleEndian.AppendUint64(input, 0) // patched below
+	input = append(input, appInfoTestRecord(t, 730, 1, payload)...)
+	input = binary.LittleEndian.AppendUint32(input, 0)
+
+	binary.LittleEndian.PutUint64(input[8:16], uint64(len(input)))
+	input = binary.LittleEndian.AppendUint32(input, 4)
+	input = append(input, "appinfo\x00appid\x00common\x00name\x00"...)
+
+	reader, err := NewAppInfoReader(bytes.NewReader(input), DecodeOptions{})
+	if err != nil {
+		t.Fatalf("NewAppInfoReader() returned error: %v", err)
+	}
+
+	if header := reader.Header(); header.Version() != 29 || header.StringTableOffset == 0 {
+		t.Fatalf("Header() = %+v", header)
+	}
+
+	record, err := reader.Next()
+	if err != nil {
+		t.Fatalf("Next() returned error: %v", err)
+	}
+
+	root := record.Doc.Roots[0]
+	if root.Key != "appinfo" || *root.First("appid").Uint32Value != 730 || *root.First("common").First("name").StringValue != "Counter-Strike 2" {
+		t.Fatalf("decoded payload root = %+v", root)
+	}
+
+	if _, err := ParseBytes(payload, DecodeOptions{Format: FormatBinary, StringPool: []string{"appinfo"}}); !errors.Is(err, ErrStringPoolIndex) {
+		t.Fatalf("ParseBytes(short pool) error = %v, want ErrStringPoolIndex", err)
+	}
+
+	if _, err := NewAppInfoReader(struct{ io.Reader }{bytes.NewReader(input)}, DecodeOptions{}); !errors.Is(err, ErrUnsupportedAppInfoVersion) {
+		t.Fatalf("NewAppInfoReader(non-seeker) error = %v, want ErrUnsupportedAppInfoVersion", err)
+	}
+}
@@ -0,0 +1,244 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// appManifestRootKey is the root key of Steam appmanifest_*.acf files.
+const appManifestRootKey = "AppState"
+
+// AppManifest is the typed content of a Steam appmanifest_*.acf file.
+type AppManifest struct {
+	// UserConfig holds the per-user settings such as "language"; nil omits the object.
+	UserConfig map[string]string `json:"user_config,omitempty" yaml:"user_config,omitempty"`
+	// Extra holds keys without a dedicated field, kept in source order for write-back.
+	Extra []*Node `json:"extra,omitempty" yaml:"extra,omitempty"`
+	// InstalledDepots lists the installed depots in source order; nil omits the object.
+	InstalledDepots []AppManifestDepot `json:"installed_depots,omitempty" yaml:"installed_depots,omitempty"`
+	// Name is the application name.
+	Name string `json:"name" yaml:"name"`
+	// InstallDir is the directory name under steamapps/common.
+	InstallDir string `json:"install_dir" yaml:"install_dir"`
+	// SizeOnDisk is the installed size in bytes.
+	SizeOnDisk uint64 `json:"size_on_disk" yaml:"size_on_disk"`
+	// LastOwner is the SteamID64 of the account that last updated the app.
+	LastOwner uint64 `json:"last_owner,omitempty" yaml:"last_owner,omitempty"`
+	// AppID is the Steam application id.
+	AppID uint32 `json:"app_id" yaml:"app_id"`
+	// StateFlags is the Steam client install state bit set; 4 means fully installed.
+	StateFlags uint32 `json:"state_flags" yaml:"state_flags"`
+	// LastUpdated is the Unix time of the last update.
+	LastUpdated uint32 `json:"last_updated,omitempty" yaml:"last_updated,omitempty"`
+	// BuildID is the installed build id.
+	BuildID uint32 `json:"build_id,omitempty" yaml:"build_id,omitempty"`
+}
+
+// AppManifestDepot is one entry of the InstalledDepots object.
+type AppManifestDepot struct {
+	// Manifest is the installed depot manifest id.
+	Manifest uint64 `json:"manifest" yaml:"manifest"`
+	// Size is the depot size in bytes.
+	Size uint64 `json:"size" yaml:"size"`
+	// DepotID is the depot id keying the entry.
+	DepotID uint32 `json:"depot_id" yaml:"depot_id"`
+	// DLCAppID is the DLC app the depot belongs to, or zero.
+	DLCAppID uint32 `json:"dlc_app_id,omitempty" yaml:"dlc_app_id,omitempty"`
+}
+
+// ParseAppManifest decodes appmanifest data into its typed model.
+func ParseAppManifest(data []byte) (*AppManifest, error) {
+	doc, err := ParseBytes(data, DecodeOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return AppManifestFromDocument(doc)
+}
+
+// WriteAppManifest encodes the manifest as text in the layout Steam writes.
+func WriteAppManifest(w io.Writer, manifest *AppManifest) error {
+	if manifest == nil {
+		return fmt.Errorf("%w: nil app manifest", ErrInvalidNodeState)
+	}
+
+	return NewEncoder(w, EncodeOptions{Format: FormatText}).EncodeDocument(AppManifestDocument(manifest))
+}
+
+// AppManifestFromDocument reads the AppState object of a decoded appmanifest document.
+// Keys are matched case-insensitively.
+func AppManifestFromDocument(doc *Document) (*AppManifest, error) {
+	var root *Node
+	if doc != nil {
+		root = fieldChild(documentObject(doc), appManifestRootKey)
+	}
+
+	if root == nil || root.Kind != NodeObject {
+		return nil, fmt.Errorf("%w: missing %q object", ErrValueConversion, appManifestRootKey)
+	}
+
+	manifest := &AppManifest{}
+	for _, child := range root.Children {
+		if child == nil {
+			continue
+		}
+
+		var err error
+		switch strings.ToLower(child.Key) {
+		case "appid":
+			manifest.AppID, err = steamUint32(child)
+		case "name":
+			manifest.Name, err = steamText(child)
+		case "stateflags":
+			manifest.StateFlags, err = steamUint32(child)
+		case "installdir":
+			manifest.InstallDir, err = steamText(child)
+		case "lastupdated":
+			manifest.LastUpdated, err = steamUint32(child)
+		case "sizeondisk":
+			manifest.SizeOnDisk, err = steamUint64(child)
+		case "buildid":
+			manifest.BuildID, err = steamUint32(child)
+		case "lastowner":
+			manifest.LastOwner, err = steamUint64(child)
+		case "installeddepots":
+			manifest.InstalledDepots, err = appManifestDepots(child)
+		case "userconfig":
+			manifest.UserConfig, err = appManifestUserConfig(child)
+		default:
+			manifest.Extra = append(manifest.Extra, cloneNode(child))
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", appManifestRootKey, err)
+		}
+	}
+
+	return manifest, nil
+}
+
+// AppManifestDocument builds an appmanifest document from the typed model.
+// Known keys come first in the order Steam writes them, then Extra, then
+// InstalledDepots and UserConfig with its keys sorted.
+func AppManifestDocument(manifest *AppManifest) *Document {
+	root := NewObjectNode(appManifestRootKey)
+	root.Add(NewStringNode("appid", strconv.FormatUint(uint64(manifest.AppID), 10)))
+	root.Add(NewStringNode("name", manifest.Name))
+	root.Add(NewStringNode("StateFlags", strconv.FormatUint(uint64(manifest.StateFlags), 10)))
+	root.Add(NewStringNode("installdir", manifest.InstallDir))
+	root.Add(NewStringNode("LastUpdated", strconv.FormatUint(uint64(manifest.LastUpdated), 10)))
+	root.Add(NewStringNode("SizeOnDisk", strconv.FormatUint(manifest.SizeOnDisk, 10)))
+	root.Add(NewStringNode("buildid", strconv.FormatUint(uint64(manifest.BuildID), 10)))
+	root.Add(NewStringNode("LastOwner", strconv.FormatUint(manifest.LastOwner, 10)))
+	for _, extra := range manifest.Extra {
+		root.Add(cloneNode(extra))
+	}
+
+	if manifest.InstalledDepots != nil {
+		depots := NewObjectNode("InstalledDepots")
+		for _, depot := range manifest.InstalledDepots {
+			entry := NewObjectNode(strconv.FormatUint(uint64(depot.DepotID), 10))
+			entry.Add(NewStringNode("manifest", strconv.FormatUint(depot.Manifest, 10)))
+			entry.Add(NewStringNode("size", strconv.FormatUint(depot.Size, 10)))
+			if depot.DLCAppID != 0 {
+				entry.Add(NewStringNode("dlcappid", strconv.FormatUint(uint64(depot.DLCAppID), 10)))
+			}
+
+			depots.Add(entry)
+		}
+
+		root.Add(depots)
+	}
+
+	if manifest.UserConfig != nil {
+		config := NewObjectNode("UserConfig")
+		keys := make([]string, 0, len(manifest.UserConfig))
+		for key := range manifest.UserConfig {
+			keys = append(keys, key)
+		}
+
+		slices.Sort(keys)
+		for _, key := range keys {
+			config.Add(NewStringNode(key, manifest.UserConfig[key]))
+		}
+
+		root.Add(config)
+	}
+
+	doc := NewDocumentWithFormat(FormatText)
+	doc.AddRoot(root)
+	return doc
+}
+
+// appManifestDepots reads the InstalledDepots object.
+func appManifestDepots(node *Node) ([]AppManifestDepot, error) {
+	if node.Kind != NodeObject {
+		return nil, fmt.Errorf("%w: key %q is not an object", ErrValueConversion, node.Key)
+	}
+
+	depots := make([]AppManifestDepot, 0, len(node.Children))
+	for _, entry := range node.Children {
+		if entry == nil {
+			continue
+		}
+
+		id, err := strconv.ParseUint(entry.Key, 10, 32)
+		if err != nil || entry.Kind != NodeObject {
+			return nil, fmt.Errorf("%w: depot %q is not a depot object", ErrValueConversion, entry.Key)
+		}
+
+		depot := AppManifestDepot{DepotID: uint32(id)}
+		for _, child := range entry.Children {
+			if child == nil {
+				continue
+			}
+
+			switch strings.ToLower(child.Key) {
+			case "manifest":
+				depot.Manifest, err = steamUint64(child)
+			case "size":
+				depot.Size, err = steamUint64(child)
+			case "dlcappid":
+				depot.DLCAppID, err = steamUint32(child)
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("depot %q: %w", entry.Key, err)
+			}
+		}
+
+		depots = append(depots, depot)
+	}
+
+	return depots, nil
+}
+
+// appManifestUserConfig reads the UserConfig object into a map.
+func appManifestUserConfig(node *Node) (map[string]string, error) {
+	if node.Kind != NodeObject {
+		return nil, fmt.Errorf("%w: key %q is not an object", ErrValueConversion, node.Key)
+	}
+
+	config := make(map[string]string, len(node.Children))
+	for _, child := range node.Children {
+		if child == nil {
+			continue
+		}
+
+		value, err := steamText(child)
+		if err != nil {
+			return nil, err
+		}
+
+		config[child.Key] = value
+	}
+
+	return config, nil
+}
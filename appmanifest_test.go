@@ -0,0 +1,87 @@
+package vdf
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestAppManifestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	input := []byte(`"AppState"
+{
+	"appid"		"440"
+	"universe"		"1"
+	"name"		"Team Fortress 2"
+	"StateFlags"		"4"
+	"installdir"		"Team Fortress 2"
+	"LastUpdated"		"1700000000"
+	"SizeOnDisk"		"26749236131"
+	"buildid"		"12345678"
+	"LastOwner"		"76561197960287930"
+	"InstalledDepots"
+	{
+		"441"
+		{
+			"manifest"		"7646733005443296581"
+			"size"		"26749236131"
+		}
+		"1200"
+		{
+			"manifest"		"1"
+			"size"		"2"
+			"dlcappid"		"1200"
+		}
+	}
+	"UserConfig"
+	{
+		"language"		"english"
+	}
+}`)
+
+	manifest, err := ParseAppManifest(input)
+	if err != nil {
+		t.Fatalf("ParseAppManifest() returned error: %v", err)
+	}
+
+	want := &AppManifest{
+		UserConfig: map[string]string{"language": "english"},
+		Extra:      []*Node{NewStringNode("universe", "1")},
+		InstalledDepots: []AppManifestDepot{
+			{DepotID: 441, Manifest: 7646733005443296581, Size: 26749236131},
+			{DepotID: 1200, Manifest: 1, Size: 2, DLCAppID: 1200},
+		},
+		Name:        "Team Fortress 2",
+		InstallDir:  "Team Fortress 2",
+		SizeOnDisk:  26749236131,
+		LastOwner:   76561197960287930,
+		AppID:       440,
+		StateFlags:  4,
+		LastUpdated: 1700000000,
+		BuildID:     12345678,
+	}
+
+	if len(manifest.Extra) != 1 || manifest.Extra[0].Key != "universe" {
+		t.Fatalf("Extra = %+v", manifest.Extra)
+	}
+
+	manifest.Extra, want.Extra = nil, nil
+	if !reflect.DeepEqual(manifest, want) {
+		t.Fatalf("ParseAppManifest() = %+v, want %+v", manifest, want)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAppManifest(&buf, want); err != nil {
+		t.Fatalf("WriteAppManifest() returned error: %v", err)
+	}
+
+	again, err := ParseAppManifest(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseAppManifest() after write returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(again, want) {
+		t.Fatalf("round trip = %+v, want %+v", again, want)
+	}
+}
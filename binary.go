@@ -13,3 +13,37 @@ func ParseAuto(data []byte) (*Document, error) {
 func ParseAutoFile(path string) (*Document, error) {
 	return ParseFile(path, DecodeOptions{Format: FormatAuto})
 }
+
+// MarshalText implements encoding.TextMarshaler by encoding text VDF.
+// JSON and YAML keep the AST shape through MarshalJSON and MarshalYAML.
+func (d *Document) MarshalText() ([]byte, error) {
+	return AppendText(nil, d, EncodeOptions{})
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, replacing the document
+// with data decoded with automatic format detection.
+func (d *Document) UnmarshalText(data []byte) error {
+	return d.unmarshalAuto(data)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler by encoding binary VDF.
+func (d *Document) MarshalBinary() ([]byte, error) {
+	return AppendBinary(nil, d, EncodeOptions{})
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, replacing the document
+// with data decoded with automatic format detection.
+func (d *Document) UnmarshalBinary(data []byte) error {
+	return d.unmarshalAuto(data)
+}
+
+// unmarshalAuto replaces the document with auto-detected VDF data.
+func (d *Document) unmarshalAuto(data []byte) error {
+	doc, err := ParseAuto(data)
+	if err != nil {
+		return err
+	}
+
+	*d = *doc
+	return nil
+}
@@ -6,11 +6,14 @@ package vdf
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"sync"
+	"unicode/utf16"
 )
 
 const (
@@ -20,10 +23,38 @@ const (
 	binaryTypeString byte = 0x01
 	// binaryTypeNumber marks a uint32 value.
 	binaryTypeNumber byte = 0x02
+	// binaryTypeFloat32 marks a float32 value.
+	binaryTypeFloat32 byte = 0x03
+	// binaryTypePointer marks a 32-bit pointer value.
+	binaryTypePointer byte = 0x04
+	// binaryTypeWideString marks a null-terminated UTF-16LE string value.
+	binaryTypeWideString byte = 0x05
+	// binaryTypeColor marks a 4-byte RGBA color value.
+	binaryTypeColor byte = 0x06
+	// binaryTypeUint64 marks a little-endian uint64 value.
+	binaryTypeUint64 byte = 0x07
+	// binaryTypeInt64 marks a little-endian int64 value.
+	binaryTypeInt64 byte = 0x0A
 	// binaryTypeMapEnd marks end of current object map.
 	binaryTypeMapEnd byte = 0x08
 )
 
+// isBuiltinBinaryType reports whether typeByte is a type marker with built-in meaning.
+func isBuiltinBinaryType(typeByte byte) bool {
+	switch typeByte {
+	case binaryTypeMapStart, binaryTypeString, binaryTypeNumber, binaryTypeFloat32, binaryTypePointer,
+		binaryTypeWideString, binaryTypeColor, binaryTypeUint64, binaryTypeInt64, binaryTypeMapEnd:
+		return true
+	default:
+		return false
+	}
+}
+
+// builtinRawTypeSizes lists payload sizes of Valve KeyValues extension types
+// without native node kinds that can be preserved as NodeRaw leaves.
+// Every known Valve type is native now; DecodeOptions.RawTypeSizes covers vendor types.
+var builtinRawTypeSizes = map[byte]int{}
+
 // binaryStringBufferPool reuses temporary buffers for binary string decoding.
 var binaryStringBufferPool = sync.Pool{
 	New: func() any {
@@ -35,6 +66,8 @@ var binaryStringBufferPool = sync.Pool{
 // binaryDecoder parses binary VDF stream.
 type binaryDecoder struct {
 	reader    binaryReadReader // Reader for the input.
+	counter   *countingReader  // Byte counter for spans, stream offsets and BinaryError.
+	path      []string         // Keys of currently open objects.
 	opts      DecodeOptions    // Decode options.
 	nodeCount int              // Number of nodes parsed.
 }
@@ -46,13 +79,57 @@ type binaryReadReader interface {
 }
 
 // parseBinaryDocument decodes binary VDF from a stream.
-func parseBinaryDocument(r io.Reader, opts DecodeOptions) (*Document, error) {
+func parseBinaryDocument(r io.Reader, opts DecodeOptions) (doc *Document, err error) {
+	reader, expected, wrapped, err := stripVBKVHeader(ensureBinaryReader(r))
+	if err != nil {
+		return nil, err
+	}
+
 	decoder := &binaryDecoder{
-		reader: ensureBinaryReader(r),
+		reader: reader,
 		opts:   opts,
 	}
 
-	return decoder.decodeDocument()
+	// The byte counter also positions BinaryError.
+	decoder.counter = &countingReader{r: decoder.reader}
+	if wrapped {
+		decoder.counter.n = vbkvHeaderSize
+	}
+
+	decoder.reader = decoder.counter
+
+	if wrapped && !opts.SkipVBKVChecksum {
+		body := &checksumReader{r: decoder.reader}
+		decoder.reader = body
+		defer func() {
+			if err == nil && body.crc != expected {
+				doc, err = nil, fmt.Errorf("%w: VBKV header 0x%08x, body 0x%08x", ErrChecksumMismatch, expected, body.crc)
+			}
+		}()
+	}
+
+	if !opts.VerifyChecksum {
+		doc, err = decoder.decodeDocument()
+		if err != nil {
+			return nil, &BinaryError{Err: err, Offset: decoder.offset()}
+		}
+
+		return doc, nil
+	}
+
+	checksum := &checksumReader{r: decoder.reader}
+	decoder.reader = checksum
+
+	doc, err = decoder.decodeDocument()
+	if err != nil {
+		return nil, &BinaryError{Err: err, Offset: decoder.offset()}
+	}
+
+	if err := checksum.verifyTrailer(); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
 }
 
 // decodeDocument decodes a full binary document.
@@ -77,37 +154,64 @@ func (d *binaryDecoder) decodeDocument() (*Document, error) {
 			return doc, nil
 		}
 
-		node, err := d.decodeEntry(typeByte, 1)
+		node, err := d.decodeEntry(typeByte, 1, doc.Roots, false)
 		if err != nil {
 			return nil, err
 		}
 
-		if d.opts.Strict && containsKey(doc.Roots, node.Key) {
-			return nil, fmt.Errorf("%w: root key %q", ErrDuplicateKeyInStrictMode, node.Key)
+		if node == nil {
+			continue
 		}
 
-		doc.AddRoot(node)
+		var ok bool
+		if doc.Roots, ok = addDecodedNode(doc.Roots, node, d.opts.DuplicatePolicy); !ok {
+			return nil, fmt.Errorf("%w: root key %q", ErrDuplicateKeyInStrictMode, node.Key)
+		}
 	}
 }
 
 // decodeEntry decodes one key/value entry based on its type byte.
-func (d *binaryDecoder) decodeEntry(typeByte byte, depth int) (*Node, error) {
+// It returns a nil node when the entry is consumed but dropped by NodeFilter
+// or DuplicatePolicy; siblings are the already decoded entries of the enclosing scope.
+func (d *binaryDecoder) decodeEntry(typeByte byte, depth int, siblings []*Node, skip bool) (*Node, error) {
 	if err := d.checkDepth(depth); err != nil {
 		return nil, err
 	}
 
-	key, err := d.readNullTerminatedString()
+	keyStart := d.offset()
+	key, err := d.readKey()
 	if err != nil {
 		return nil, err
 	}
 
+	valueStart := d.offset()
+	keyEnd := valueStart
+	if d.opts.StringPool == nil {
+		keyEnd-- // exclude the null terminator
+	}
+
+	key = mapDecodedKey(d.opts, d.path, key)
+	skip = skip || skipDuplicate(siblings, key, d.opts.DuplicatePolicy)
+
+	// Descendants of a filtered-out object are dropped without consulting the filter.
+	if kind := d.binaryNodeKind(typeByte); !skip && kind != 0 && d.opts.NodeFilter != nil {
+		skip = !d.opts.NodeFilter(d.path, key, kind)
+	}
+
 	switch typeByte {
 	case binaryTypeMapStart:
-		node := NewObjectNode(key)
+		var node *Node
+		if !skip {
+			node = NewObjectNode(key)
+		}
+
 		if err := d.incrementNodeCount(); err != nil {
 			return nil, err
 		}
 
+		d.path = append(d.path, key)
+		defer func() { d.path = d.path[:len(d.path)-1] }()
+
 		for {
 			childType, err := d.readTypeByte()
 			if err != nil {
@@ -120,20 +224,29 @@ func (d *binaryDecoder) decodeEntry(typeByte byte, depth int) (*Node, error) {
 
 			if childType == binaryTypeMapEnd {
 				// End marker closes only the current nested object scope.
+				d.setSpan(node, keyStart, keyEnd, valueStart, d.offset())
 				return node, nil
 			}
 
 			// Recursively decode each nested entry until map end is reached.
-			child, err := d.decodeEntry(childType, depth+1)
+			var children []*Node
+			if node != nil {
+				children = node.Children
+			}
+
+			child, err := d.decodeEntry(childType, depth+1, children, skip)
 			if err != nil {
 				return nil, err
 			}
 
-			if d.opts.Strict && containsKey(node.Children, child.Key) {
-				return nil, fmt.Errorf("%w: key %q in object %q", ErrDuplicateKeyInStrictMode, child.Key, key)
+			if child == nil {
+				continue
 			}
 
-			node.Add(child)
+			var ok bool
+			if node.Children, ok = addDecodedNode(node.Children, child, d.opts.DuplicatePolicy); !ok {
+				return nil, fmt.Errorf("%w: key %q in object %q", ErrDuplicateKeyInStrictMode, child.Key, key)
+			}
 		}
 	case binaryTypeString:
 		value, err := d.readNullTerminatedString()
@@ -141,27 +254,241 @@ func (d *binaryDecoder) decodeEntry(typeByte byte, depth int) (*Node, error) {
 			return nil, err
 		}
 
+		if err := d.incrementNodeCount(); err != nil {
+			return nil, err
+		}
+
+		if skip {
+			return nil, nil
+		}
+
 		node := NewStringNode(key, value)
+		d.setSpan(node, keyStart, keyEnd, valueStart, d.offset()-1)
+		return node, nil
+	case binaryTypeWideString:
+		value, err := d.readWideString()
+		if err != nil {
+			return nil, err
+		}
+
 		if err := d.incrementNodeCount(); err != nil {
 			return nil, err
 		}
 
+		if skip {
+			return nil, nil
+		}
+
+		node := NewWideStringNode(key, value)
+		d.setSpan(node, keyStart, keyEnd, valueStart, d.offset()-2)
 		return node, nil
-	case binaryTypeNumber:
+	case binaryTypeNumber, binaryTypePointer:
 		value, err := d.readUint32()
 		if err != nil {
 			return nil, err
 		}
 
+		if err := d.incrementNodeCount(); err != nil {
+			return nil, err
+		}
+
+		if skip {
+			return nil, nil
+		}
+
 		node := NewUint32Node(key, value)
+		if typeByte == binaryTypePointer {
+			node.Kind = NodePointer
+		}
+
+		d.setSpan(node, keyStart, keyEnd, valueStart, d.offset())
+		return node, nil
+	case binaryTypeFloat32:
+		value, err := d.readUint32()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := d.incrementNodeCount(); err != nil {
+			return nil, err
+		}
+
+		if skip {
+			return nil, nil
+		}
+
+		node := NewFloat32Node(key, math.Float32frombits(value))
+		d.setSpan(node, keyStart, keyEnd, valueStart, d.offset())
+		return node, nil
+	case binaryTypeUint64, binaryTypeInt64:
+		value, err := d.readUint64()
+		if err != nil {
+			return nil, err
+		}
+
 		if err := d.incrementNodeCount(); err != nil {
 			return nil, err
 		}
 
+		if skip {
+			return nil, nil
+		}
+
+		node := NewUint64Node(key, value)
+		if typeByte == binaryTypeInt64 {
+			node = NewInt64Node(key, int64(value))
+		}
+
+		d.setSpan(node, keyStart, keyEnd, valueStart, d.offset())
+		return node, nil
+	case binaryTypeColor:
+		value, err := d.readColor()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := d.incrementNodeCount(); err != nil {
+			return nil, err
+		}
+
+		if skip {
+			return nil, nil
+		}
+
+		node := NewColorNode(key, value)
+		d.setSpan(node, keyStart, keyEnd, valueStart, d.offset())
 		return node, nil
 	default:
-		return nil, fmt.Errorf("%w: 0x%02x", ErrUnrecognizedType, typeByte)
+		if !d.opts.PreserveUnknownTypes {
+			return nil, fmt.Errorf("%w: 0x%02x", ErrUnrecognizedType, typeByte)
+		}
+
+		payload, err := d.readRawPayload(typeByte)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := d.incrementNodeCount(); err != nil {
+			return nil, err
+		}
+
+		if skip {
+			return nil, nil
+		}
+
+		node := NewRawNode(key, typeByte, payload)
+		d.setSpan(node, keyStart, keyEnd, valueStart, d.offset())
+		return node, nil
+	}
+}
+
+// binaryNodeKind maps a binary type marker to the node kind it produces.
+func (d *binaryDecoder) binaryNodeKind(typeByte byte) NodeKind {
+	switch typeByte {
+	case binaryTypeMapStart:
+		return NodeObject
+	case binaryTypeString:
+		return NodeString
+	case binaryTypeNumber:
+		return NodeUint32
+	case binaryTypeFloat32:
+		return NodeFloat32
+	case binaryTypePointer:
+		return NodePointer
+	case binaryTypeWideString:
+		return NodeWideString
+	case binaryTypeColor:
+		return NodeColor
+	case binaryTypeUint64:
+		return NodeUint64
+	case binaryTypeInt64:
+		return NodeInt64
+	default:
+		if d.opts.PreserveUnknownTypes && d.rawPayloadSize(typeByte) != 0 {
+			return NodeRaw
+		}
+
+		return 0
+	}
+}
+
+// rawPayloadSize returns the payload size for a preserved raw type byte.
+// It returns 0 when the size is unknown.
+func (d *binaryDecoder) rawPayloadSize(typeByte byte) int {
+	if size, ok := d.opts.RawTypeSizes[typeByte]; ok && size > 0 {
+		return size
+	}
+
+	return builtinRawTypeSizes[typeByte]
+}
+
+// readRawPayload reads the verbatim payload of a preserved raw entry.
+func (d *binaryDecoder) readRawPayload(typeByte byte) ([]byte, error) {
+	size := d.rawPayloadSize(typeByte)
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: 0x%02x has no known payload size", ErrUnrecognizedType, typeByte)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(d.reader, payload); err != nil {
+		return nil, ErrBufferOverflow
+	}
+
+	return payload, nil
+}
+
+// offset returns the number of input bytes consumed, or 0 without a byte counter.
+func (d *binaryDecoder) offset() int64 {
+	if d.counter == nil {
+		return 0
+	}
+
+	return d.counter.n
+}
+
+// setSpan stores source byte ranges and the key position on node when they are recorded.
+func (d *binaryDecoder) setSpan(node *Node, keyStart, keyEnd, valueStart, valueEnd int64) {
+	if d.counter == nil || node == nil {
+		return
+	}
+
+	if d.opts.TrackPositions {
+		node.Pos = &Pos{Offset: keyStart}
+	}
+
+	if !d.opts.RecordSpans {
+		return
+	}
+
+	node.Span = &Span{
+		KeyStart:   keyStart,
+		KeyEnd:     keyEnd,
+		ValueStart: valueStart,
+		ValueEnd:   valueEnd,
+	}
+}
+
+// countingReader counts bytes read through a binary reader.
+type countingReader struct {
+	r binaryReadReader // Underlying reader.
+	n int64            // Bytes consumed so far.
+}
+
+// Read reads from the underlying reader and counts consumed bytes.
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// ReadByte reads one byte and counts it.
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
 	}
+
+	return b, err
 }
 
 // readTypeByte reads one binary type marker byte.
@@ -174,8 +501,15 @@ func (d *binaryDecoder) readTypeByte() (byte, error) {
 	return b, nil
 }
 
-// readNullTerminatedString reads one null-terminated string.
+// readNullTerminatedString reads one null-terminated string value,
+// limited by DecodeOptions.MaxStringBytes.
 func (d *binaryDecoder) readNullTerminatedString() (string, error) {
+	return d.readCString(d.opts.MaxStringBytes, "string")
+}
+
+// readCString reads one null-terminated string of at most limit bytes (0 means unlimited);
+// what names the string in limit errors.
+func (d *binaryDecoder) readCString(limit int, what string) (string, error) {
 	bufPtr := binaryStringBufferPool.Get().(*[]byte)
 	buf := (*bufPtr)[:0]
 	defer func() {
@@ -201,10 +535,59 @@ func (d *binaryDecoder) readNullTerminatedString() (string, error) {
 			return string(buf), nil
 		}
 
+		if limit > 0 && len(buf) >= limit {
+			return "", fmt.Errorf("%w: %s exceeds %d bytes", ErrStringLimitExceeded, what, limit)
+		}
+
 		buf = append(buf, b)
 	}
 }
 
+// readKey reads one entry key, resolving it through the string pool when one is set.
+func (d *binaryDecoder) readKey() (string, error) {
+	if d.opts.StringPool == nil {
+		return d.readCString(d.opts.MaxKeyBytes, "key")
+	}
+
+	index, err := d.readUint32()
+	if err != nil {
+		return "", err
+	}
+
+	if uint64(index) >= uint64(len(d.opts.StringPool)) {
+		return "", fmt.Errorf("%w: %d of %d", ErrStringPoolIndex, index, len(d.opts.StringPool))
+	}
+
+	return d.opts.StringPool[index], nil
+}
+
+// readWideString reads one UTF-16LE string terminated by a zero code unit
+// and transcodes it to UTF-8. Unpaired surrogates become U+FFFD.
+func (d *binaryDecoder) readWideString() (string, error) {
+	units := make([]uint16, 0, 16)
+	for {
+		var raw [2]byte
+		if _, err := io.ReadFull(d.reader, raw[:]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return "", ErrBufferOverflow
+			}
+
+			return "", err
+		}
+
+		unit := binary.LittleEndian.Uint16(raw[:])
+		if unit == 0 {
+			return string(utf16.Decode(units)), nil
+		}
+
+		if limit := d.opts.MaxStringBytes; limit > 0 && 2*len(units) >= limit {
+			return "", fmt.Errorf("%w: wide string exceeds %d bytes", ErrStringLimitExceeded, limit)
+		}
+
+		units = append(units, unit)
+	}
+}
+
 // readUint32 reads little-endian uint32.
 func (d *binaryDecoder) readUint32() (uint32, error) {
 	var raw [4]byte
@@ -219,6 +602,34 @@ func (d *binaryDecoder) readUint32() (uint32, error) {
 	return binary.LittleEndian.Uint32(raw[:]), nil
 }
 
+// readUint64 reads little-endian uint64.
+func (d *binaryDecoder) readUint64() (uint64, error) {
+	var raw [8]byte
+	if _, err := io.ReadFull(d.reader, raw[:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, ErrBufferOverflow
+		}
+
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint64(raw[:]), nil
+}
+
+// readColor reads one RGBA color payload.
+func (d *binaryDecoder) readColor() (Color, error) {
+	var raw [4]byte
+	if _, err := io.ReadFull(d.reader, raw[:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return Color{}, ErrBufferOverflow
+		}
+
+		return Color{}, err
+	}
+
+	return Color{R: raw[0], G: raw[1], B: raw[2], A: raw[3]}, nil
+}
+
 // checkDepth validates configured maximum nesting depth.
 func (d *binaryDecoder) checkDepth(depth int) error {
 	if d.opts.MaxDepth > 0 && depth > d.opts.MaxDepth {
@@ -244,6 +655,10 @@ func looksBinaryPrefix(data []byte) bool {
 		return false
 	}
 
+	if bytes.HasPrefix(data, []byte(vbkvMagic)) {
+		return true
+	}
+
 	first := data[0]
 	if first != binaryTypeMapStart && first != binaryTypeString && first != binaryTypeNumber {
 		return false
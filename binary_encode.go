@@ -8,7 +8,9 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"strings"
+	"unicode/utf16"
 )
 
 // binaryZeroByte is a zero byte.
@@ -21,7 +23,21 @@ type byteWriter interface {
 
 // encodeBinaryDocument writes document in binary VDF format.
 func encodeBinaryDocument(w io.Writer, doc *Document, opts EncodeOptions) error {
-	roots := orderedNodes(doc.Roots, opts.Deterministic)
+	if opts.VBKV {
+		return encodeVBKVDocument(w, doc, opts)
+	}
+
+	if opts.Checksum {
+		checksum := &checksumWriter{w: w}
+		opts.Checksum = false
+		if err := encodeBinaryDocument(checksum, doc, opts); err != nil {
+			return err
+		}
+
+		return checksum.writeTrailer()
+	}
+
+	roots := orderedNodes(doc.Roots, opts.Deterministic, opts.Collation)
 	for _, root := range roots {
 		if err := encodeBinaryNode(w, root, opts); err != nil {
 			return err
@@ -35,30 +51,65 @@ func encodeBinaryDocument(w io.Writer, doc *Document, opts EncodeOptions) error
 	return nil
 }
 
-// encodeBinaryNode writes a single AST node as binary entry.
+// encodeBinaryNode writes a single AST node subtree as binary entries.
+// Traversal uses an explicit stack, so nesting depth is not limited by the goroutine stack,
+// and cyclic object graphs fail with ErrInvalidNodeState instead of looping forever.
 func encodeBinaryNode(w io.Writer, node *Node, opts EncodeOptions) error {
-	switch node.Kind {
-	case NodeObject:
-		if err := writeBinaryByte(w, binaryTypeMapStart); err != nil {
-			return err
-		}
+	if err := writeBinaryNodeOpen(w, node); err != nil {
+		return err
+	}
 
-		if err := writeNullTerminatedString(w, node.Key); err != nil {
-			return err
-		}
+	if node.Kind != NodeObject {
+		return nil
+	}
+
+	stack := newEncodeStack(opts.Deterministic, opts.DeterministicDepth, opts.Collation)
+	if err := stack.push(node); err != nil {
+		return err
+	}
+
+	for stack.depth() > 0 {
+		top := stack.top()
 
-		children := orderedNodes(node.Children, opts.Deterministic)
-		for _, child := range children {
-			if err := encodeBinaryNode(w, child, opts); err != nil {
+		if top.index < len(top.children) {
+			child := top.children[top.index]
+			top.index++
+
+			if err := writeBinaryNodeOpen(w, child); err != nil {
 				return err
 			}
+
+			if child.Kind == NodeObject {
+				if err := stack.push(child); err != nil {
+					return err
+				}
+			}
+
+			continue
 		}
 
+		stack.pop()
 		if err := writeBinaryByte(w, binaryTypeMapEnd); err != nil {
 			return err
 		}
+	}
 
-		return nil
+	return nil
+}
+
+// writeBinaryNodeOpen writes a full leaf entry or an object header for one node.
+func writeBinaryNodeOpen(w io.Writer, node *Node) error {
+	if node == nil {
+		return fmt.Errorf("%w: nil node", ErrInvalidNodeState)
+	}
+
+	switch node.Kind {
+	case NodeObject:
+		if err := writeBinaryByte(w, binaryTypeMapStart); err != nil {
+			return err
+		}
+
+		return writeNullTerminatedString(w, node.Key)
 	case NodeString:
 		if err := writeBinaryByte(w, binaryTypeString); err != nil {
 			return err
@@ -73,8 +124,27 @@ func encodeBinaryNode(w io.Writer, node *Node, opts EncodeOptions) error {
 		}
 
 		return writeNullTerminatedString(w, *node.StringValue)
-	case NodeUint32:
-		if err := writeBinaryByte(w, binaryTypeNumber); err != nil {
+	case NodeWideString:
+		if err := writeBinaryByte(w, binaryTypeWideString); err != nil {
+			return err
+		}
+
+		if err := writeNullTerminatedString(w, node.Key); err != nil {
+			return err
+		}
+
+		if node.StringValue == nil {
+			return fmt.Errorf("%w: nil string value for key %q", ErrInvalidNodeState, node.Key)
+		}
+
+		return writeWideString(w, *node.StringValue)
+	case NodeUint32, NodePointer:
+		typeByte := binaryTypeNumber
+		if node.Kind == NodePointer {
+			typeByte = binaryTypePointer
+		}
+
+		if err := writeBinaryByte(w, typeByte); err != nil {
 			return err
 		}
 
@@ -90,6 +160,77 @@ func encodeBinaryNode(w io.Writer, node *Node, opts EncodeOptions) error {
 		binary.LittleEndian.PutUint32(raw[:], *node.Uint32Value)
 		_, err := w.Write(raw[:])
 		return err
+	case NodeFloat32:
+		if err := writeBinaryByte(w, binaryTypeFloat32); err != nil {
+			return err
+		}
+
+		if err := writeNullTerminatedString(w, node.Key); err != nil {
+			return err
+		}
+
+		if node.Float32Value == nil {
+			return fmt.Errorf("%w: nil float32 value for key %q", ErrInvalidNodeState, node.Key)
+		}
+
+		var raw [4]byte
+		binary.LittleEndian.PutUint32(raw[:], math.Float32bits(*node.Float32Value))
+		_, err := w.Write(raw[:])
+		return err
+	case NodeUint64:
+		if err := writeBinaryByte(w, binaryTypeUint64); err != nil {
+			return err
+		}
+
+		if err := writeNullTerminatedString(w, node.Key); err != nil {
+			return err
+		}
+
+		if node.Uint64Value == nil {
+			return fmt.Errorf("%w: nil uint64 value for key %q", ErrInvalidNodeState, node.Key)
+		}
+
+		var raw [8]byte
+		binary.LittleEndian.PutUint64(raw[:], *node.Uint64Value)
+		_, err := w.Write(raw[:])
+		return err
+	case NodeInt64:
+		if err := writeBinaryByte(w, binaryTypeInt64); err != nil {
+			return err
+		}
+
+		if err := writeNullTerminatedString(w, node.Key); err != nil {
+			return err
+		}
+
+		if node.Int64Value == nil {
+			return fmt.Errorf("%w: nil int64 value for key %q", ErrInvalidNodeState, node.Key)
+		}
+
+		var raw [8]byte
+		binary.LittleEndian.PutUint64(raw[:], uint64(*node.Int64Value))
+		_, err := w.Write(raw[:])
+		return err
+	case NodeColor:
+		if err := writeBinaryByte(w, binaryTypeColor); err != nil {
+			return err
+		}
+
+		if err := writeNullTerminatedString(w, node.Key); err != nil {
+			return err
+		}
+
+		if node.ColorValue == nil {
+			return fmt.Errorf("%w: nil color value for key %q", ErrInvalidNodeState, node.Key)
+		}
+
+		c := node.ColorValue
+		_, err := w.Write([]byte{c.R, c.G, c.B, c.A})
+		return err
+	case NodeRaw:
+		return writeBinaryRaw(w, node.Key, node.RawType, node.RawValue)
+	case NodeDirective:
+		return fmt.Errorf("%w: directive %q cannot be encoded as binary", ErrInvalidFormat, node.Key)
 	default:
 		return fmt.Errorf("%w: unknown node kind %d", ErrInvalidNodeState, node.Kind)
 	}
@@ -124,23 +265,75 @@ func writeNullTerminatedString(w io.Writer, value string) error {
 	return nil
 }
 
+// writeWideString writes one UTF-16LE string terminated by a zero code unit.
+func writeWideString(w io.Writer, value string) error {
+	if strings.IndexByte(value, 0) >= 0 {
+		return ErrNullInString
+	}
+
+	units := utf16.Encode([]rune(value))
+	raw := make([]byte, 0, 2*len(units)+2)
+	for _, unit := range units {
+		raw = binary.LittleEndian.AppendUint16(raw, unit)
+	}
+
+	raw = append(raw, 0, 0)
+	_, err := w.Write(raw)
+	return err
+}
+
 // estimateBinaryDocumentSize returns an approximate encoded byte size.
-func estimateBinaryDocumentSize(doc *Document, deterministic bool) int {
+func estimateBinaryDocumentSize(doc *Document) int {
 	if doc == nil {
 		return 0
 	}
 
-	size := 1 // trailing root map-end byte
-	roots := orderedNodes(doc.Roots, deterministic)
-	for _, root := range roots {
-		size += estimateBinaryNodeSize(root, deterministic)
+	size := 1 + checksumSize // trailing root map-end byte and optional checksum
+	for _, root := range doc.Roots {
+		size += estimateBinaryNodeSize(root)
 	}
 
 	return size
 }
 
-// estimateBinaryNodeSize returns encoded byte size for one AST node.
-func estimateBinaryNodeSize(node *Node, deterministic bool) int {
+// estimateBinaryNodeSize returns encoded byte size for one AST node subtree.
+// Estimation stops descending into cyclic paths; the encoder reports those as errors.
+func estimateBinaryNodeSize(node *Node) int {
+	size := estimateBinaryEntrySize(node)
+	if node == nil || node.Kind != NodeObject {
+		return size
+	}
+
+	stack := newEncodeStack(false, 0, 0)
+	if err := stack.push(node); err != nil {
+		return size
+	}
+
+	for stack.depth() > 0 {
+		top := stack.top()
+
+		if top.index < len(top.children) {
+			child := top.children[top.index]
+			top.index++
+
+			size += estimateBinaryEntrySize(child)
+			if child != nil && child.Kind == NodeObject {
+				if err := stack.push(child); err != nil {
+					return size
+				}
+			}
+
+			continue
+		}
+
+		stack.pop()
+	}
+
+	return size
+}
+
+// estimateBinaryEntrySize returns encoded byte size for one entry excluding object children.
+func estimateBinaryEntrySize(node *Node) int {
 	if node == nil {
 		return 0
 	}
@@ -149,20 +342,51 @@ func estimateBinaryNodeSize(node *Node, deterministic bool) int {
 
 	switch node.Kind {
 	case NodeObject:
-		children := orderedNodes(node.Children, deterministic)
-		for _, child := range children {
-			size += estimateBinaryNodeSize(child, deterministic)
-		}
-
 		size++ // object end byte
 	case NodeString:
 		if node.StringValue != nil {
 			size += len(*node.StringValue) + 1
 		}
 
-	case NodeUint32:
+	case NodeWideString:
+		if node.StringValue != nil {
+			for _, r := range *node.StringValue {
+				size += 2 * utf16.RuneLen(r)
+			}
+
+			size += 2
+		}
+
+	case NodeUint32, NodeFloat32, NodePointer, NodeColor:
 		size += 4
+	case NodeUint64, NodeInt64:
+		size += 8
+	case NodeRaw:
+		size += len(node.RawValue)
 	}
 
 	return size
 }
+
+// writeBinaryRaw writes a preserved raw entry verbatim after checking that its
+// type byte does not collide with a built-in type, which would corrupt the stream.
+func writeBinaryRaw(w io.Writer, key string, rawType byte, payload []byte) error {
+	if payload == nil {
+		return fmt.Errorf("%w: raw node %q missing payload", ErrInvalidNodeState, key)
+	}
+
+	if isBuiltinBinaryType(rawType) {
+		return fmt.Errorf("%w: raw node %q uses built-in type byte 0x%02x", ErrInvalidNodeState, key, rawType)
+	}
+
+	if err := writeBinaryByte(w, rawType); err != nil {
+		return err
+	}
+
+	if err := writeNullTerminatedString(w, key); err != nil {
+		return err
+	}
+
+	_, err := w.Write(payload)
+	return err
+}
@@ -2,7 +2,13 @@ package vdf
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 )
 
@@ -135,3 +141,830 @@ func TestManualBinaryEncoder(t *testing.T) {
 		t.Fatalf("Validate() returned error: %v", err)
 	}
 }
+
+func TestBinaryChecksumTrailer(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"root" { "name" "srv" "sub" { "k" "v" } }`)
+
+	plain, err := AppendBinary(nil, doc, EncodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	data, err := AppendBinary(nil, doc, EncodeOptions{Format: FormatBinary, Checksum: true})
+	if err != nil {
+		t.Fatalf("AppendBinary(checksum) returned error: %v", err)
+	}
+
+	if len(data) != len(plain)+4 || !bytes.Equal(data[:len(plain)], plain) {
+		t.Fatalf("checksum output must be payload plus 4-byte trailer")
+	}
+
+	if got, want := binary.LittleEndian.Uint32(data[len(plain):]), crc32.ChecksumIEEE(plain); got != want {
+		t.Fatalf("trailer = 0x%08x, want 0x%08x", got, want)
+	}
+
+	if _, err := ParseBytes(data, DecodeOptions{Format: FormatBinary, VerifyChecksum: true}); err != nil {
+		t.Fatalf("ParseBytes(verify) returned error: %v", err)
+	}
+
+	corrupted := bytes.Clone(data)
+	corrupted[8] ^= 0xFF
+	if _, err := ParseBytes(corrupted, DecodeOptions{Format: FormatBinary, VerifyChecksum: true}); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("ParseBytes(corrupted) error = %v, want ErrChecksumMismatch", err)
+	}
+
+	if _, err := ParseBytes(plain, DecodeOptions{Format: FormatBinary, VerifyChecksum: true}); !errors.Is(err, ErrBufferOverflow) {
+		t.Fatalf("ParseBytes(no trailer) error = %v, want ErrBufferOverflow", err)
+	}
+
+	var manual bytes.Buffer
+	enc := NewEncoder(&manual, EncodeOptions{Format: FormatBinary, Checksum: true})
+	if err := enc.StartObject("root"); err != nil {
+		t.Fatalf("StartObject() returned error: %v", err)
+	}
+	if err := enc.WriteString("name", "srv"); err != nil {
+		t.Fatalf("WriteString() returned error: %v", err)
+	}
+	if err := enc.StartObject("sub"); err != nil {
+		t.Fatalf("StartObject() returned error: %v", err)
+	}
+	if err := enc.WriteString("k", "v"); err != nil {
+		t.Fatalf("WriteString() returned error: %v", err)
+	}
+	if err := enc.EndObject(); err != nil {
+		t.Fatalf("EndObject() returned error: %v", err)
+	}
+	if err := enc.EndObject(); err != nil {
+		t.Fatalf("EndObject() returned error: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	if !bytes.Equal(manual.Bytes(), data) {
+		t.Fatalf("manual checksum output mismatch:\n got %x\nwant %x", manual.Bytes(), data)
+	}
+}
+
+func TestBinaryPreserveUnknownTypes(t *testing.T) {
+	t.Parallel()
+
+	var input []byte
+	input = append(input, binaryTypeMapStart)
+	input = append(input, "root\x00"...)
+	input = append(input, 0x03)
+	input = append(input, "scale\x00"...)
+	input = append(input, 0x00, 0x00, 0x80, 0x3F)
+	input = append(input, 0x06)
+	input = append(input, "color\x00"...)
+	input = append(input, 0xFF, 0x80, 0x00, 0xFF)
+	input = append(input, 0x42)
+	input = append(input, "vendor\x00"...)
+	input = append(input, 0x01, 0x02)
+	input = append(input, binaryTypeString)
+	input = append(input, "name\x00value\x00"...)
+	input = append(input, binaryTypeMapEnd, binaryTypeMapEnd)
+
+	if _, err := ParseBytes(input, DecodeOptions{Format: FormatBinary}); !errors.Is(err, ErrUnrecognizedType) {
+		t.Fatalf("ParseBytes() without PreserveUnknownTypes error=%v, want ErrUnrecognizedType", err)
+	}
+
+	opts := DecodeOptions{
+		Format:               FormatBinary,
+		PreserveUnknownTypes: true,
+		RawTypeSizes:         map[byte]int{0x42: 2},
+	}
+
+	doc, err := ParseBytes(input, opts)
+	if err != nil {
+		t.Fatalf("ParseBytes() returned error: %v", err)
+	}
+
+	vendor := doc.Roots[0].First("vendor")
+	if vendor == nil || vendor.Kind != NodeRaw || vendor.RawType != 0x42 || !bytes.Equal(vendor.RawValue, []byte{0x01, 0x02}) {
+		t.Fatalf("unexpected vendor raw node: %+v", vendor)
+	}
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("Validate() returned error: %v", err)
+	}
+
+	encoded, err := AppendBinary(nil, doc, EncodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	if !bytes.Equal(encoded, input) {
+		t.Fatalf("raw round-trip mismatch:\n got %x\nwant %x", encoded, input)
+	}
+
+	if _, err := AppendText(nil, doc, EncodeOptions{}); !errors.Is(err, ErrInvalidNodeState) {
+		t.Fatalf("AppendText() error=%v, want ErrInvalidNodeState", err)
+	}
+}
+
+func TestUpsertBinaryEntry(t *testing.T) {
+	t.Parallel()
+
+	shortcut := func(key, name string, appid uint32) *Node {
+		entry := NewObjectNode(key)
+		entry.Add(NewUint32Node("appid", appid))
+		entry.Add(NewStringNode("AppName", name))
+		return entry
+	}
+
+	doc := NewDocumentWithFormat(FormatBinary)
+	root := NewObjectNode("shortcuts")
+	root.Add(shortcut("0", "First", 1))
+	root.Add(shortcut("1", "Second", 2))
+	doc.AddRoot(root)
+
+	path := filepath.Join(t.TempDir(), "shortcuts.vdf")
+	if err := WriteBinaryFile(path, doc); err != nil {
+		t.Fatalf("WriteBinaryFile() returned error: %v", err)
+	}
+
+	if err := UpsertBinaryEntry(path, "shortcuts", shortcut("2", "Third", 3)); err != nil {
+		t.Fatalf("UpsertBinaryEntry(append) returned error: %v", err)
+	}
+
+	if err := UpsertBinaryEntry(path, "shortcuts", shortcut("0", "First Renamed", 10)); err != nil {
+		t.Fatalf("UpsertBinaryEntry(replace) returned error: %v", err)
+	}
+
+	root.Children[0] = shortcut("0", "First Renamed", 10)
+	root.Add(shortcut("2", "Third", 3))
+	want, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() returned error: %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Fatalf("upserted file mismatch:\n got %x\nwant %x", got, want)
+	}
+
+	if err := UpsertBinaryEntry(path, "missing", shortcut("0", "x", 1)); !errors.Is(err, ErrPathNotFound) {
+		t.Fatalf("UpsertBinaryEntry(missing parent) error=%v, want ErrPathNotFound", err)
+	}
+
+	wrapped, err := AppendBinary(nil, doc, EncodeOptions{VBKV: true})
+	if err != nil {
+		t.Fatalf("AppendBinary(VBKV) returned error: %v", err)
+	}
+
+	if err := os.WriteFile(path, wrapped, 0o600); err != nil {
+		t.Fatalf("WriteFile() returned error: %v", err)
+	}
+
+	if err := UpsertBinaryEntry(path, "shortcuts", shortcut("3", "Fourth", 4)); err != nil {
+		t.Fatalf("UpsertBinaryEntry(VBKV) returned error: %v", err)
+	}
+
+	root.Add(shortcut("3", "Fourth", 4))
+	if want, err = AppendBinary(nil, doc, EncodeOptions{VBKV: true}); err != nil {
+		t.Fatalf("AppendBinary(VBKV) returned error: %v", err)
+	}
+
+	if got, err = os.ReadFile(path); err != nil || !bytes.Equal(got, want) {
+		t.Fatalf("upserted VBKV file = %x, %v, want %x", got, err, want)
+	}
+
+	if _, err := ParseFile(path, DecodeOptions{Format: FormatBinary}); err != nil {
+		t.Fatalf("ParseFile(upserted VBKV) returned error: %v", err)
+	}
+}
+
+func TestBinaryFloat32(t *testing.T) {
+	t.Parallel()
+
+	doc := NewDocumentWithFormat(FormatBinary)
+	root := NewObjectNode("scheme")
+	root.Add(NewFloat32Node("scale", 1.25))
+	root.Add(NewFloat32Node("alpha", -0.1))
+	doc.AddRoot(root)
+
+	data, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	if want := estimateBinaryDocumentSize(doc) - checksumSize; len(data) != want {
+		t.Fatalf("encoded size = %d, want estimate %d", len(data), want)
+	}
+
+	decoded, err := ParseBytes(data, DecodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("ParseBytes() returned error: %v", err)
+	}
+
+	scale := decoded.Roots[0].First("scale")
+	if scale.Kind != NodeFloat32 || *scale.Float32Value != 1.25 {
+		t.Fatalf("scale = %+v, want float32 1.25", scale)
+	}
+
+	text, err := AppendText(nil, decoded, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	if want := `"scheme" { "scale" "1.25" "alpha" "-0.1" } `; string(text) != want {
+		t.Fatalf("AppendText() = %q, want %q", text, want)
+	}
+
+	var manual bytes.Buffer
+	enc := NewEncoder(&manual, EncodeOptions{Format: FormatBinary})
+	if err := enc.StartObject("scheme"); err != nil {
+		t.Fatalf("StartObject() returned error: %v", err)
+	}
+
+	if err := enc.WriteFloat32("scale", 1.25); err != nil {
+		t.Fatalf("WriteFloat32() returned error: %v", err)
+	}
+
+	if err := enc.WriteFloat32("alpha", -0.1); err != nil {
+		t.Fatalf("WriteFloat32() returned error: %v", err)
+	}
+
+	if err := enc.EndObject(); err != nil {
+		t.Fatalf("EndObject() returned error: %v", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	if !bytes.Equal(manual.Bytes(), data) {
+		t.Fatalf("manual encoding mismatch:\n got %x\nwant %x", manual.Bytes(), data)
+	}
+
+	var filtered bytes.Buffer
+	if err := Filter(&filtered, bytes.NewReader(data), func(path []string) bool {
+		return path[len(path)-1] == "alpha"
+	}, DecodeOptions{}); err != nil {
+		t.Fatalf("Filter() returned error: %v", err)
+	}
+
+	kept, err := ParseBytes(filtered.Bytes(), DecodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("ParseBytes(filtered) returned error: %v", err)
+	}
+
+	if alpha := kept.Roots[0].First("alpha"); alpha == nil || alpha.Kind != NodeFloat32 || *alpha.Float32Value != -0.1 {
+		t.Fatalf("filtered alpha = %+v", alpha)
+	}
+}
+
+func TestBinaryPointer(t *testing.T) {
+	t.Parallel()
+
+	data := []byte{
+		binaryTypeMapStart, 'd', 'u', 'm', 'p', 0,
+		binaryTypePointer, 'p', 't', 'r', 0, 0x78, 0x56, 0x34, 0x12,
+		binaryTypeMapEnd,
+		binaryTypeMapEnd,
+	}
+
+	doc, err := ParseBytes(data, DecodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("ParseBytes() returned error: %v", err)
+	}
+
+	ptr := doc.Roots[0].First("ptr")
+	if ptr == nil || ptr.Kind != NodePointer || *ptr.Uint32Value != 0x12345678 {
+		t.Fatalf("ptr = %+v, want pointer 0x12345678", ptr)
+	}
+
+	encoded, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	if !bytes.Equal(encoded, data) {
+		t.Fatalf("round-trip mismatch:\n got %x\nwant %x", encoded, data)
+	}
+
+	var manual bytes.Buffer
+	enc := NewEncoder(&manual, EncodeOptions{Format: FormatBinary})
+	if err := enc.StartObject("dump"); err != nil {
+		t.Fatalf("StartObject() returned error: %v", err)
+	}
+
+	if err := enc.WritePointer("ptr", 0x12345678); err != nil {
+		t.Fatalf("WritePointer() returned error: %v", err)
+	}
+
+	if err := enc.EndObject(); err != nil {
+		t.Fatalf("EndObject() returned error: %v", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	if !bytes.Equal(manual.Bytes(), data) {
+		t.Fatalf("manual encoding mismatch:\n got %x\nwant %x", manual.Bytes(), data)
+	}
+
+	var filtered bytes.Buffer
+	if err := Filter(&filtered, bytes.NewReader(data), func([]string) bool { return true }, DecodeOptions{}); err != nil {
+		t.Fatalf("Filter() returned error: %v", err)
+	}
+
+	if !bytes.Equal(filtered.Bytes(), data) {
+		t.Fatalf("Filter() output mismatch:\n got %x\nwant %x", filtered.Bytes(), data)
+	}
+
+	text, err := AppendText(nil, doc, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	if want := `"dump" { "ptr" "305419896" } `; string(text) != want {
+		t.Fatalf("AppendText() = %q, want %q", text, want)
+	}
+}
+
+func TestBinaryWideString(t *testing.T) {
+	t.Parallel()
+
+	data := []byte{
+		binaryTypeMapStart, 'a', 'p', 'p', 0,
+		binaryTypeWideString, 'n', 'a', 'm', 'e', 0,
+		'H', 0x00, 0xE9, 0x00, 0x3D, 0xD8, 0x00, 0xDE, 0x00, 0x00,
+		binaryTypeMapEnd,
+		binaryTypeMapEnd,
+	}
+
+	doc, err := ParseBytes(data, DecodeOptions{Format: FormatBinary, RecordSpans: true})
+	if err != nil {
+		t.Fatalf("ParseBytes() returned error: %v", err)
+	}
+
+	name := doc.Roots[0].First("name")
+	if name == nil || name.Kind != NodeWideString || *name.StringValue != "Hé😀" {
+		t.Fatalf("name = %+v, want wide string %q", name, "Hé😀")
+	}
+
+	encoded, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	if !bytes.Equal(encoded, data) {
+		t.Fatalf("round-trip mismatch:\n got %x\nwant %x", encoded, data)
+	}
+
+	if want := estimateBinaryDocumentSize(doc) - checksumSize; len(encoded) != want {
+		t.Fatalf("encoded size = %d, want estimate %d", len(encoded), want)
+	}
+
+	var manual bytes.Buffer
+	enc := NewEncoder(&manual, EncodeOptions{Format: FormatBinary})
+	if err := enc.StartObject("app"); err != nil {
+		t.Fatalf("StartObject() returned error: %v", err)
+	}
+
+	if err := enc.WriteWideString("name", "Hé😀"); err != nil {
+		t.Fatalf("WriteWideString() returned error: %v", err)
+	}
+
+	if err := enc.EndObject(); err != nil {
+		t.Fatalf("EndObject() returned error: %v", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	if !bytes.Equal(manual.Bytes(), data) {
+		t.Fatalf("manual encoding mismatch:\n got %x\nwant %x", manual.Bytes(), data)
+	}
+
+	edited, err := EditBytes(data, []Edit{{Path: "app/name", Value: "Hi"}})
+	if err != nil {
+		t.Fatalf("EditBytes() returned error: %v", err)
+	}
+
+	patched, err := ParseBytes(edited, DecodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("ParseBytes(edited) returned error: %v", err)
+	}
+
+	if name := patched.Roots[0].First("name"); name.Kind != NodeWideString || *name.StringValue != "Hi" {
+		t.Fatalf("edited name = %+v, want wide string %q", name, "Hi")
+	}
+}
+
+func TestBinaryColor(t *testing.T) {
+	t.Parallel()
+
+	data := []byte{
+		binaryTypeMapStart, 's', 'c', 'h', 'e', 'm', 'e', 0,
+		binaryTypeColor, 'f', 'g', 0, 0xFF, 0x80, 0x00, 0xC8,
+		binaryTypeMapEnd,
+		binaryTypeMapEnd,
+	}
+
+	doc, err := ParseBytes(data, DecodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("ParseBytes() returned error: %v", err)
+	}
+
+	want := Color{R: 255, G: 128, B: 0, A: 200}
+	if got, ok := doc.Roots[0].First("fg").Color(); !ok || got != want {
+		t.Fatalf("Color() = %v, %t, want %v", got, ok, want)
+	}
+
+	encoded, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	if !bytes.Equal(encoded, data) {
+		t.Fatalf("round-trip mismatch:\n got %x\nwant %x", encoded, data)
+	}
+
+	text, err := AppendText(nil, doc, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	if want := `"scheme" { "fg" "255 128 0 200" } `; string(text) != want {
+		t.Fatalf("AppendText() = %q, want %q", text, want)
+	}
+
+	var scheme struct {
+		Scheme struct {
+			FG Color `vdf:"fg"`
+		} `vdf:"scheme"`
+	}
+
+	for _, src := range [][]byte{data, text} {
+		if err := Unmarshal(src, &scheme); err != nil {
+			t.Fatalf("Unmarshal() returned error: %v", err)
+		}
+
+		if scheme.Scheme.FG != want {
+			t.Fatalf("Unmarshal() FG = %v, want %v", scheme.Scheme.FG, want)
+		}
+	}
+
+	marshaled, err := Marshal(scheme, EncodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+
+	if !bytes.Equal(marshaled, data) {
+		t.Fatalf("Marshal() = %x, want %x", marshaled, data)
+	}
+
+	var manual bytes.Buffer
+	enc := NewEncoder(&manual, EncodeOptions{Format: FormatBinary})
+	if err := enc.StartObject("scheme"); err != nil {
+		t.Fatalf("StartObject() returned error: %v", err)
+	}
+
+	if err := enc.WriteColor("fg", want); err != nil {
+		t.Fatalf("WriteColor() returned error: %v", err)
+	}
+
+	if err := enc.EndObject(); err != nil {
+		t.Fatalf("EndObject() returned error: %v", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	if !bytes.Equal(manual.Bytes(), data) {
+		t.Fatalf("manual encoding mismatch:\n got %x\nwant %x", manual.Bytes(), data)
+	}
+}
+
+func TestBinaryUint64(t *testing.T) {
+	t.Parallel()
+
+	const steamID = uint64(76561197960287930)
+
+	doc := NewDocumentWithFormat(FormatBinary)
+	root := NewObjectNode("user")
+	root.Add(NewUint64Node("steamid", steamID))
+	doc.AddRoot(root)
+
+	data, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	if want := estimateBinaryDocumentSize(doc) - checksumSize; len(data) != want {
+		t.Fatalf("encoded size = %d, want estimate %d", len(data), want)
+	}
+
+	decoded, err := ParseBytes(data, DecodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("ParseBytes() returned error: %v", err)
+	}
+
+	id := decoded.Roots[0].First("steamid")
+	if id == nil || id.Kind != NodeUint64 || *id.Uint64Value != steamID {
+		t.Fatalf("steamid = %+v, want uint64 %d", id, steamID)
+	}
+
+	text, err := AppendText(nil, decoded, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	if want := `"user" { "steamid" "76561197960287930" } `; string(text) != want {
+		t.Fatalf("AppendText() = %q, want %q", text, want)
+	}
+
+	var manual bytes.Buffer
+	enc := NewEncoder(&manual, EncodeOptions{Format: FormatBinary})
+	if err := enc.StartObject("user"); err != nil {
+		t.Fatalf("StartObject() returned error: %v", err)
+	}
+
+	if err := enc.WriteUint64("steamid", steamID); err != nil {
+		t.Fatalf("WriteUint64() returned error: %v", err)
+	}
+
+	if err := enc.EndObject(); err != nil {
+		t.Fatalf("EndObject() returned error: %v", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	if !bytes.Equal(manual.Bytes(), data) {
+		t.Fatalf("manual encoding mismatch:\n got %x\nwant %x", manual.Bytes(), data)
+	}
+
+	var user struct {
+		User struct {
+			SteamID uint64 `vdf:"steamid"`
+		} `vdf:"user"`
+	}
+
+	if err := Unmarshal(data, &user); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+
+	if user.User.SteamID != steamID {
+		t.Fatalf("Unmarshal() SteamID = %d, want %d", user.User.SteamID, steamID)
+	}
+
+	marshaled, err := Marshal(user, EncodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+
+	if !bytes.Equal(marshaled, data) {
+		t.Fatalf("Marshal() = %x, want %x", marshaled, data)
+	}
+}
+
+func TestBinaryInt64(t *testing.T) {
+	t.Parallel()
+
+	data := []byte{
+		binaryTypeMapStart, 's', 't', 'a', 't', 's', 0,
+		binaryTypeInt64, 'd', 'e', 'l', 't', 'a', 0, 0xFE, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+		binaryTypeMapEnd,
+		binaryTypeMapEnd,
+	}
+
+	doc, err := ParseBytes(data, DecodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("ParseBytes() returned error: %v", err)
+	}
+
+	delta := doc.Roots[0].First("delta")
+	if delta == nil || delta.Kind != NodeInt64 || *delta.Int64Value != -2 {
+		t.Fatalf("delta = %+v, want int64 -2", delta)
+	}
+
+	encoded, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	if !bytes.Equal(encoded, data) {
+		t.Fatalf("round-trip mismatch:\n got %x\nwant %x", encoded, data)
+	}
+
+	if want := estimateBinaryDocumentSize(doc) - checksumSize; len(encoded) != want {
+		t.Fatalf("encoded size = %d, want estimate %d", len(encoded), want)
+	}
+
+	text, err := AppendText(nil, doc, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	if want := `"stats" { "delta" "-2" } `; string(text) != want {
+		t.Fatalf("AppendText() = %q, want %q", text, want)
+	}
+
+	var manual bytes.Buffer
+	enc := NewEncoder(&manual, EncodeOptions{Format: FormatBinary})
+	if err := enc.StartObject("stats"); err != nil {
+		t.Fatalf("StartObject() returned error: %v", err)
+	}
+
+	if err := enc.WriteInt64("delta", -2); err != nil {
+		t.Fatalf("WriteInt64() returned error: %v", err)
+	}
+
+	if err := enc.EndObject(); err != nil {
+		t.Fatalf("EndObject() returned error: %v", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	if !bytes.Equal(manual.Bytes(), data) {
+		t.Fatalf("manual encoding mismatch:\n got %x\nwant %x", manual.Bytes(), data)
+	}
+}
+
+func TestBinaryVBKV(t *testing.T) {
+	t.Parallel()
+
+	doc := NewDocumentWithFormat(FormatBinary)
+	root := NewObjectNode("config")
+	root.Add(NewStringNode("name", "value"))
+	doc.AddRoot(root)
+
+	body, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	data, err := AppendBinary(nil, doc, EncodeOptions{VBKV: true})
+	if err != nil {
+		t.Fatalf("AppendBinary(VBKV) returned error: %v", err)
+	}
+
+	if string(data[:4]) != "VBKV" || binary.LittleEndian.Uint32(data[4:8]) != crc32.ChecksumIEEE(body) || !bytes.Equal(data[8:], body) {
+		t.Fatalf("VBKV output = %x", data)
+	}
+
+	decoded, err := ParseBytes(data, DecodeOptions{})
+	if err != nil {
+		t.Fatalf("ParseBytes(auto) returned error: %v", err)
+	}
+
+	if name := decoded.Roots[0].First("name"); name == nil || *name.StringValue != "value" {
+		t.Fatalf("decoded = %+v", decoded.Roots[0])
+	}
+
+	spans, err := ParseBytes(data, DecodeOptions{Format: FormatBinary, RecordSpans: true})
+	if err != nil {
+		t.Fatalf("ParseBytes(spans) returned error: %v", err)
+	}
+
+	if span := spans.Roots[0].First("name").Span; string(data[span.ValueStart:span.ValueEnd]) != "value" {
+		t.Fatalf("span = %+v", span)
+	}
+
+	corrupt := bytes.Clone(data)
+	corrupt[4] ^= 0xFF
+	if _, err := ParseBytes(corrupt, DecodeOptions{Format: FormatBinary}); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("ParseBytes(corrupt) error = %v, want ErrChecksumMismatch", err)
+	}
+
+	if _, err := ParseBytes(corrupt, DecodeOptions{Format: FormatBinary, SkipVBKVChecksum: true}); err != nil {
+		t.Fatalf("ParseBytes(SkipVBKVChecksum) returned error: %v", err)
+	}
+
+	var filtered bytes.Buffer
+	if err := Filter(&filtered, bytes.NewReader(data), func([]string) bool { return true }, DecodeOptions{}); err != nil {
+		t.Fatalf("Filter() returned error: %v", err)
+	}
+
+	if !bytes.Equal(filtered.Bytes(), body) {
+		t.Fatalf("Filter() = %x, want %x", filtered.Bytes(), body)
+	}
+}
+
+func TestDocumentMarshalers(t *testing.T) {
+	t.Parallel()
+
+	doc := NewDocument()
+	root := NewObjectNode("root")
+	root.Add(NewStringNode("name", "x"))
+	root.Add(NewUint32Node("appid", 440))
+	doc.AddRoot(root)
+
+	text, err := doc.MarshalText()
+	if err != nil || !bytes.Contains(text, []byte(`"name"`)) {
+		t.Fatalf("MarshalText = %q, %v", text, err)
+	}
+
+	bin, err := doc.MarshalBinary()
+	if err != nil || bin[0] != binaryTypeMapStart {
+		t.Fatalf("MarshalBinary = %v, %v", bin, err)
+	}
+
+	var fromBinary Document
+	if err := fromBinary.UnmarshalBinary(bin); err != nil || !fromBinary.Equal(doc) {
+		t.Fatalf("UnmarshalBinary = %+v, %v", fromBinary, err)
+	}
+
+	// Auto-detection accepts either encoding on both unmarshalers.
+	var fromText Document
+	if err := fromText.UnmarshalBinary(text); err != nil || fromText.Get("root/name") == nil {
+		t.Fatalf("UnmarshalBinary(text) = %+v, %v", fromText, err)
+	}
+
+	if err := fromText.UnmarshalText(bin); err != nil || !fromText.Equal(doc) {
+		t.Fatalf("UnmarshalText(binary) = %+v, %v", fromText, err)
+	}
+
+	if err := fromText.UnmarshalText([]byte(`"root" {`)); err == nil {
+		t.Fatal("UnmarshalText(truncated) returned no error")
+	}
+}
+
+func TestRawNodeStreamingAndTypeCheck(t *testing.T) {
+	t.Parallel()
+
+	doc := NewDocument()
+	root := NewObjectNode("root")
+	root.Add(NewRawNode("vendor", 0x42, []byte{7}))
+	doc.AddRoot(root)
+
+	data, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	decode := DecodeOptions{Format: FormatBinary, PreserveUnknownTypes: true, RawTypeSizes: map[byte]int{0x42: 1}}
+
+	// The event iterator and the stream reader both emit raw entries.
+	var fromDoc, fromStream []Event
+	dec := NewDecoder(bytes.NewReader(data), decode)
+	for event, err := range dec.Events() {
+		if err != nil {
+			t.Fatalf("Events() returned error: %v", err)
+		}
+
+		fromStream = append(fromStream, event)
+	}
+
+	dec = NewDecoder(bytes.NewReader(data), decode)
+	if _, err := dec.DecodeDocument(); err != nil {
+		t.Fatalf("DecodeDocument() returned error: %v", err)
+	}
+
+	for {
+		event, err := dec.NextEvent()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("NextEvent() returned error: %v", err)
+		}
+
+		fromDoc = append(fromDoc, event)
+	}
+
+	for _, events := range [][]Event{fromDoc, fromStream} {
+		raw := slices.IndexFunc(events, func(e Event) bool { return e.Type == EventRaw })
+		if raw < 0 || events[raw].RawType != 0x42 || !bytes.Equal(events[raw].RawValue, []byte{7}) {
+			t.Fatalf("events = %+v, want a raw event", events)
+		}
+	}
+
+	var out bytes.Buffer
+	err = Transcode(&out, bytes.NewReader(data), TranscodeOptions{Decode: decode, Encode: EncodeOptions{Format: FormatBinary}})
+	if err != nil || !bytes.Equal(out.Bytes(), data) {
+		t.Fatalf("Transcode(raw) = %x, %v, want %x", out.Bytes(), err, data)
+	}
+
+	err = NewEncoder(&out, EncodeOptions{Format: FormatText}).WriteEvent(Event{Type: EventRaw, Key: "k", RawType: 0x42, RawValue: []byte{1}})
+	if !errors.Is(err, ErrInvalidFormat) {
+		t.Fatalf("WriteEvent(raw, text) error = %v, want ErrInvalidFormat", err)
+	}
+
+	// A raw node must not reuse a built-in type byte such as the map end marker.
+	root.Children[0].RawType = binaryTypeMapEnd
+	if _, err := AppendBinary(nil, doc, EncodeOptions{}); !errors.Is(err, ErrInvalidNodeState) {
+		t.Fatalf("AppendBinary(reserved raw type) error = %v, want ErrInvalidNodeState", err)
+	}
+
+	if err := doc.Validate(); !errors.Is(err, ErrInvalidNodeState) {
+		t.Fatalf("Validate(reserved raw type) error = %v, want ErrInvalidNodeState", err)
+	}
+}
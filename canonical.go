@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"strings"
+)
+
+// Canonical returns a normalized deep copy of d for comparison and caching:
+// keys are lower-cased and sorted by bytes at every level, keeping repeated keys
+// in their relative order, and source details that do not change meaning, such
+// as token quoting, whitespace, comments, spans and positions, are dropped,
+// so escapes and quoting are written uniformly when encoded.
+// Values, kinds and conditionals are kept as is.
+func (d *Document) Canonical() *Document {
+	if d == nil {
+		return nil
+	}
+
+	out := &Document{Format: d.Format, Roots: make([]*Node, 0, len(d.Roots))}
+	for _, root := range d.Roots {
+		if root != nil {
+			out.Roots = append(out.Roots, cloneNode(root))
+		}
+	}
+
+	for node := range out.Nodes() {
+		node.Key = strings.ToLower(node.Key)
+		node.Layout = nil
+		node.KeyUnquoted, node.ValueUnquoted = false, false
+		if node.Kind == NodeObject {
+			node.Children = compactNodes(node.Children)
+		}
+	}
+
+	out.Sort(nil, true)
+	return out
+}
+
+// compactNodes returns nodes without nil entries.
+func compactNodes(nodes []*Node) []*Node {
+	out := nodes[:0]
+	for _, node := range nodes {
+		if node != nil {
+			out = append(out, node)
+		}
+	}
+
+	return out
+}
+
+// Hash returns a SHA-256 digest of the roots of d: keys, kinds, values,
+// conditionals and order. Layout, spans, positions and the document format do
+// not affect it, so equal trees hash equally across runs and machines.
+// Hash d.Canonical() to ignore key case and order as well.
+func (d *Document) Hash() [sha256.Size]byte {
+	h := sha256.New()
+	if d != nil {
+		for _, root := range d.Roots {
+			hashNode(h, root)
+		}
+	}
+
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// hashNode writes a length-prefixed encoding of a subtree in pre-order.
+func hashNode(h hash.Hash, root *Node) {
+	stack := []*Node{root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if node == nil {
+			continue
+		}
+
+		h.Write([]byte{byte(node.Kind), node.RawType})
+		hashString(h, node.Key)
+		hashString(h, node.Condition)
+
+		switch node.Kind {
+		case NodeObject:
+			hashUint(h, uint64(len(node.Children)-countNil(node.Children)))
+			for i := len(node.Children) - 1; i >= 0; i-- {
+				stack = append(stack, node.Children[i])
+			}
+		case NodeRaw:
+			hashString(h, string(node.RawValue))
+		default:
+			value, _ := textValueForNode(node)
+			hashString(h, value)
+		}
+	}
+}
+
+// countNil returns the number of nil nodes.
+func countNil(nodes []*Node) int {
+	count := 0
+	for _, node := range nodes {
+		if node == nil {
+			count++
+		}
+	}
+
+	return count
+}
+
+// hashString writes a length-prefixed string.
+func hashString(h hash.Hash, s string) {
+	hashUint(h, uint64(len(s)))
+	h.Write([]byte(s))
+}
+
+// hashUint writes a fixed-size little-endian integer.
+func hashUint(h hash.Hash, v uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	h.Write(buf[:])
+}
@@ -0,0 +1,38 @@
+package vdf
+
+import "testing"
+
+func TestCanonicalAndHash(t *testing.T) {
+	t.Parallel()
+
+	a := mustParseString(t, "// comment\n\"App\" { \"Name\" \"x\" \"b\" \"1\" \"a\" { \"z\" \"1\" \"Y\" \"2\" } }")
+	b := mustParseString(t, `app { a { y 2 z 1 } b 1 name x }`)
+
+	if a.Hash() == b.Hash() {
+		t.Fatal("Hash() of differently ordered documents is equal")
+	}
+
+	canonA, canonB := a.Canonical(), b.Canonical()
+	if canonA.Hash() != canonB.Hash() {
+		t.Fatalf("Canonical().Hash() differs:\n%v\n%v", canonA.Roots[0], canonB.Roots[0])
+	}
+
+	out, err := AppendText(nil, canonB, EncodeOptions{Compact: true, QuoteStyle: QuotePreserve})
+	if err != nil || string(out) != `"app" { "a" { "y" "2" "z" "1" } "b" "1" "name" "x" } ` {
+		t.Fatalf("canonical text = %q, %v", out, err)
+	}
+
+	if a.Roots[0].Key != "App" || a.Roots[0].Children[0].Key != "Name" {
+		t.Fatal("Canonical() modified the source document")
+	}
+
+	parsed := mustParseString(t, "\"App\" { \"Name\" \"x\" \"b\" \"1\" \"a\" { \"z\" \"1\" \"Y\" \"2\" } }")
+	if parsed.Hash() != a.Hash() {
+		t.Fatal("Hash() depends on comments or layout")
+	}
+
+	changed := mustParseString(t, "\"App\" { \"Name\" \"y\" \"b\" \"1\" \"a\" { \"z\" \"1\" \"Y\" \"2\" } }")
+	if changed.Hash() == a.Hash() {
+		t.Fatal("Hash() did not change with a value")
+	}
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"maps"
+	"slices"
+)
+
+// Feature names an optional subsystem reported by Capabilities.
+type Feature string
+
+const (
+	// FeatureChecksum is CRC32 trailer writing and verification for binary VDF.
+	FeatureChecksum Feature = "checksum"
+	// FeatureRawTypes is NodeRaw preservation of unrecognized binary types.
+	FeatureRawTypes Feature = "raw-types"
+	// FeatureSpans is source byte span recording and in-place raw edits.
+	FeatureSpans Feature = "spans"
+	// FeatureStreaming is event streaming, filtering, and redaction without building an AST.
+	FeatureStreaming Feature = "streaming"
+	// FeatureReflection is struct and map encoding through reflection.
+	FeatureReflection Feature = "reflection"
+	// FeatureKV3 is KeyValues3 text and binary support.
+	FeatureKV3 Feature = "kv3"
+	// FeatureAppInfo is appinfo.vdf and packageinfo.vdf decoding.
+	FeatureAppInfo Feature = "appinfo"
+	// FeatureIncludes is "#include" and "#base" directive parsing and resolution.
+	FeatureIncludes Feature = "includes"
+	// FeatureConditionals is "[$WIN32]"-style conditional tag handling.
+	FeatureConditionals Feature = "conditionals"
+)
+
+// CapabilitySet describes what this build of the package supports.
+type CapabilitySet struct {
+	// Formats lists concrete encode and decode formats.
+	Formats []Format `json:"formats" yaml:"formats"`
+	// BinaryTypes lists binary type bytes decoded into typed nodes, including the map end marker.
+	BinaryTypes []byte `json:"binary_types" yaml:"binary_types"`
+	// RawBinaryTypes lists binary type bytes that can be preserved as NodeRaw leaves.
+	RawBinaryTypes []byte `json:"raw_binary_types" yaml:"raw_binary_types"`
+	// Encodings lists supported text encodings.
+	Encodings []string `json:"encodings" yaml:"encodings"`
+	// Features lists supported optional subsystems.
+	Features []Feature `json:"features" yaml:"features"`
+}
+
+// Capabilities reports formats, binary type bytes, text encodings, and optional
+// subsystems supported by this build, for feature detection at runtime.
+// The returned value is a fresh copy and may be modified by the caller.
+func Capabilities() CapabilitySet {
+	return CapabilitySet{
+		Formats: []Format{FormatText, FormatBinary},
+		BinaryTypes: []byte{
+			binaryTypeMapStart,
+			binaryTypeString,
+			binaryTypeNumber,
+			binaryTypeFloat32,
+			binaryTypePointer,
+			binaryTypeWideString,
+			binaryTypeColor,
+			binaryTypeUint64,
+			binaryTypeMapEnd,
+			binaryTypeInt64,
+		},
+		RawBinaryTypes: slices.Sorted(maps.Keys(builtinRawTypeSizes)),
+		Encodings:      []string{"utf-8"},
+		Features: []Feature{
+			FeatureChecksum,
+			FeatureRawTypes,
+			FeatureSpans,
+			FeatureStreaming,
+			FeatureReflection,
+			FeatureAppInfo,
+			FeatureIncludes,
+			FeatureConditionals,
+		},
+	}
+}
+
+// Has reports whether feature is supported.
+func (c CapabilitySet) Has(feature Feature) bool {
+	return slices.Contains(c.Features, feature)
+}
@@ -0,0 +1,34 @@
+package vdf
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	caps := Capabilities()
+	if !slices.Equal(caps.Formats, []Format{FormatText, FormatBinary}) {
+		t.Fatalf("Formats = %v", caps.Formats)
+	}
+
+	if !caps.Has(FeatureChecksum) || !caps.Has(FeatureSpans) {
+		t.Fatalf("Features = %v, want checksum and spans", caps.Features)
+	}
+
+	if caps.Has(FeatureKV3) {
+		t.Fatal("Has(FeatureKV3) = true, want false")
+	}
+
+	for _, typeByte := range caps.BinaryTypes {
+		if slices.Contains(caps.RawBinaryTypes, typeByte) {
+			t.Fatalf("RawBinaryTypes = %x contains native type 0x%02x", caps.RawBinaryTypes, typeByte)
+		}
+	}
+
+	caps.Features[0] = "mutated"
+	if !Capabilities().Has(FeatureChecksum) {
+		t.Fatal("Capabilities() shares state between calls")
+	}
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// checksumSize is the byte length of a CRC32 trailer.
+const checksumSize = 4
+
+// checksumWriter forwards writes and accumulates a CRC32 of written bytes.
+type checksumWriter struct {
+	w   io.Writer // Destination writer.
+	crc uint32    // Running CRC32 (IEEE) of written bytes.
+}
+
+// Write forwards bytes and updates the checksum with the written prefix.
+func (c *checksumWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.crc = crc32.Update(c.crc, crc32.IEEETable, p[:n])
+	return n, err
+}
+
+// WriteByte forwards one byte and updates the checksum.
+func (c *checksumWriter) WriteByte(b byte) error {
+	if err := writeBinaryByte(c.w, b); err != nil {
+		return err
+	}
+
+	one := [1]byte{b}
+	c.crc = crc32.Update(c.crc, crc32.IEEETable, one[:])
+	return nil
+}
+
+// writeTrailer writes the accumulated checksum to the destination without hashing it.
+func (c *checksumWriter) writeTrailer() error {
+	var raw [checksumSize]byte
+	binary.LittleEndian.PutUint32(raw[:], c.crc)
+	_, err := c.w.Write(raw[:])
+	return err
+}
+
+// checksumReader forwards reads and accumulates a CRC32 of consumed bytes.
+type checksumReader struct {
+	r   binaryReadReader // Source reader.
+	crc uint32           // Running CRC32 (IEEE) of consumed bytes.
+}
+
+// Read forwards reads and updates the checksum with returned bytes.
+func (c *checksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.crc = crc32.Update(c.crc, crc32.IEEETable, p[:n])
+	return n, err
+}
+
+// ReadByte forwards one byte read and updates the checksum.
+func (c *checksumReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	one := [1]byte{b}
+	c.crc = crc32.Update(c.crc, crc32.IEEETable, one[:])
+	return b, nil
+}
+
+// verifyTrailer reads a CRC32 trailer from the source and compares it with consumed bytes.
+func (c *checksumReader) verifyTrailer() error {
+	var raw [checksumSize]byte
+	if _, err := io.ReadFull(c.r, raw[:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return fmt.Errorf("%w: missing checksum trailer", ErrBufferOverflow)
+		}
+
+		return err
+	}
+
+	if want := binary.LittleEndian.Uint32(raw[:]); want != c.crc {
+		return fmt.Errorf("%w: got 0x%08x, want 0x%08x", ErrChecksumMismatch, c.crc, want)
+	}
+
+	return nil
+}
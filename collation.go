@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"cmp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Collation is a set of key comparison rules for deterministic ordering.
+// The zero value compares keys as raw bytes.
+type Collation uint8
+
+const (
+	// CollationFoldCase compares keys case-insensitively, grouping mixed-case keys.
+	CollationFoldCase Collation = 1 << iota
+	// CollationNumeric compares digit runs by numeric value, so "App2" sorts before "App10".
+	CollationNumeric
+)
+
+// Compare returns -1, 0, or +1 comparing a and b under the collation rules.
+// Keys that collate equal but differ in bytes are ordered by raw bytes,
+// so results stay total and deterministic.
+func (c Collation) Compare(a, b string) int {
+	if c == 0 || a == b {
+		return strings.Compare(a, b)
+	}
+
+	if result := c.compareRules(a, b); result != 0 {
+		return result
+	}
+
+	return strings.Compare(a, b)
+}
+
+// compareRules compares a and b with case folding and numeric runs as configured.
+func (c Collation) compareRules(a, b string) int {
+	for a != "" && b != "" {
+		if c&CollationNumeric != 0 && isASCIIDigit(a[0]) && isASCIIDigit(b[0]) {
+			runA, restA := cutDigits(a)
+			runB, restB := cutDigits(b)
+			if result := compareDigitRuns(runA, runB); result != 0 {
+				return result
+			}
+
+			a, b = restA, restB
+			continue
+		}
+
+		ra, sizeA := utf8.DecodeRuneInString(a)
+		rb, sizeB := utf8.DecodeRuneInString(b)
+		if c&CollationFoldCase != 0 {
+			ra, rb = unicode.ToLower(ra), unicode.ToLower(rb)
+		}
+
+		if ra != rb {
+			if ra < rb {
+				return -1
+			}
+
+			return 1
+		}
+
+		a, b = a[sizeA:], b[sizeB:]
+	}
+
+	return cmp.Compare(len(a), len(b))
+}
+
+// compareDigitRuns compares two ASCII digit runs by numeric value without overflow.
+func compareDigitRuns(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
+
+// cutDigits splits a leading ASCII digit run from s.
+func cutDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isASCIIDigit(s[i]) {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
+
+// isASCIIDigit reports whether b is an ASCII decimal digit.
+func isASCIIDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
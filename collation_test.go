@@ -0,0 +1,48 @@
+package vdf
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCollationCompare(t *testing.T) {
+	t.Parallel()
+
+	keys := []string{"app10", "App2", "app1", "b", "App02", "a"}
+	cases := []struct {
+		collation Collation
+		want      []string
+	}{
+		{0, []string{"App02", "App2", "a", "app1", "app10", "b"}},
+		{CollationFoldCase, []string{"a", "App02", "app1", "app10", "App2", "b"}},
+		{CollationNumeric, []string{"App02", "App2", "a", "app1", "app10", "b"}},
+		{CollationFoldCase | CollationNumeric, []string{"a", "app1", "App02", "App2", "app10", "b"}},
+	}
+
+	for _, tc := range cases {
+		got := slices.Clone(keys)
+		slices.SortFunc(got, tc.collation.Compare)
+		if !slices.Equal(got, tc.want) {
+			t.Fatalf("collation %d order = %v, want %v", tc.collation, got, tc.want)
+		}
+	}
+}
+
+func TestEncodeDeterministicCollation(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"root" { "App10" "x" "app2" "y" "App1" "z" }`)
+	got, err := AppendText(nil, doc, EncodeOptions{
+		Compact:       true,
+		Deterministic: true,
+		Collation:     CollationFoldCase | CollationNumeric,
+	})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	want := `"root" { "App1" "z" "app2" "y" "App10" "x" } `
+	if string(got) != want {
+		t.Fatalf("AppendText() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Color is an RGBA value stored by binary KeyValues type 0x06.
+type Color struct {
+	R uint8 `json:"r" yaml:"r"`
+	G uint8 `json:"g" yaml:"g"`
+	B uint8 `json:"b" yaml:"b"`
+	A uint8 `json:"a" yaml:"a"`
+}
+
+// String formats the color as four space-separated decimal components, as Valve text dumps do.
+func (c Color) String() string {
+	return fmt.Sprintf("%d %d %d %d", c.R, c.G, c.B, c.A)
+}
+
+// MarshalVDF stores the color as a NodeColor leaf.
+func (c Color) MarshalVDF() (*Node, error) {
+	return NewColorNode("", c), nil
+}
+
+// UnmarshalVDF reads a NodeColor leaf or a text leaf holding "R G B A" components.
+func (c *Color) UnmarshalVDF(node *Node) error {
+	if color, ok := node.Color(); ok {
+		*c = color
+		return nil
+	}
+
+	if node.Kind == NodeObject {
+		return fmt.Errorf("%w: key %q is an object for vdf.Color", ErrValueConversion, node.Key)
+	}
+
+	text, err := textValueForNode(node)
+	if err != nil {
+		return err
+	}
+
+	color, err := parseColor(text)
+	if err != nil {
+		return fmt.Errorf("key %q: %w", node.Key, err)
+	}
+
+	*c = color
+	return nil
+}
+
+// Color returns the value of a NodeColor leaf.
+func (n *Node) Color() (Color, bool) {
+	if n == nil || n.Kind != NodeColor || n.ColorValue == nil {
+		return Color{}, false
+	}
+
+	return *n.ColorValue, true
+}
+
+// parseColor parses four whitespace-separated decimal components.
+func parseColor(text string) (Color, error) {
+	fields := strings.Fields(text)
+	if len(fields) != 4 {
+		return Color{}, fmt.Errorf("%w: %q is not an RGBA color", ErrValueConversion, text)
+	}
+
+	var parts [4]uint8
+	for i, field := range fields {
+		value, err := strconv.ParseUint(field, 10, 8)
+		if err != nil {
+			return Color{}, fmt.Errorf("%w: %q is not an RGBA color", ErrValueConversion, text)
+		}
+
+		parts[i] = uint8(value)
+	}
+
+	return Color{R: parts[0], G: parts[1], B: parts[2], A: parts[3]}, nil
+}
@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"bytes"
+	"math"
+	"strings"
+)
+
+// ChangedPaths returns paths of entries that differ between a and b.
+// Paths join keys with PathSeparator; the n-th repeated occurrence of a key
+// (n > 0) within one object is addressed as "key[n]".
+// Entries present on one side only, leaves with different values or kinds,
+// and objects replaced by leaves (or vice versa) are reported at their own path;
+// objects present on both sides are compared child by child.
+// Reordering entries without changing them is not reported.
+func ChangedPaths(a, b *Document) []string {
+	changed := make([]string, 0)
+	collectChangedPaths(documentRoots(a), documentRoots(b), "", &changed)
+	return changed
+}
+
+// documentRoots returns document roots or nil for a nil document.
+func documentRoots(doc *Document) []*Node {
+	if doc == nil {
+		return nil
+	}
+
+	return doc.Roots
+}
+
+// collectChangedPaths compares two sibling lists and appends differing paths.
+func collectChangedPaths(left, right []*Node, prefix string, changed *[]string) {
+	rightByKey := groupByKey(right)
+	leftSeen := make(map[string]int, len(left))
+
+	for _, node := range left {
+		if node == nil {
+			continue
+		}
+
+		occurrence := leftSeen[node.Key]
+		leftSeen[node.Key]++
+
+		path := joinOccurrencePath(prefix, node.Key, occurrence)
+		matches := rightByKey[node.Key]
+		if occurrence >= len(matches) {
+			*changed = append(*changed, path)
+			continue
+		}
+
+		other := matches[occurrence]
+		if node.Kind == NodeObject && other.Kind == NodeObject {
+			collectChangedPaths(node.Children, other.Children, path, changed)
+			continue
+		}
+
+		if !leafEqual(node, other) {
+			*changed = append(*changed, path)
+		}
+	}
+
+	// Remaining right-side occurrences have no counterpart on the left.
+	rightSeen := make(map[string]int, len(right))
+	for _, node := range right {
+		if node == nil {
+			continue
+		}
+
+		occurrence := rightSeen[node.Key]
+		rightSeen[node.Key]++
+
+		if occurrence >= leftSeen[node.Key] {
+			*changed = append(*changed, joinOccurrencePath(prefix, node.Key, occurrence))
+		}
+	}
+}
+
+// groupByKey indexes non-nil nodes by key preserving occurrence order.
+func groupByKey(nodes []*Node) map[string][]*Node {
+	out := make(map[string][]*Node, len(nodes))
+	for _, node := range nodes {
+		if node != nil {
+			out[node.Key] = append(out[node.Key], node)
+		}
+	}
+
+	return out
+}
+
+// leafEqual reports whether two nodes have the same kind and scalar value.
+// Objects are never equal here; callers compare them structurally.
+func leafEqual(a, b *Node) bool {
+	if a.Kind != b.Kind {
+		return false
+	}
+
+	switch a.Kind {
+	case NodeString, NodeWideString, NodeDirective:
+		return a.StringValue != nil && b.StringValue != nil && *a.StringValue == *b.StringValue
+	case NodeUint32, NodePointer:
+		return a.Uint32Value != nil && b.Uint32Value != nil && *a.Uint32Value == *b.Uint32Value
+	case NodeFloat32:
+		return a.Float32Value != nil && b.Float32Value != nil && math.Float32bits(*a.Float32Value) == math.Float32bits(*b.Float32Value)
+	case NodeUint64:
+		return a.Uint64Value != nil && b.Uint64Value != nil && *a.Uint64Value == *b.Uint64Value
+	case NodeInt64:
+		return a.Int64Value != nil && b.Int64Value != nil && *a.Int64Value == *b.Int64Value
+	case NodeColor:
+		return a.ColorValue != nil && b.ColorValue != nil && *a.ColorValue == *b.ColorValue
+	case NodeRaw:
+		return a.RawType == b.RawType && bytes.Equal(a.RawValue, b.RawValue)
+	default:
+		return false
+	}
+}
+
+// EqualOptions relaxes Document.Equal and Node.Equal comparisons.
+type EqualOptions struct {
+	// IgnoreOrder pairs object children by key and occurrence instead of position,
+	// so only the relative order of repeated keys matters.
+	IgnoreOrder bool
+	// IgnoreCase compares keys case-insensitively.
+	IgnoreCase bool
+	// NumericStrings treats a string leaf as equal to a numeric leaf with the same
+	// text form, such as "440" and uint32 440, as after a text/binary round-trip.
+	NumericStrings bool
+}
+
+// Equal reports whether d and other hold equal roots under opts.
+// By default keys, kinds, values, conditionals and order must match exactly;
+// layout, spans and positions are ignored. At most one EqualOptions is used.
+func (d *Document) Equal(other *Document, opts ...EqualOptions) bool {
+	var options EqualOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	return nodesEqual(documentRoots(d), documentRoots(other), options)
+}
+
+// Equal reports whether n and other have equal keys, values and subtrees under opts,
+// following Document.Equal. At most one EqualOptions is used.
+func (n *Node) Equal(other *Node, opts ...EqualOptions) bool {
+	var options EqualOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	return nodeEqual(n, other, options)
+}
+
+// nodeEqual compares two nodes and their subtrees.
+func nodeEqual(a, b *Node, opts EqualOptions) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if !keysEqual(a.Key, b.Key, opts) || a.Condition != b.Condition {
+		return false
+	}
+
+	if a.Kind == NodeObject || b.Kind == NodeObject {
+		return a.Kind == b.Kind && nodesEqual(a.Children, b.Children, opts)
+	}
+
+	if leafEqual(a, b) {
+		return true
+	}
+
+	if !opts.NumericStrings || (a.Kind != NodeString && b.Kind != NodeString) {
+		return false
+	}
+
+	left, errA := textValueForNode(a)
+	right, errB := textValueForNode(b)
+	return errA == nil && errB == nil && left == right
+}
+
+// nodesEqual compares two sibling lists in order or, with IgnoreOrder, by key and occurrence.
+func nodesEqual(left, right []*Node, opts EqualOptions) bool {
+	if len(left) != len(right) {
+		return false
+	}
+
+	if !opts.IgnoreOrder {
+		for i := range left {
+			if !nodeEqual(left[i], right[i], opts) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	rightByKey := make(map[string][]*Node, len(right))
+	for _, node := range right {
+		key := equalKey(node, opts)
+		rightByKey[key] = append(rightByKey[key], node)
+	}
+
+	leftSeen := make(map[string]int, len(left))
+	for _, node := range left {
+		key := equalKey(node, opts)
+		occurrence := leftSeen[key]
+		leftSeen[key]++
+
+		matches := rightByKey[key]
+		if occurrence >= len(matches) || !nodeEqual(node, matches[occurrence], opts) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// keysEqual compares keys under the IgnoreCase option.
+func keysEqual(a, b string, opts EqualOptions) bool {
+	if opts.IgnoreCase {
+		return strings.EqualFold(a, b)
+	}
+
+	return a == b
+}
+
+// equalKey returns the grouping key of a node for IgnoreOrder pairing.
+// Nil nodes group under a NUL-prefixed key, which binary VDF keys cannot hold.
+func equalKey(node *Node, opts EqualOptions) string {
+	switch {
+	case node == nil:
+		return "\x00nil"
+	case opts.IgnoreCase:
+		return strings.ToLower(node.Key)
+	default:
+		return node.Key
+	}
+}
@@ -0,0 +1,75 @@
+package vdf
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestChangedPaths(t *testing.T) {
+	t.Parallel()
+
+	a := mustParseString(t, `"cfg" { "launch" "-novid" "apps" { "440" { "name" "tf2" } "570" { "name" "dota" } } "dup" "1" "dup" "2" "gone" "x" }`)
+	b := mustParseString(t, `"cfg" { "apps" { "570" { "name" "dota" } "440" { "name" "tf2" "beta" "1" } } "launch" "-novid -high" "dup" "1" "dup" "3" "dup" "4" }`)
+
+	got := ChangedPaths(a, b)
+	want := []string{
+		"cfg/launch",
+		"cfg/apps/440/beta",
+		"cfg/dup[1]",
+		"cfg/gone",
+		"cfg/dup[2]",
+	}
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("ChangedPaths() = %q, want %q", got, want)
+	}
+
+	if got := ChangedPaths(a, a); len(got) != 0 {
+		t.Fatalf("ChangedPaths(same) = %q, want none", got)
+	}
+
+	if got := ChangedPaths(nil, a); !slices.Equal(got, []string{"cfg"}) {
+		t.Fatalf("ChangedPaths(nil, a) = %q, want [cfg]", got)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	base := mustParseString(t, `"App" { "id" "440" "tag" "a" "tag" "b" "sub" { "x" "1" } }`)
+	reordered := mustParseString(t, `"App" { "sub" { "x" "1" } "tag" "a" "id" "440" "tag" "b" }`)
+	swapped := mustParseString(t, `"App" { "id" "440" "tag" "b" "tag" "a" "sub" { "x" "1" } }`)
+	upper := mustParseString(t, `"APP" { "ID" "440" "TAG" "a" "TAG" "b" "SUB" { "X" "1" } }`)
+
+	numeric := mustParseString(t, `"App" { "tag" "a" "tag" "b" "sub" { "x" "1" } }`)
+	if err := numeric.Roots[0].Insert(0, NewUint32Node("id", 440)); err != nil {
+		t.Fatalf("Insert() returned error: %v", err)
+	}
+
+	cases := []struct {
+		other *Document
+		name  string
+		opts  EqualOptions
+		want  bool
+	}{
+		{name: "same", other: mustParseString(t, `"App" { "id" "440" "tag" "a" "tag" "b" "sub" { "x" "1" } }`), want: true},
+		{name: "reordered", other: reordered, want: false},
+		{name: "reordered ignore order", other: reordered, opts: EqualOptions{IgnoreOrder: true}, want: true},
+		{name: "swapped duplicates", other: swapped, opts: EqualOptions{IgnoreOrder: true}, want: false},
+		{name: "upper", other: upper, want: false},
+		{name: "upper ignore case", other: upper, opts: EqualOptions{IgnoreCase: true}, want: true},
+		{name: "numeric", other: numeric, want: false},
+		{name: "numeric strings", other: numeric, opts: EqualOptions{NumericStrings: true}, want: true},
+		{name: "nil", other: nil, want: false},
+	}
+
+	for _, tc := range cases {
+		if got := base.Equal(tc.other, tc.opts); got != tc.want {
+			t.Fatalf("Equal(%s) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	if !base.Roots[0].First("sub").Equal(upper.Roots[0].First("SUB"), EqualOptions{IgnoreCase: true}) {
+		t.Fatal("Node.Equal(IgnoreCase) = false, want true")
+	}
+}
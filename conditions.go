@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import "strings"
+
+// conditionSymbols lowercases condition symbol names for case-insensitive lookup.
+// It returns nil for nil input, which keeps conditionals unevaluated.
+func conditionSymbols(conditions map[string]bool) map[string]bool {
+	if conditions == nil {
+		return nil
+	}
+
+	symbols := make(map[string]bool, len(conditions))
+	for name, value := range conditions {
+		name = strings.ToLower(strings.TrimPrefix(name, "$"))
+		symbols[name] = symbols[name] || value
+	}
+
+	return symbols
+}
+
+// evalCondition evaluates a conditional body such as "$WIN32", "!$OSX" or
+// "$X360||$PS3" against lowercased symbols. "&&" binds tighter than "||",
+// and each term may be negated with "!"; malformed terms are false.
+func evalCondition(expr string, symbols map[string]bool) bool {
+	for alternative := range strings.SplitSeq(expr, "||") {
+		holds := true
+		for term := range strings.SplitSeq(alternative, "&&") {
+			if !evalConditionTerm(term, symbols) {
+				holds = false
+				break
+			}
+		}
+
+		if holds {
+			return true
+		}
+	}
+
+	return false
+}
+
+// evalConditionTerm evaluates one optionally negated "$SYMBOL" term.
+func evalConditionTerm(term string, symbols map[string]bool) bool {
+	term = strings.TrimSpace(term)
+	negated := false
+	for strings.HasPrefix(term, "!") {
+		negated = !negated
+		term = strings.TrimSpace(term[1:])
+	}
+
+	name, ok := strings.CutPrefix(term, "$")
+	if !ok || name == "" {
+		return false
+	}
+
+	return symbols[strings.ToLower(name)] != negated
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import "fmt"
+
+// DedupePolicy controls how Dedupe collapses repeated keys within one object.
+type DedupePolicy uint8
+
+const (
+	// DedupeKeepFirst keeps the first occurrence of each key.
+	DedupeKeepFirst DedupePolicy = iota
+	// DedupeKeepLast keeps the last occurrence at the position of the first one,
+	// matching the effective "last wins" lookup of Valve KeyValues.
+	DedupeKeepLast
+	// DedupeMergeObjects merges repeated objects into the first one, appending
+	// their children in source order before those are deduplicated in turn;
+	// for leaves, and for objects mixed with leaves, the last occurrence wins.
+	DedupeMergeObjects
+)
+
+// Dedupe collapses repeated keys among the document roots and every object
+// below them under policy, in place, and returns the number of nodes removed.
+func (d *Document) Dedupe(policy DedupePolicy) (int, error) {
+	if d == nil {
+		return 0, fmt.Errorf("%w: nil document", ErrInvalidNodeState)
+	}
+
+	return dedupeNodes(&d.Roots, policy)
+}
+
+// Dedupe collapses repeated keys among the children of an object node and
+// every object below them under policy, in place, and returns the number of nodes removed.
+func (n *Node) Dedupe(policy DedupePolicy) (int, error) {
+	if err := n.checkObject(); err != nil {
+		return 0, err
+	}
+
+	return dedupeNodes(&n.Children, policy)
+}
+
+// dedupeNodes deduplicates a sibling list and the objects below it using an explicit stack.
+func dedupeNodes(siblings *[]*Node, policy DedupePolicy) (int, error) {
+	if policy > DedupeMergeObjects {
+		return 0, fmt.Errorf("%w: %d", ErrInvalidDedupePolicy, policy)
+	}
+
+	removed := 0
+	stack := []*[]*Node{siblings}
+	for len(stack) > 0 {
+		list := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		removed += dedupeList(list, policy)
+		for _, node := range *list {
+			if node != nil && node.Kind == NodeObject {
+				stack = append(stack, &node.Children)
+			}
+		}
+	}
+
+	return removed, nil
+}
+
+// dedupeList collapses repeated keys of one sibling list and returns the number removed.
+func dedupeList(list *[]*Node, policy DedupePolicy) int {
+	first := make(map[string]int, len(*list))
+	out := make([]*Node, 0, len(*list))
+	for _, node := range *list {
+		if node == nil {
+			out = append(out, node)
+			continue
+		}
+
+		i, seen := first[node.Key]
+		if !seen {
+			first[node.Key] = len(out)
+			out = append(out, node)
+			continue
+		}
+
+		switch kept := out[i]; {
+		case policy == DedupeKeepFirst:
+		case policy == DedupeMergeObjects && kept.Kind == NodeObject && node.Kind == NodeObject:
+			kept.Children = append(kept.Children, node.Children...)
+		default:
+			out[i] = node
+		}
+	}
+
+	removed := len(*list) - len(out)
+	*list = out
+	return removed
+}
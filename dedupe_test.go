@@ -0,0 +1,57 @@
+package vdf
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDedupe(t *testing.T) {
+	t.Parallel()
+
+	const input = `"root" {
+		"name" "a"
+		"opts" { "x" "1" "y" "2" }
+		"name" "b"
+		"opts" { "x" "3" "z" "4" }
+	}`
+
+	cases := []struct {
+		name    string
+		policy  DedupePolicy
+		name1   string
+		opts    string
+		x       string
+		removed int
+	}{
+		{name: "first", policy: DedupeKeepFirst, name1: "a", opts: "x,y", x: "1", removed: 2},
+		{name: "last", policy: DedupeKeepLast, name1: "b", opts: "x,z", x: "3", removed: 2},
+		{name: "merge", policy: DedupeMergeObjects, name1: "b", opts: "x,y,z", x: "3", removed: 3},
+	}
+
+	for _, tc := range cases {
+		doc := mustParseString(t, input)
+		removed, err := doc.Dedupe(tc.policy)
+		if err != nil || removed != tc.removed {
+			t.Fatalf("Dedupe(%s) = %d, %v, want %d", tc.name, removed, err, tc.removed)
+		}
+
+		root := doc.Roots[0]
+		if childKeys(root) != "name,opts" || *root.First("name").StringValue != tc.name1 {
+			t.Fatalf("Dedupe(%s) root = %s", tc.name, childKeys(root))
+		}
+
+		opts := root.First("opts")
+		if childKeys(opts) != tc.opts || *opts.First("x").StringValue != tc.x {
+			t.Fatalf("Dedupe(%s) opts = %s, x = %s", tc.name, childKeys(opts), *opts.First("x").StringValue)
+		}
+	}
+
+	doc := mustParseString(t, input)
+	if _, err := doc.Dedupe(DedupeMergeObjects + 1); !errors.Is(err, ErrInvalidDedupePolicy) {
+		t.Fatalf("Dedupe(bad policy) error = %v, want ErrInvalidDedupePolicy", err)
+	}
+
+	if _, err := doc.Roots[0].First("name").Dedupe(DedupeKeepFirst); !errors.Is(err, ErrInvalidNodeState) {
+		t.Fatalf("Dedupe(leaf) error = %v, want ErrInvalidNodeState", err)
+	}
+}
@@ -12,7 +12,20 @@ The canonical model is an AST:
 
   - Document is a full file with ordered root nodes.
   - NodeObject keeps ordered children and allows duplicate keys.
-  - NodeString and NodeUint32 are scalar leaves.
+  - NodeString, NodeWideString, NodeUint32, NodeUint64, NodeInt64, NodeFloat32,
+    NodePointer and NodeColor are scalar leaves.
+  - NodeRaw keeps an unrecognized binary type byte and payload verbatim
+    (binary only, opt-in via DecodeOptions.PreserveUnknownTypes).
+  - NodeDirective is a root-level "#include" or "#base" line of text input,
+    with the directive name in Key and the referenced file in StringValue.
+
+Text entries may carry a platform conditional such as "[$WIN32]" after the
+value or before an object body; it is kept in Node.Condition and written back
+by the text encoder. Set DecodeOptions.Conditions to evaluate conditionals
+against a symbol set and drop entries whose conditional is false.
+
+Each leaf kind stores its payload in the matching typed Node field;
+Node.Value and Event.Value return it as a plain Go value for any kind.
 
 This preserves VDF semantics that are commonly lost in map-based APIs
 (ordering and duplicate keys).
@@ -28,10 +41,27 @@ For byte slices and strings use ParseBytes and ParseString.
 For file paths use ParseFile with optional DecodeOptions,
 or ParseTextFile/ParseAutoFile.
 
+Nesting depth is limited to DefaultMaxDepth unless DecodeOptions.MaxDepth
+is set; assign Unlimited to disable the limit for trusted input.
+
+Text "#include" and "#base" directives are kept as NodeDirective roots unless
+DecodeOptions.IncludeFS is set, in which case DecodeDocument loads the
+referenced files and merges them into the document:
+
+	doc, err := vdf.ParseBytes(data, vdf.DecodeOptions{
+		IncludeFS:   os.DirFS(gameDir),
+		IncludePath: "resource/ui/hud.res",
+	})
+
 NextEvent provides traversal events over the decoded document:
 
 	event, err := dec.NextEvent()
 
+Token returns the same events straight from the input without building a
+Document, for large files:
+
+	event, err := dec.Token()
+
 # Encode API
 
 Use Encoder for stream-oriented output to io.Writer:
@@ -41,6 +71,7 @@ Use Encoder for stream-oriented output to io.Writer:
 
 Manual streaming methods are available for incremental writing:
 StartObject, WriteString, WriteUint32, EndObject, Close.
+WriteEvent writes events from Token or NextEvent, for streaming transforms.
 For file output use WriteFile with optional EncodeOptions,
 or WriteTextFile/WriteBinaryFile.
 
@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Edit replaces one scalar value in raw VDF bytes.
+type Edit struct {
+	// Path addresses the leaf to change using Document path syntax.
+	Path string `json:"path" yaml:"path"`
+	// Value is the new scalar value.
+	// Binary numeric leaves require a decimal value that fits their type.
+	Value string `json:"value" yaml:"value"`
+}
+
+// EditBytes applies edits to src and returns the patched bytes.
+// Only the value bytes of edited leaves change; keys, comments, whitespace,
+// and all other entries are copied verbatim, so user files keep their formatting.
+// Format is detected like ParseBytes with FormatAuto.
+// In text input, unquoted values stay unquoted when the new value allows it.
+// A checksum trailer on binary input is not updated.
+func EditBytes(src []byte, edits []Edit) ([]byte, error) {
+	doc, err := ParseBytes(src, DecodeOptions{RecordSpans: true})
+	if err != nil {
+		return nil, err
+	}
+
+	type patch struct {
+		span        *Span
+		replacement []byte
+	}
+
+	patches := make([]patch, 0, len(edits))
+	for _, edit := range edits {
+		node, err := doc.lookupPath(edit.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		if node.Span == nil || !isEditableKind(node.Kind) {
+			return nil, fmt.Errorf("%w: %q is not a scalar value", ErrInvalidEdit, edit.Path)
+		}
+
+		var replacement []byte
+		if doc.Format == FormatBinary {
+			replacement, err = binaryEditValue(node, edit.Value)
+		} else {
+			replacement = textEditValue(src[node.Span.ValueStart:node.Span.ValueEnd], edit.Value)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("edit %q: %w", edit.Path, err)
+		}
+
+		patches = append(patches, patch{span: node.Span, replacement: replacement})
+	}
+
+	slices.SortFunc(patches, func(a, b patch) int {
+		return int(a.span.ValueStart - b.span.ValueStart)
+	})
+
+	out := make([]byte, 0, len(src))
+	var pos int64
+	for _, p := range patches {
+		if p.span.ValueStart < pos {
+			return nil, fmt.Errorf("%w: overlapping edits at offset %d", ErrInvalidEdit, p.span.ValueStart)
+		}
+
+		out = append(out, src[pos:p.span.ValueStart]...)
+		out = append(out, p.replacement...)
+		pos = p.span.ValueEnd
+	}
+
+	return append(out, src[pos:]...), nil
+}
+
+// isEditableKind reports whether leaves of kind can be patched in place.
+func isEditableKind(kind NodeKind) bool {
+	return kind.IsLeaf() && kind != NodeRaw
+}
+
+// textEditValue renders a replacement text token, keeping an unquoted original unquoted when safe.
+func textEditValue(original []byte, value string) []byte {
+	if len(original) > 0 && original[0] != '"' && isSafeUnquoted(value) {
+		return []byte(value)
+	}
+
+	return []byte(`"` + escapeString(value) + `"`)
+}
+
+// isSafeUnquoted reports whether value lexes back unchanged as an unquoted token.
+func isSafeUnquoted(value string) bool {
+	// A leading "[" would start a conditional.
+	if value == "" || strings.HasPrefix(value, "//") || value[0] == '[' {
+		return false
+	}
+
+	for _, r := range value {
+		// A backslash reads back literally, but Valve tools may treat it as an escape.
+		if isWhitespace(r) || r == '{' || r == '}' || r == '"' || r == '\\' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// binaryEditValue encodes a replacement payload for a binary leaf without its terminator.
+func binaryEditValue(node *Node, value string) ([]byte, error) {
+	if node.Kind == NodeUint32 || node.Kind == NodePointer {
+		number, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a uint32", ErrValueConversion, value)
+		}
+
+		return binary.LittleEndian.AppendUint32(nil, uint32(number)), nil
+	}
+
+	if node.Kind == NodeUint64 {
+		number, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a uint64", ErrValueConversion, value)
+		}
+
+		return binary.LittleEndian.AppendUint64(nil, number), nil
+	}
+
+	if node.Kind == NodeInt64 {
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not an int64", ErrValueConversion, value)
+		}
+
+		return binary.LittleEndian.AppendUint64(nil, uint64(number)), nil
+	}
+
+	if node.Kind == NodeFloat32 {
+		number, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a float32", ErrValueConversion, value)
+		}
+
+		return binary.LittleEndian.AppendUint32(nil, math.Float32bits(float32(number))), nil
+	}
+
+	if node.Kind == NodeColor {
+		color, err := parseColor(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return []byte{color.R, color.G, color.B, color.A}, nil
+	}
+
+	if strings.IndexByte(value, 0) >= 0 {
+		return nil, ErrNullInString
+	}
+
+	if node.Kind == NodeWideString {
+		var buf bytes.Buffer
+		if err := writeWideString(&buf, value); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes()[:buf.Len()-2], nil
+	}
+
+	return []byte(value), nil
+}
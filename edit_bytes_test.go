@@ -0,0 +1,95 @@
+package vdf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEditBytesText(t *testing.T) {
+	t.Parallel()
+
+	input := "// user config\n\"cfg\"\n{\n  \"name\"   \"old\"  // keep\n\tlevel 3\n\t\"item\" \"a\"\n\t\"item\" \"b\"\n}\n"
+	got, err := EditBytes([]byte(input), []Edit{
+		{Path: "cfg/item[1]", Value: `say "hi"`},
+		{Path: "cfg/name", Value: "new"},
+		{Path: "cfg/level", Value: "10"},
+	})
+	if err != nil {
+		t.Fatalf("EditBytes() returned error: %v", err)
+	}
+
+	want := "// user config\n\"cfg\"\n{\n  \"name\"   \"new\"  // keep\n\tlevel 10\n\t\"item\" \"a\"\n\t\"item\" \"say \\\"hi\\\"\"\n}\n"
+	if string(got) != want {
+		t.Fatalf("EditBytes() = %q, want %q", got, want)
+	}
+
+	got, err = EditBytes([]byte(input), []Edit{{Path: "cfg/level", Value: "two words"}})
+	if err != nil {
+		t.Fatalf("EditBytes() returned error: %v", err)
+	}
+
+	if want := "\tlevel \"two words\"\n"; !strings.Contains(string(got), want) {
+		t.Fatalf("EditBytes() = %q, want it to contain %q", got, want)
+	}
+
+	if _, err := EditBytes([]byte(input), []Edit{{Path: "cfg", Value: "x"}}); !errors.Is(err, ErrInvalidEdit) {
+		t.Fatalf("EditBytes(object) error=%v, want ErrInvalidEdit", err)
+	}
+
+	if _, err := EditBytes([]byte(input), []Edit{{Path: "cfg/name", Value: "a"}, {Path: "cfg/name", Value: "b"}}); !errors.Is(err, ErrInvalidEdit) {
+		t.Fatalf("EditBytes(duplicate) error=%v, want ErrInvalidEdit", err)
+	}
+
+	if _, err := EditBytes([]byte(input), []Edit{{Path: "cfg/missing", Value: "x"}}); !errors.Is(err, ErrPathNotFound) {
+		t.Fatalf("EditBytes(missing) error=%v, want ErrPathNotFound", err)
+	}
+}
+
+func TestEditBytesBinary(t *testing.T) {
+	t.Parallel()
+
+	doc := NewDocumentWithFormat(FormatBinary)
+	root := NewObjectNode("shortcuts")
+	entry := NewObjectNode("0")
+	entry.Add(NewStringNode("AppName", "Game"))
+	entry.Add(NewUint32Node("appid", 1))
+	entry.Add(NewStringNode("Exe", "game.exe"))
+	root.Add(entry)
+	doc.AddRoot(root)
+
+	src, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	got, err := EditBytes(src, []Edit{
+		{Path: "shortcuts/0/AppName", Value: "Renamed Game"},
+		{Path: "shortcuts/0/appid", Value: "4278190081"},
+	})
+	if err != nil {
+		t.Fatalf("EditBytes() returned error: %v", err)
+	}
+
+	edited, err := ParseBytes(got, DecodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("ParseBytes() returned error: %v", err)
+	}
+
+	node := edited.Roots[0].First("0")
+	if value := *node.First("AppName").StringValue; value != "Renamed Game" {
+		t.Fatalf("AppName = %q, want %q", value, "Renamed Game")
+	}
+
+	if value := *node.First("appid").Uint32Value; value != 0xFF000001 {
+		t.Fatalf("appid = %#x, want %#x", value, 0xFF000001)
+	}
+
+	if value := *node.First("Exe").StringValue; value != "game.exe" {
+		t.Fatalf("Exe = %q, want %q", value, "game.exe")
+	}
+
+	if _, err := EditBytes(src, []Edit{{Path: "shortcuts/0/appid", Value: "-1"}}); !errors.Is(err, ErrValueConversion) {
+		t.Fatalf("EditBytes(bad uint32) error=%v, want ErrValueConversion", err)
+	}
+}
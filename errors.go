@@ -4,7 +4,10 @@
 
 package vdf
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrInvalidFormat indicates unsupported format selection.
@@ -27,10 +30,48 @@ var (
 	ErrDepthLimitExceeded = errors.New("maximum depth exceeded")
 	// ErrNodeLimitExceeded indicates decode exceeded configured max node count.
 	ErrNodeLimitExceeded = errors.New("maximum node count exceeded")
+	// ErrInputLimitExceeded indicates decode input longer than DecodeOptions.MaxInputBytes.
+	ErrInputLimitExceeded = errors.New("maximum input size exceeded")
+	// ErrStringLimitExceeded indicates a decoded key or string longer than MaxKeyBytes or MaxStringBytes.
+	ErrStringLimitExceeded = errors.New("maximum string size exceeded")
+	// ErrChecksumMismatch indicates that a binary payload CRC32 does not match its trailer.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// ErrInvalidPath indicates a malformed slash-delimited node path.
+	ErrInvalidPath = errors.New("invalid node path")
+	// ErrPathNotFound indicates that a node path does not address an existing node.
+	ErrPathNotFound = errors.New("node path not found")
+	// ErrPathNotObject indicates that a node path traverses through a non-object node.
+	ErrPathNotObject = errors.New("node path segment is not an object")
+	// ErrInvalidMergeStrategy indicates an unknown MergeStrategy value.
+	ErrInvalidMergeStrategy = errors.New("invalid merge strategy")
+	// ErrInvalidSetDuplicates indicates an unknown SetDuplicates value.
+	ErrInvalidSetDuplicates = errors.New("invalid set duplicates mode")
+	// ErrInvalidDedupePolicy indicates an unknown DedupePolicy value.
+	ErrInvalidDedupePolicy = errors.New("invalid dedupe policy")
+	// ErrInvalidPatch indicates a patch operation that cannot be applied to the document.
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrChildNotFound indicates that a referenced node is not a child of the object.
+	ErrChildNotFound = errors.New("child node not found")
+	// ErrIndexOutOfRange indicates that a child index is outside the object children.
+	ErrIndexOutOfRange = errors.New("child index out of range")
+	// ErrUnsupportedGoType indicates a Go type that cannot be mapped to VDF nodes.
+	ErrUnsupportedGoType = errors.New("unsupported Go type")
+	// ErrValueConversion indicates a node value that cannot be converted to or from a Go value.
+	ErrValueConversion = errors.New("value conversion failed")
+	// ErrInvalidJSON indicates JSON input that cannot be converted to a document.
+	ErrInvalidJSON = errors.New("invalid JSON document")
+	// ErrRoundTripMismatch indicates that re-encoded data decodes to a different document.
+	ErrRoundTripMismatch = errors.New("round-trip mismatch")
+	// ErrInvalidEdit indicates a raw byte edit that targets a non-scalar node or overlaps another edit.
+	ErrInvalidEdit = errors.New("invalid edit")
 	// ErrUnexpectedEOFInQuotedString indicates that a quoted text token ended before its closing quote.
 	ErrUnexpectedEOFInQuotedString = errors.New("unexpected EOF in quoted string")
 	// ErrUnexpectedEOFInEscapeSequence indicates that an escape sequence ended before its escaped rune.
 	ErrUnexpectedEOFInEscapeSequence = errors.New("unexpected EOF in escape sequence")
+	// ErrUnexpectedEOFInCondition indicates that a "[...]" conditional ended before its closing bracket.
+	ErrUnexpectedEOFInCondition = errors.New("unexpected EOF in conditional")
+	// ErrInvalidUTF8 indicates an invalid UTF-8 byte in a text token under InvalidUTF8Reject.
+	ErrInvalidUTF8 = errors.New("invalid UTF-8")
 	// ErrUnexpectedCharacter indicates that the lexer found an invalid token start.
 	ErrUnexpectedCharacter = errors.New("unexpected character")
 	// ErrExpectedStringKey indicates that the parser expected a string token for a node key.
@@ -39,6 +80,71 @@ var (
 	ErrExpectedValueOrObject = errors.New("expected value or '{'")
 	// ErrExpectedObjectStart indicates that the parser expected an opening object brace.
 	ErrExpectedObjectStart = errors.New("expected '{'")
+	// ErrInvalidAppInfo indicates a truncated or malformed appinfo.vdf or packageinfo.vdf container.
+	ErrInvalidAppInfo = errors.New("invalid appinfo container")
+	// ErrUnsupportedAppInfoVersion indicates an appinfo.vdf or packageinfo.vdf container version this package cannot decode.
+	ErrUnsupportedAppInfoVersion = errors.New("unsupported appinfo version")
+	// ErrStringPoolIndex indicates a pooled binary key index outside DecodeOptions.StringPool.
+	ErrStringPoolIndex = errors.New("string pool index out of range")
+	// ErrIncludeCycle indicates an "#include" or "#base" directive that refers back to a file being resolved.
+	ErrIncludeCycle = errors.New("include cycle")
 	// ErrUnexpectedEOFInObject indicates that the parser reached EOF before closing an object.
 	ErrUnexpectedEOFInObject = errors.New("unexpected EOF, expected '}'")
 )
+
+// ParseError reports a text decode error at its source position.
+// Offset counts bytes of the UTF-8 text after byte order mark removal
+// and UTF-16LE transcoding.
+type ParseError struct {
+	Err    error // Underlying error, usually one of the Err* sentinels.
+	Offset int64 // Byte offset of the error.
+	Line   int   // 1-based line number.
+	Col    int   // 1-based column number.
+}
+
+// Error formats the error with its line and column.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%v at line %d, col %d", e.Err, e.Line, e.Col)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// BinaryError reports a binary decode error at the input byte offset where decoding stopped.
+type BinaryError struct {
+	Err    error // Underlying error, usually one of the Err* sentinels.
+	Offset int64 // Input bytes consumed when the error occurred.
+}
+
+// Error formats the error with its byte offset.
+func (e *BinaryError) Error() string {
+	return fmt.Sprintf("%v at offset %d", e.Err, e.Offset)
+}
+
+// Unwrap returns the underlying error.
+func (e *BinaryError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorList holds the errors recovered while decoding with DecodeOptions.Recover,
+// in input order. It is returned with the best-effort Document.
+type ErrorList []error
+
+// Error reports the first error and the number of further errors.
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	default:
+		return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+	}
+}
+
+// Unwrap returns the recovered errors for errors.Is and errors.As.
+func (l ErrorList) Unwrap() []error {
+	return l
+}
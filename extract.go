@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+// ToDocument returns a new document with format marker whose only root is a deep copy of n.
+func (n *Node) ToDocument(format Format) *Document {
+	doc := NewDocumentWithFormat(format)
+	doc.AddRoot(cloneNode(n))
+
+	return doc
+}
+
+// Extract returns a new document rooted at the node addressed by path.
+// The subtree is deep-copied unless share is true, in which case the new
+// document references the same nodes and edits are visible in both.
+// The extracted document keeps the format marker of d.
+func (d *Document) Extract(path string, share bool) (*Document, error) {
+	node, err := d.lookupPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !share {
+		return node.ToDocument(d.Format), nil
+	}
+
+	doc := NewDocumentWithFormat(d.Format)
+	doc.AddRoot(node)
+	return doc, nil
+}
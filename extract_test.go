@@ -0,0 +1,44 @@
+package vdf
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDocumentExtract(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"shortcuts" { "0" { "AppName" "A" } "1" { "AppName" "B" } }`)
+
+	copied, err := doc.Extract("shortcuts/1", false)
+	if err != nil {
+		t.Fatalf("Extract(copy) returned error: %v", err)
+	}
+
+	if copied.Format != FormatText || len(copied.Roots) != 1 || copied.Roots[0].Key != "1" {
+		t.Fatalf("Extract(copy) = %+v, want one root \"1\"", copied)
+	}
+
+	copied.Roots[0].Children[0].Key = "changed"
+	if doc.Roots[0].Children[1].Children[0].Key != "AppName" {
+		t.Fatalf("Extract(copy) must not share nodes")
+	}
+
+	shared, err := doc.Extract("shortcuts/0", true)
+	if err != nil {
+		t.Fatalf("Extract(share) returned error: %v", err)
+	}
+
+	if shared.Roots[0] != doc.Roots[0].Children[0] {
+		t.Fatalf("Extract(share) must reference source node")
+	}
+
+	if _, err := doc.Extract("shortcuts/9", false); !errors.Is(err, ErrPathNotFound) {
+		t.Fatalf("Extract(missing) error = %v, want ErrPathNotFound", err)
+	}
+
+	single := doc.Roots[0].ToDocument(FormatBinary)
+	if single.Format != FormatBinary || single.Roots[0] == doc.Roots[0] {
+		t.Fatalf("ToDocument() must deep-copy with requested format")
+	}
+}
@@ -0,0 +1,335 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// TextLayout is the source text around one text node, or after the last root,
+// recorded with DecodeOptions.Fidelity and used by EncodeOptions.Fidelity.
+// It is opaque and never modified, so node copies may share it.
+type TextLayout struct {
+	leading   string   // Whitespace and comments before the key.
+	rawKey    string   // Key token as written.
+	separator string   // Text between the key and the value or "{", including a leading conditional.
+	rawValue  string   // Scalar value token as written; empty for objects.
+	suffix    string   // Text from the value end through a trailing conditional.
+	closing   string   // Text before the closing "}" of an object, or after the last root.
+	key       string   // Decoded key, to detect edits.
+	value     string   // Decoded scalar value, to detect edits.
+	condition string   // Decoded conditional, to detect edits.
+	kind      NodeKind // Decoded kind, to detect edits.
+}
+
+// entryLayout captures the source text of a parsed entry.
+// For scalar entries the parser has already consumed any trailing conditional.
+func (p *textParser) entryLayout(entry textEntry, leadingStart int64, keyTok, valueTok textToken) *TextLayout {
+	layout := &TextLayout{
+		leading:   string(p.source[leadingStart:keyTok.start]),
+		rawKey:    string(p.source[keyTok.start:keyTok.end]),
+		separator: string(p.source[keyTok.end:valueTok.start]),
+		key:       entry.key,
+		value:     entry.value,
+		condition: entry.condition,
+		kind:      entry.kind,
+	}
+
+	if entry.kind != NodeObject {
+		layout.rawValue = string(p.source[valueTok.start:valueTok.end])
+		layout.suffix = string(p.source[valueTok.end:p.lastEnd])
+	}
+
+	return layout
+}
+
+// fidelityFrame is one open object during fidelity encoding.
+type fidelityFrame struct {
+	node   *Node  // Object being written.
+	indent string // Indentation for generated children.
+	index  int    // Next child index.
+}
+
+// fidelityWriter writes text output from recorded layouts.
+type fidelityWriter struct {
+	w       io.Writer     // Destination stream.
+	newline string        // Line break for generated lines, following the source.
+	opts    EncodeOptions // Encode options.
+	written bool          // Whether any output was written.
+}
+
+// encodeFidelityDocument writes doc reusing recorded layouts.
+// Traversal uses an explicit stack like encodeTextNode.
+func encodeFidelityDocument(w io.Writer, doc *Document, opts EncodeOptions) error {
+	fw := &fidelityWriter{w: w, newline: layoutNewline(doc), opts: opts}
+	for _, root := range doc.Roots {
+		if err := fw.writeTree(root, childIndent(doc.Roots, "", "")); err != nil {
+			return err
+		}
+	}
+
+	closing := fw.newline
+	if doc.Layout != nil {
+		closing = doc.Layout.closing
+	} else if !fw.written {
+		closing = ""
+	}
+
+	return fw.write(closing)
+}
+
+// writeTree writes one root subtree; indent applies to generated lines of the root itself.
+func (fw *fidelityWriter) writeTree(root *Node, indent string) error {
+	if err := fw.writeOpen(root, indent); err != nil {
+		return err
+	}
+
+	if root.Kind != NodeObject {
+		return nil
+	}
+
+	seen := map[*Node]struct{}{root: {}}
+	stack := []fidelityFrame{{node: root, indent: childIndent(root.Children, indent, fw.opts.Indent)}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.index < len(top.node.Children) {
+			child := top.node.Children[top.index]
+			top.index++
+
+			if err := fw.writeOpen(child, top.indent); err != nil {
+				return err
+			}
+
+			if child.Kind == NodeObject {
+				if _, cyclic := seen[child]; cyclic {
+					return fmt.Errorf("%w: cyclic node %q", ErrInvalidNodeState, child.Key)
+				}
+
+				seen[child] = struct{}{}
+				stack = append(stack, fidelityFrame{node: child, indent: childIndent(child.Children, top.indent, fw.opts.Indent)})
+			}
+
+			continue
+		}
+
+		// The closed object was written with its parent frame's child indentation.
+		closed := top.node
+		ownIndent := indent
+		if len(stack) > 1 {
+			ownIndent = stack[len(stack)-2].indent
+		}
+
+		delete(seen, closed)
+		stack = stack[:len(stack)-1]
+
+		closing := fw.newline + ownIndent
+		if layout := shapeLayout(closed); layout != nil {
+			closing = layout.closing
+		}
+
+		if err := fw.write(closing + "}"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeOpen writes a leaf or directive entry, or an object header through "{".
+func (fw *fidelityWriter) writeOpen(node *Node, indent string) error {
+	if node == nil {
+		return fmt.Errorf("%w: nil node", ErrInvalidNodeState)
+	}
+
+	layout := node.Layout
+	var sb strings.Builder
+
+	switch {
+	case layout != nil:
+		sb.WriteString(layout.leading)
+	case fw.written:
+		sb.WriteString(fw.newline + indent)
+	default:
+		sb.WriteString(indent)
+	}
+
+	rawKey := ""
+	if layout != nil {
+		rawKey = layout.rawKey
+		if node.Key != layout.key {
+			edited, err := fw.editText(rawKey, node.Key)
+			if err != nil {
+				return err
+			}
+
+			rawKey = edited
+		}
+	}
+
+	switch {
+	case rawKey != "":
+		sb.WriteString(rawKey)
+	case node.Kind == NodeDirective:
+		sb.WriteString(node.Key)
+	default:
+		key, err := quoteText(node.Key, node.KeyUnquoted, fw.opts)
+		if err != nil {
+			return err
+		}
+
+		sb.WriteString(key)
+	}
+
+	shape := shapeLayout(node)
+	conditionKept := shape != nil && shape.condition == node.Condition
+
+	if node.Kind == NodeObject {
+		if conditionKept {
+			sb.WriteString(shape.separator)
+		} else {
+			sb.WriteString(conditionSuffix(node.Condition) + fw.newline + indent)
+		}
+
+		sb.WriteByte('{')
+		return fw.write(sb.String())
+	}
+
+	value, err := textValueForNode(node)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case conditionKept:
+		sb.WriteString(shape.separator)
+	case shape != nil && !strings.Contains(shape.separator, "["):
+		sb.WriteString(shape.separator)
+	case node.Kind == NodeDirective:
+		sb.WriteByte(' ')
+	default:
+		sb.WriteString("\t\t")
+	}
+
+	switch {
+	case shape == nil:
+		value, err = quoteText(value, node.ValueUnquoted, fw.opts)
+	case value == shape.value:
+		value = shape.rawValue
+	default:
+		value, err = fw.editText(shape.rawValue, value)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	sb.WriteString(value)
+
+	if conditionKept {
+		sb.WriteString(shape.suffix)
+	} else {
+		sb.WriteString(conditionSuffix(node.Condition))
+	}
+
+	return fw.write(sb.String())
+}
+
+// editText renders an edited token, keeping an unquoted original unquoted when safe like EditBytes.
+func (fw *fidelityWriter) editText(raw, value string) (string, error) {
+	unquoted := raw != "" && raw[0] != '"'
+	return quoteText(value, unquoted, EncodeOptions{QuoteStyle: QuotePreserve, DisableEscapes: fw.opts.DisableEscapes})
+}
+
+// write emits text and tracks whether anything was written.
+func (fw *fidelityWriter) write(text string) error {
+	if text == "" {
+		return nil
+	}
+
+	fw.written = true
+	_, err := io.WriteString(fw.w, text)
+	return err
+}
+
+// shapeLayout returns the node layout when it was recorded for the same node shape,
+// so separators and values written for a leaf are not reused for an object or vice versa.
+func shapeLayout(node *Node) *TextLayout {
+	layout := node.Layout
+	if layout == nil {
+		return nil
+	}
+
+	if (layout.kind == NodeObject) != (node.Kind == NodeObject) || (layout.kind == NodeDirective) != (node.Kind == NodeDirective) {
+		return nil
+	}
+
+	return layout
+}
+
+// childIndent returns the indentation for generated entries among siblings:
+// the indentation of the first sibling with a recorded layout, or parent plus unit.
+func childIndent(siblings []*Node, parent, unit string) string {
+	for _, sibling := range siblings {
+		if sibling == nil || sibling.Layout == nil {
+			continue
+		}
+
+		leading := sibling.Layout.leading
+		if i := strings.LastIndexByte(leading, '\n'); i >= 0 {
+			return leading[i+1:]
+		}
+	}
+
+	return parent + unit
+}
+
+// layoutNewline returns "\r\n" when the first line break recorded in doc layouts is CRLF, else "\n".
+func layoutNewline(doc *Document) string {
+	// Nodes are visited once, so cyclic documents reach writeTree and its cycle error.
+	seen := make(map[*Node]struct{})
+	stack := slices.Clone(doc.Roots)
+	for len(stack) > 0 {
+		node := stack[0]
+		stack = stack[1:]
+		if node == nil {
+			continue
+		}
+
+		if _, ok := seen[node]; ok {
+			continue
+		}
+		seen[node] = struct{}{}
+
+		if node.Layout != nil {
+			for _, text := range [...]string{node.Layout.leading, node.Layout.separator, node.Layout.closing} {
+				if i := strings.IndexByte(text, '\n'); i >= 0 {
+					return lineBreakAt(text, i)
+				}
+			}
+		}
+
+		stack = append(stack, node.Children...)
+	}
+
+	if doc.Layout != nil {
+		if i := strings.IndexByte(doc.Layout.closing, '\n'); i >= 0 {
+			return lineBreakAt(doc.Layout.closing, i)
+		}
+	}
+
+	return "\n"
+}
+
+// lineBreakAt returns the line break ending at the '\n' at index i of text.
+func lineBreakAt(text string, i int) string {
+	if i > 0 && text[i-1] == '\r' {
+		return "\r\n"
+	}
+
+	return "\n"
+}
@@ -0,0 +1,96 @@
+package vdf
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFidelityRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	input := "// Generated by hand\r\n" +
+		"\"AppState\"\r\n" +
+		"{\r\n" +
+		"    appid    440   // comment\r\n" +
+		"\r\n" +
+		"    \"name\" \"Team Fortress 2\" [$WIN32]\r\n" +
+		"    UserConfig [!$OSX] {\r\n" +
+		"        language english\r\n" +
+		"    }\r\n" +
+		"}\r\n" +
+		"\r\n"
+
+	doc, err := ParseBytes([]byte(input), DecodeOptions{Format: FormatText, Fidelity: true})
+	if err != nil {
+		t.Fatalf("ParseBytes() returned error: %v", err)
+	}
+
+	opts := EncodeOptions{Format: FormatText, Fidelity: true}
+	out, err := AppendText(nil, doc, opts)
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	if string(out) != input {
+		t.Fatalf("unedited output differs:\n%q\nwant\n%q", out, input)
+	}
+
+	if _, err := doc.SetPath("AppState/appid", "730"); err != nil {
+		t.Fatalf("SetPath() returned error: %v", err)
+	}
+
+	if _, err := doc.SetPath("AppState/name", "Counter-Strike 2"); err != nil {
+		t.Fatalf("SetPath() returned error: %v", err)
+	}
+
+	doc.Roots[0].First("UserConfig").Add(NewStringNode("beta", "public"))
+
+	out, err = AppendText(nil, doc, opts)
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	want := strings.NewReplacer(
+		"appid    440", "appid    730",
+		"\"Team Fortress 2\"", "\"Counter-Strike 2\"",
+		"language english\r\n", "language english\r\n        \"beta\"\t\t\"public\"\r\n",
+	).Replace(input)
+	if string(out) != want {
+		t.Fatalf("edited output differs:\n%q\nwant\n%q", out, want)
+	}
+}
+
+func TestFidelityWithoutLayout(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"root" { "a" "1" "child" [$WIN32] { "b" "2" } } "second" "x"`)
+	out, err := AppendText(nil, doc, EncodeOptions{Format: FormatText, Fidelity: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	want := "\"root\"\n{\n\t\"a\"\t\t\"1\"\n\t\"child\" [$WIN32]\n\t{\n\t\t\"b\"\t\t\"2\"\n\t}\n}\n\"second\"\t\t\"x\"\n"
+	if string(out) != want {
+		t.Fatalf("AppendText() = %q, want %q", out, want)
+	}
+}
+
+func TestFidelityCyclicDocumentFails(t *testing.T) {
+	t.Parallel()
+
+	root := NewObjectNode("root")
+	child := NewObjectNode("child")
+	root.Add(child)
+	child.Add(root)
+
+	doc := NewDocument()
+	doc.AddRoot(root)
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf, EncodeOptions{Format: FormatText, Fidelity: true}).EncodeDocument(doc)
+	if !errors.Is(err, ErrInvalidNodeState) {
+		t.Fatalf("EncodeDocument(cyclic, fidelity) error = %v, want ErrInvalidNodeState", err)
+	}
+}
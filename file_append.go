@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// AppendToFile appends roots as new top-level entries to an existing text VDF file
+// without rewriting its content.
+// The file is validated first and must be UTF-8 text VDF, since roots are
+// encoded as UTF-8; UTF-16 files fail with ErrInvalidFormat.
+// Trailing whitespace is replaced by the blank-line separator the text encoder
+// puts between roots.
+// An empty or missing file receives the roots alone.
+func AppendToFile(path string, roots ...*Node) (err error) {
+	appended := &Document{Roots: roots, Format: FormatText}
+	if err := appended.Validate(); err != nil {
+		return err
+	}
+
+	encoded, err := AppendText(nil, appended, EncodeOptions{Format: FormatText})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close file: %w", cerr)
+		}
+	}()
+
+	if err := rejectUTF16(f); err != nil {
+		return err
+	}
+
+	if err := validateTextStream(f); err != nil {
+		return err
+	}
+
+	end, err := contentEnd(f)
+	if err != nil {
+		return err
+	}
+
+	if end > 0 {
+		encoded = append([]byte("\n\n"), encoded...)
+	}
+
+	if err := f.Truncate(end); err != nil {
+		return fmt.Errorf("failed to truncate file: %w", err)
+	}
+
+	if _, err := f.WriteAt(encoded, end); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertBinaryEntry stores entry as a child of the object at parentPath in a
+// binary VDF file, replacing the first child with the same key or appending
+// it before the object's map end marker.
+// Only the entry bytes and the file tail after them are written, so adding one
+// shortcut to shortcuts.vdf does not re-encode the other entries.
+// Sibling subtrees are scanned but not built into memory.
+// The CRC32 in a VBKV header is recomputed over the new body;
+// a checksum trailer on the file is not updated.
+func UpsertBinaryEntry(path, parentPath string, entry *Node) (err error) {
+	if err := (&Document{Roots: []*Node{entry}}).Validate(); err != nil {
+		return err
+	}
+
+	segments, err := parsePath(parentPath)
+	if err != nil {
+		return err
+	}
+
+	encoded := &sliceWriter{buf: make([]byte, 0, estimateBinaryNodeSize(entry))}
+	if err := encodeBinaryNode(encoded, entry, EncodeOptions{Format: FormatBinary}); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("failed to close file: %w", cerr)
+		}
+	}()
+
+	// Keep only the parent chain and same-key children so spans locate the framing.
+	doc, err := NewDecoder(f, DecodeOptions{
+		Format:      FormatBinary,
+		RecordSpans: true,
+		NodeFilter: func(path []string, key string, kind NodeKind) bool {
+			if depth := len(path); depth < len(segments) {
+				return kind == NodeObject && key == segments[depth].key
+			}
+
+			return len(path) == len(segments) && key == entry.Key
+		},
+	}).DecodeDocument()
+	if err != nil {
+		return err
+	}
+
+	parent, err := doc.lookupPath(parentPath)
+	if err != nil {
+		return err
+	}
+
+	if parent.Kind != NodeObject {
+		return fmt.Errorf("%w: %q is not an object", ErrPathNotObject, parentPath)
+	}
+
+	// Without a match the entry goes right before the parent's map end marker.
+	start, end := parent.Span.ValueEnd-1, parent.Span.ValueEnd-1
+	if existing := parent.First(entry.Key); existing != nil {
+		// The entry starts at its type byte, one byte before the key.
+		start, end = existing.Span.KeyStart-1, existing.Span.ValueEnd
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	tail := make([]byte, info.Size()-end)
+	if _, err := f.ReadAt(tail, end); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if _, err := f.WriteAt(append(encoded.buf, tail...), start); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	size := start + int64(len(encoded.buf)+len(tail))
+	if err := f.Truncate(size); err != nil {
+		return fmt.Errorf("failed to truncate file: %w", err)
+	}
+
+	return updateVBKVChecksum(f, size)
+}
+
+// validateTextStream checks that r holds well-formed text VDF without building an AST.
+func validateTextStream(r io.Reader) error {
+	stream, err := openStreamReader(r, DecodeOptions{})
+	if err != nil {
+		return err
+	}
+
+	if stream.format != FormatText {
+		return fmt.Errorf("%w: expected text input", ErrInvalidFormat)
+	}
+
+	for {
+		if _, err := stream.next(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+	}
+}
+
+// rejectUTF16 fails when f starts with a UTF-16LE byte order mark.
+func rejectUTF16(f *os.File) error {
+	var prefix [2]byte
+	n, err := f.ReadAt(prefix[:], 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if bytes.Equal(prefix[:n], utf16LEBOM) {
+		return fmt.Errorf("%w: cannot append UTF-8 text to a UTF-16 file", ErrInvalidFormat)
+	}
+
+	return nil
+}
+
+// contentEnd returns the file size without trailing ASCII whitespace.
+func contentEnd(f *os.File) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	end := info.Size()
+	var chunk [512]byte
+	for end > 0 {
+		n := min(end, int64(len(chunk)))
+		if _, err := f.ReadAt(chunk[:n], end-n); err != nil {
+			return 0, fmt.Errorf("failed to read file: %w", err)
+		}
+
+		for i := n - 1; i >= 0; i-- {
+			if !isWhitespace(rune(chunk[i])) {
+				return end - n + i + 1, nil
+			}
+		}
+
+		end -= n
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"errors"
+	"io"
+)
+
+// Filter copies entries whose path matches pred from src to dst in the input format.
+// The path passed to pred holds keys from the root down to the entry itself;
+// it is reused between calls and must be copied to be retained.
+// A matching object is copied with its whole subtree, and enclosing objects of
+// matches are emitted so output stays well-formed.
+// Input is streamed, so memory use is bounded by nesting depth, not input size.
+func Filter(dst io.Writer, src io.Reader, pred func(path []string) bool, opts DecodeOptions) error {
+	stream, err := openStreamReader(src, opts)
+	if err != nil {
+		return err
+	}
+
+	enc := NewEncoder(dst, EncodeOptions{Format: stream.format})
+
+	path := make([]string, 0, 8)
+	conditions := make([]string, 0, 8) // Text conditionals of the objects in path.
+	opened := 0                        // Number of path objects already written to output.
+	matchDepth := 0                    // Path length of the matched object being copied (0 means none).
+
+	// openPath writes enclosing objects of a match that were not emitted yet.
+	openPath := func(until int) error {
+		for ; opened < until; opened++ {
+			if err := enc.startObject(path[opened], conditions[opened]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for {
+		event, err := stream.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		switch event.Type {
+		case EventObjectStart:
+			path = append(path, event.Key)
+			conditions = append(conditions, event.Condition)
+			if matchDepth == 0 && pred(path) {
+				matchDepth = len(path)
+			}
+
+			if matchDepth > 0 {
+				if err := openPath(len(path)); err != nil {
+					return err
+				}
+			}
+
+		case EventObjectEnd:
+			if opened == len(path) {
+				if err := enc.EndObject(); err != nil {
+					return err
+				}
+
+				opened--
+			}
+
+			if matchDepth == len(path) {
+				matchDepth = 0
+			}
+
+			path = path[:len(path)-1]
+			conditions = conditions[:len(conditions)-1]
+
+		default:
+			path = append(path, event.Key)
+			matched := matchDepth > 0 || pred(path)
+			path = path[:len(path)-1]
+
+			if !matched {
+				continue
+			}
+
+			if err := openPath(len(path)); err != nil {
+				return err
+			}
+
+			if err := enc.WriteEvent(event); err != nil {
+				return err
+			}
+		}
+	}
+
+	return enc.Close()
+}
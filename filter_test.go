@@ -0,0 +1,79 @@
+package vdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	input := `"root" { "apps" { "440" { "name" "tf2" "size" "1" } "570" { "name" "dota" } } "other" "x" } "tail" "y"`
+	pred := func(path []string) bool {
+		return len(path) == 3 && path[1] == "apps" && path[2] == "440" ||
+			len(path) == 4 && path[3] == "name"
+	}
+
+	var out bytes.Buffer
+	if err := Filter(&out, strings.NewReader(input), pred, DecodeOptions{Format: FormatText}); err != nil {
+		t.Fatalf("Filter(text) returned error: %v", err)
+	}
+
+	doc, err := ParseString(out.String())
+	if err != nil {
+		t.Fatalf("ParseString(filtered) returned error: %v\n%s", err, out.String())
+	}
+
+	got, err := AppendText(nil, doc, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	want := `"root" { "apps" { "440" { "name" "tf2" "size" "1" } "570" { "name" "dota" } } } `
+	if string(got) != want {
+		t.Fatalf("filtered text = %q, want %q", got, want)
+	}
+
+	bin, err := AppendBinary(nil, mustParseString(t, input), EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	out.Reset()
+	if err := Filter(&out, bytes.NewReader(bin), pred, DecodeOptions{}); err != nil {
+		t.Fatalf("Filter(binary) returned error: %v", err)
+	}
+
+	doc, err = ParseBytes(out.Bytes(), DecodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("ParseBytes(filtered binary) returned error: %v", err)
+	}
+
+	got, err = AppendText(nil, doc, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	if string(got) != want {
+		t.Fatalf("filtered binary = %q, want %q", got, want)
+	}
+
+	input = `"root" [$OSX] { "sub" [$WIN32] { "pass" "x" [$X360] } "skip" "y" }`
+	pred = func(path []string) bool { return len(path) == 3 && path[2] == "pass" }
+
+	out.Reset()
+	if err := Filter(&out, strings.NewReader(input), pred, DecodeOptions{Format: FormatText}); err != nil {
+		t.Fatalf("Filter(conditional) returned error: %v", err)
+	}
+
+	got, err = AppendText(nil, mustParseString(t, out.String()), EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	want = `"root" [$OSX] { "sub" [$WIN32] { "pass" "x" [$X360] } } `
+	if string(got) != want {
+		t.Fatalf("filtered conditional = %q, want %q", got, want)
+	}
+}
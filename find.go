@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+// Find returns the first node below n in depth-first pre-order for which pred
+// reports true, or nil.
+func (n *Node) Find(pred func(*Node) bool) *Node {
+	for node := range n.Descendants() {
+		if pred(node) {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// FindAll returns all nodes below n for which pred reports true, in depth-first pre-order.
+func (n *Node) FindAll(pred func(*Node) bool) []*Node {
+	var matches []*Node
+	for node := range n.Descendants() {
+		if pred(node) {
+			matches = append(matches, node)
+		}
+	}
+
+	return matches
+}
+
+// Find returns the first document node in depth-first pre-order for which pred
+// reports true, or nil.
+func (d *Document) Find(pred func(*Node) bool) *Node {
+	for node := range d.Nodes() {
+		if pred(node) {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// FindAll returns all document nodes for which pred reports true, in depth-first pre-order.
+func (d *Document) FindAll(pred func(*Node) bool) []*Node {
+	var matches []*Node
+	for node := range d.Nodes() {
+		if pred(node) {
+			matches = append(matches, node)
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,34 @@
+package vdf
+
+import "testing"
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"users" { "1" { "name" "alice" } "2" { "name" "bob" } "3" { "name" "bob" } }`)
+	isBob := func(node *Node) bool {
+		return node.StringValue != nil && *node.StringValue == "bob"
+	}
+
+	first := doc.Find(isBob)
+	if first == nil || first != doc.Get("users/2/name") {
+		t.Fatalf("Document.Find() = %v, want users/2/name", first)
+	}
+
+	if all := doc.FindAll(isBob); len(all) != 2 || all[1] != doc.Get("users/3/name") {
+		t.Fatalf("Document.FindAll() = %v, want two matches", all)
+	}
+
+	users := doc.Roots[0]
+	if got := users.Find(func(node *Node) bool { return node.Kind == NodeObject }); got == nil || got.Key != "1" {
+		t.Fatalf("Node.Find(object) = %v, want 1", got)
+	}
+
+	if got := users.Find(func(node *Node) bool { return node == users }); got != nil {
+		t.Fatalf("Node.Find(self) = %v, want nil", got)
+	}
+
+	if all := users.FindAll(isBob); len(all) != 2 {
+		t.Fatalf("Node.FindAll() = %d matches, want 2", len(all))
+	}
+}
@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FlattenDuplicates defines how Flatten handles keys repeated among siblings.
+type FlattenDuplicates uint8
+
+const (
+	// FlattenSuffix keeps every occurrence, adding a "[n]" suffix to the key
+	// segment of later ones, as in node paths.
+	FlattenSuffix FlattenDuplicates = iota
+	// FlattenFirst keeps only the first occurrence of each key.
+	FlattenFirst
+	// FlattenLast keeps only the last occurrence of each key,
+	// matching the effective "last wins" lookup of Valve KeyValues.
+	FlattenLast
+	// FlattenStrict fails with ErrDuplicateKeyInStrictMode on a repeated key.
+	FlattenStrict
+)
+
+// Flatten returns the document leaves as a map from joined key paths to
+// text values, so "Steam" { "Apps" { "440" { "name" "Team Fortress 2" } } }
+// flattened with "." yields "Steam.Apps.440.name" → "Team Fortress 2".
+// An empty sep uses PathSeparator. Values are formatted as the text encoder
+// writes them; empty objects and nil nodes produce no entries.
+// Keys containing sep make paths ambiguous and may overwrite each other.
+func (d *Document) Flatten(sep string, duplicates FlattenDuplicates) (map[string]string, error) {
+	if d == nil {
+		return nil, fmt.Errorf("%w: nil document", ErrInvalidNodeState)
+	}
+
+	if duplicates > FlattenStrict {
+		return nil, fmt.Errorf("%w: flatten duplicates mode %d", ErrValueConversion, duplicates)
+	}
+
+	if sep == "" {
+		sep = PathSeparator
+	}
+
+	out := make(map[string]string)
+	if err := flattenNodes(out, "", sep, d.Roots, duplicates); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// flattenNodes adds the leaves of sibling nodes below prefix to out.
+func flattenNodes(out map[string]string, prefix, sep string, nodes []*Node, duplicates FlattenDuplicates) error {
+	groups := groupByKey(nodes)
+	seen := make(map[string]int, len(nodes))
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+
+		occurrence := seen[node.Key]
+		seen[node.Key]++
+
+		count := len(groups[node.Key])
+		segment := node.Key
+		switch duplicates {
+		case FlattenSuffix:
+			if occurrence > 0 {
+				segment += "[" + strconv.Itoa(occurrence) + "]"
+			}
+		case FlattenFirst:
+			if occurrence > 0 {
+				continue
+			}
+		case FlattenLast:
+			if occurrence < count-1 {
+				continue
+			}
+		}
+
+		key := segment
+		if prefix != "" {
+			key = prefix + sep + segment
+		}
+
+		if duplicates == FlattenStrict && count > 1 {
+			return fmt.Errorf("%w: %q", ErrDuplicateKeyInStrictMode, key)
+		}
+
+		if node.Kind == NodeObject {
+			if err := flattenNodes(out, key, sep, node.Children, duplicates); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		value, err := textValueForNode(node)
+		if err != nil {
+			return err
+		}
+
+		out[key] = value
+	}
+
+	return nil
+}
+
+// Unflatten builds a document from a map of separator-joined key paths to
+// string values, the inverse of Flatten: "Steam.Apps.440.name" with "."
+// creates nested "Steam", "Apps" and "440" objects holding a "name" string.
+// An empty sep uses PathSeparator. A "[n]" suffix on a segment selects a
+// repeated key as in node paths; occurrences must be contiguous from zero.
+// Keys are applied in segment order, so the result is deterministic:
+// siblings are sorted by key, and repeated keys by occurrence.
+func Unflatten(m map[string]string, sep string) (*Document, error) {
+	if sep == "" {
+		sep = PathSeparator
+	}
+
+	type entry struct {
+		path     string
+		segments []pathSegment
+	}
+
+	entries := make([]entry, 0, len(m))
+	for path := range m {
+		if path == "" {
+			return nil, fmt.Errorf("%w: empty flattened key", ErrInvalidPath)
+		}
+
+		parts := strings.Split(path, sep)
+		segments := make([]pathSegment, 0, len(parts))
+		for _, part := range parts {
+			if part == "" {
+				return nil, fmt.Errorf("%w: empty segment in %q", ErrInvalidPath, path)
+			}
+
+			segment, err := parsePathSegment(part)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q", err, path)
+			}
+
+			segments = append(segments, segment)
+		}
+
+		entries = append(entries, entry{path: path, segments: segments})
+	}
+
+	slices.SortFunc(entries, func(a, b entry) int {
+		return slices.CompareFunc(a.segments, b.segments, func(x, y pathSegment) int {
+			return cmp.Or(strings.Compare(x.key, y.key), cmp.Compare(x.occurrence, y.occurrence))
+		})
+	})
+
+	doc := NewDocumentWithFormat(FormatText)
+	for _, e := range entries {
+		siblings := &doc.Roots
+		if last := len(e.segments) - 1; last > 0 {
+			root, err := ensureChild(&doc.Roots, e.segments[0])
+			if err != nil {
+				return nil, err
+			}
+
+			parent, err := ensureSegments(root, e.segments[1:last])
+			if err != nil {
+				return nil, err
+			}
+
+			if parent.Kind != NodeObject {
+				return nil, fmt.Errorf("%w: %q is not an object", ErrPathNotObject, parent.Key)
+			}
+
+			siblings = &parent.Children
+		}
+
+		leaf := e.segments[len(e.segments)-1]
+		if findOccurrence(*siblings, leaf) >= 0 {
+			return nil, fmt.Errorf("%w: %q conflicts with another key", ErrInvalidPath, e.path)
+		}
+
+		if countKey(*siblings, leaf.key) != leaf.occurrence {
+			return nil, fmt.Errorf("%w: occurrence %d of %q", ErrPathNotFound, leaf.occurrence, leaf.key)
+		}
+
+		*siblings = append(*siblings, NewStringNode(leaf.key, m[e.path]))
+	}
+
+	return doc, nil
+}
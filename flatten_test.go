@@ -0,0 +1,96 @@
+package vdf
+
+import (
+	"errors"
+	"maps"
+	"testing"
+)
+
+func TestDocumentFlatten(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"Steam" { "Apps" { "440" { "name" "Team Fortress 2" } } "tag" "a" "tag" "b" "empty" { } }`)
+
+	cases := []struct {
+		want       map[string]string
+		name       string
+		duplicates FlattenDuplicates
+	}{
+		{
+			name: "suffix",
+			want: map[string]string{"Steam.Apps.440.name": "Team Fortress 2", "Steam.tag": "a", "Steam.tag[1]": "b"},
+		},
+		{
+			name:       "first",
+			duplicates: FlattenFirst,
+			want:       map[string]string{"Steam.Apps.440.name": "Team Fortress 2", "Steam.tag": "a"},
+		},
+		{
+			name:       "last",
+			duplicates: FlattenLast,
+			want:       map[string]string{"Steam.Apps.440.name": "Team Fortress 2", "Steam.tag": "b"},
+		},
+	}
+
+	for _, tc := range cases {
+		got, err := doc.Flatten(".", tc.duplicates)
+		if err != nil || !maps.Equal(got, tc.want) {
+			t.Fatalf("Flatten(%s) = %v, %v, want %v", tc.name, got, err, tc.want)
+		}
+	}
+
+	if got, err := doc.Flatten("", FlattenFirst); err != nil || got["Steam/Apps/440/name"] == "" {
+		t.Fatalf("Flatten(default sep) = %v, %v", got, err)
+	}
+
+	if _, err := doc.Flatten(".", FlattenStrict); !errors.Is(err, ErrDuplicateKeyInStrictMode) {
+		t.Fatalf("Flatten(strict) error = %v", err)
+	}
+
+	if _, err := doc.Flatten(".", FlattenStrict+1); err == nil {
+		t.Fatal("Flatten(bad mode) returned no error")
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	t.Parallel()
+
+	flat := map[string]string{
+		"Steam.Apps.440.name": "Team Fortress 2",
+		"Steam.tag[1]":        "b",
+		"Steam.tag":           "a",
+		"Steam.Apps.570.name": "Dota 2",
+	}
+
+	doc, err := Unflatten(flat, ".")
+	if err != nil {
+		t.Fatalf("Unflatten: %v", err)
+	}
+
+	if got := childKeys(doc.Roots[0]); got != "Apps,tag,tag" {
+		t.Fatalf("keys = %s", got)
+	}
+
+	back, err := doc.Flatten(".", FlattenSuffix)
+	if err != nil || !maps.Equal(back, flat) {
+		t.Fatalf("Flatten(Unflatten) = %v, %v", back, err)
+	}
+
+	if doc, err := Unflatten(map[string]string{"a/b": "1"}, ""); err != nil || doc.Get("a/b") == nil {
+		t.Fatalf("Unflatten(default sep) = %v, %v", doc, err)
+	}
+
+	bad := []map[string]string{
+		{"a": "1", "a.b": "2"},
+		{"a": "1", "a[0]": "2"},
+		{"a[2]": "1"},
+		{"a..b": "1"},
+		{"a[x]": "1"},
+	}
+
+	for _, m := range bad {
+		if _, err := Unflatten(m, "."); err == nil {
+			t.Fatalf("Unflatten(%v) returned no error", m)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import "regexp"
+
+// GrepMatch is one leaf found by Document.Grep.
+type GrepMatch struct {
+	Node *Node  // Matching leaf node.
+	Path string // Slash-delimited path with "[n]" occurrence suffixes, usable with Get.
+}
+
+// Grep returns leaf nodes whose key matches keyRe and whose value text matches valueRe,
+// with their paths, in depth-first pre-order. A nil expression matches anything.
+// Values are matched in their text VDF form, so numeric leaves match their digits;
+// raw binary leaves only match a nil valueRe.
+// For example Grep(regexp.MustCompile(`(?i)password`), nil) lists every password entry.
+func (d *Document) Grep(keyRe, valueRe *regexp.Regexp) []GrepMatch {
+	if d == nil {
+		return nil
+	}
+
+	type grepFrame struct {
+		nodes  []*Node        // Sibling nodes.
+		seen   map[string]int // Occurrences of keys already visited.
+		prefix string         // Path of the parent object.
+		index  int            // Next sibling to visit.
+	}
+
+	var matches []GrepMatch
+	stack := []grepFrame{{nodes: d.Roots, seen: make(map[string]int)}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.index >= len(top.nodes) {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		node := top.nodes[top.index]
+		top.index++
+		if node == nil {
+			continue
+		}
+
+		occurrence := top.seen[node.Key]
+		top.seen[node.Key]++
+		path := joinOccurrencePath(top.prefix, node.Key, occurrence)
+
+		if node.Kind == NodeObject {
+			stack = append(stack, grepFrame{nodes: node.Children, seen: make(map[string]int), prefix: path})
+			continue
+		}
+
+		if keyRe != nil && !keyRe.MatchString(node.Key) {
+			continue
+		}
+
+		if valueRe != nil {
+			value, err := textValueForNode(node)
+			if err != nil || !valueRe.MatchString(value) {
+				continue
+			}
+		}
+
+		matches = append(matches, GrepMatch{Node: node, Path: path})
+	}
+
+	return matches
+}
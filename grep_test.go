@@ -0,0 +1,36 @@
+package vdf
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestDocumentGrep(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"cfg" {
+		"db" { "Password" "secret" "path" "C:\\data" }
+		"path" "D:\\logs"
+		"path" "/var/log"
+		"port" "27015"
+	}`)
+
+	matches := doc.Grep(regexp.MustCompile(`(?i)password`), nil)
+	if len(matches) != 1 || matches[0].Path != "cfg/db/Password" {
+		t.Fatalf("Grep(password) = %+v, want cfg/db/Password", matches)
+	}
+
+	matches = doc.Grep(nil, regexp.MustCompile(`^[A-Z]:\\`))
+	if len(matches) != 2 || matches[0].Path != "cfg/db/path" || matches[1].Path != "cfg/path" {
+		t.Fatalf("Grep(drive letter) = %+v", matches)
+	}
+
+	matches = doc.Grep(regexp.MustCompile(`^path$`), regexp.MustCompile(`^/`))
+	if len(matches) != 1 || matches[0].Path != "cfg/path[1]" || doc.Get(matches[0].Path) != matches[0].Node {
+		t.Fatalf("Grep(path, /) = %+v, want cfg/path[1]", matches)
+	}
+
+	if all := doc.Grep(nil, nil); len(all) != 5 {
+		t.Fatalf("Grep(nil, nil) = %d leaves, want 5", len(all))
+	}
+}
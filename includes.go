@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// includeResolver expands directives of one decode call against DecodeOptions.IncludeFS.
+type includeResolver struct {
+	opts   DecodeOptions // Decode options shared by included files.
+	active []string      // FS paths of files currently being resolved, outermost first.
+	depth  int           // Number of include levels currently open.
+}
+
+// resolveIncludes replaces the directives of doc with the content of the referenced files.
+func resolveIncludes(doc *Document, opts DecodeOptions) error {
+	r := &includeResolver{opts: opts}
+	if opts.IncludePath != "" {
+		r.active = append(r.active, path.Clean(opts.IncludePath))
+	}
+
+	return r.resolve(doc, path.Dir(opts.IncludePath))
+}
+
+// resolve expands the root directives of doc, whose file lives in dir.
+// Included roots are appended in directive order, then base roots are merged.
+func (r *includeResolver) resolve(doc *Document, dir string) error {
+	roots := make([]*Node, 0, len(doc.Roots))
+	var included, bases []*Node
+	for _, root := range doc.Roots {
+		if root == nil || root.Kind != NodeDirective {
+			roots = append(roots, root)
+			continue
+		}
+
+		loaded, err := r.load(dir, root)
+		if err != nil {
+			return err
+		}
+
+		if strings.EqualFold(root.Key, "#base") {
+			bases = append(bases, loaded...)
+		} else {
+			included = append(included, loaded...)
+		}
+	}
+
+	doc.Roots = mergeChildren(append(roots, included...), bases, MergeKeepExisting)
+	return nil
+}
+
+// load parses and resolves the file referenced by directive.
+func (r *includeResolver) load(dir string, directive *Node) ([]*Node, error) {
+	if directive.StringValue == nil {
+		return nil, fmt.Errorf("%w: directive node %q missing value", ErrInvalidNodeState, directive.Key)
+	}
+
+	// Valve files often spell paths with backslashes.
+	name := path.Join(dir, strings.ReplaceAll(*directive.StringValue, `\`, "/"))
+	if !fs.ValidPath(name) {
+		return nil, fmt.Errorf("%w: %s %q escapes the include root", ErrInvalidPath, directive.Key, *directive.StringValue)
+	}
+
+	for _, active := range r.active {
+		if active == name {
+			return nil, fmt.Errorf("%w: %s -> %s", ErrIncludeCycle, strings.Join(r.active, " -> "), name)
+		}
+	}
+
+	if r.opts.MaxIncludeDepth > 0 && r.depth >= r.opts.MaxIncludeDepth {
+		return nil, fmt.Errorf("%w: include depth %d > %d at %q", ErrDepthLimitExceeded, r.depth+1, r.opts.MaxIncludeDepth, name)
+	}
+
+	f, err := r.opts.IncludeFS.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q: %w", directive.Key, *directive.StringValue, err)
+	}
+
+	var doc *Document
+	source, err := decodeTextInput(f)
+	if err == nil {
+		doc, err = parseTextDocument(source, r.opts)
+	}
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	r.active = append(r.active, name)
+	r.depth++
+	err = r.resolve(doc, path.Dir(name))
+	r.active = r.active[:len(r.active)-1]
+	r.depth--
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.Roots, nil
+}
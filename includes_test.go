@@ -0,0 +1,98 @@
+package vdf
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDecodeOptionsIncludeFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"resource/hud.res": {Data: []byte(`#base "base.res"
+#include "extra/more.res"
+"Hud"
+{
+	"color"	"red"
+	"panel"
+	{
+		"wide"	"10"
+	}
+}`)},
+		"resource/base.res": {Data: []byte(`"Hud"
+{
+	"color"	"blue"
+	"font"	"Default"
+	"panel"
+	{
+		"wide"	"20"
+		"tall"	"5"
+	}
+}`)},
+		"resource/extra/more.res": {Data: []byte(`"Extra" { "enabled" "1" }`)},
+		"loop/a.res":              {Data: []byte(`#include "b.res"` + "\n" + `"A" { }`)},
+		"loop/b.res":              {Data: []byte(`#base "a.res"` + "\n" + `"B" { }`)},
+		"deep/1.res":              {Data: []byte(`#include "2.res"`)},
+		"deep/2.res":              {Data: []byte(`#include "3.res"`)},
+		"deep/3.res":              {Data: []byte(`"Leaf" { }`)},
+		"escape.res":              {Data: []byte(`#include "../outside.res"`)},
+	}
+
+	data, err := fsys.ReadFile("resource/hud.res")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := ParseBytes(data, DecodeOptions{Format: FormatText, IncludeFS: fsys, IncludePath: "resource/hud.res"})
+	if err != nil {
+		t.Fatalf("ParseBytes() returned error: %v", err)
+	}
+
+	want, err := ParseString(`"Hud"
+{
+	"color"	"red"
+	"panel"
+	{
+		"wide"	"10"
+		"tall"	"5"
+	}
+	"font"	"Default"
+}
+"Extra" { "enabled" "1" }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changed := ChangedPaths(doc, want); len(changed) != 0 {
+		t.Fatalf("resolved document differs at %v", changed)
+	}
+
+	tests := []struct {
+		wantErr  error
+		name     string
+		path     string
+		maxDepth int
+	}{
+		{name: "cycle", path: "loop/a.res", wantErr: ErrIncludeCycle},
+		{name: "depth", path: "deep/1.res", maxDepth: 1, wantErr: ErrDepthLimitExceeded},
+		{name: "depth within limit", path: "deep/1.res", maxDepth: 2},
+		{name: "escape", path: "escape.res", wantErr: ErrInvalidPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input, err := fsys.ReadFile(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			opts := DecodeOptions{Format: FormatText, IncludeFS: fsys, IncludePath: tt.path, MaxIncludeDepth: tt.maxDepth}
+			if _, err := ParseBytes(input, opts); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseBytes() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
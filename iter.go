@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"errors"
+	"io"
+	"iter"
+)
+
+// Pairs returns an iterator over the keys and children of an object node
+// in source order, including duplicate keys. Other nodes yield nothing.
+func (n *Node) Pairs() iter.Seq2[string, *Node] {
+	return func(yield func(string, *Node) bool) {
+		if n == nil || n.Kind != NodeObject {
+			return
+		}
+
+		for _, child := range n.Children {
+			if child != nil && !yield(child.Key, child) {
+				return
+			}
+		}
+	}
+}
+
+// Descendants returns an iterator over all nodes below n in depth-first pre-order.
+func (n *Node) Descendants() iter.Seq[*Node] {
+	return func(yield func(*Node) bool) {
+		if n == nil || n.Kind != NodeObject {
+			return
+		}
+
+		yieldNodes(n.Children, yield)
+	}
+}
+
+// Nodes returns an iterator over all document nodes in depth-first pre-order,
+// roots first in source order.
+func (d *Document) Nodes() iter.Seq[*Node] {
+	return func(yield func(*Node) bool) {
+		if d == nil {
+			return
+		}
+
+		yieldNodes(d.Roots, yield)
+	}
+}
+
+// yieldNodes passes nodes and their descendants to yield in pre-order until it returns false.
+func yieldNodes(nodes []*Node, yield func(*Node) bool) {
+	_ = walkNodes(nodes, func(_ []string, node *Node) error {
+		if !yield(node) {
+			return SkipAll
+		}
+
+		return nil
+	})
+}
+
+// Events returns an iterator over the events of Token, read incrementally
+// from the input. Iteration ends after EventDocumentEnd, or after yielding
+// the first decode error with a zero Event.
+func (d *Decoder) Events() iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		for {
+			event, err := d.Token()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if !yield(event, err) || err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package vdf
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNodeIterators(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"root" { "a" "1" "sub" { "b" "2" } "a" "3" } "other" "4"`)
+	root := doc.Roots[0]
+
+	var pairs []string
+	for key, node := range root.Pairs() {
+		pairs = append(pairs, key+"="+nodeKey(node))
+	}
+
+	if !slices.Equal(pairs, []string{"a=a", "sub=sub", "a=a"}) {
+		t.Fatalf("Pairs() = %v", pairs)
+	}
+
+	var keys []string
+	for node := range root.Descendants() {
+		keys = append(keys, node.Key)
+	}
+
+	if !slices.Equal(keys, []string{"a", "sub", "b", "a"}) {
+		t.Fatalf("Descendants() = %v", keys)
+	}
+
+	keys = keys[:0]
+	for node := range doc.Nodes() {
+		keys = append(keys, node.Key)
+		if node.Key == "b" {
+			break
+		}
+	}
+
+	if !slices.Equal(keys, []string{"root", "a", "sub", "b"}) {
+		t.Fatalf("Nodes() with break = %v", keys)
+	}
+}
+
+func TestDecoderEvents(t *testing.T) {
+	t.Parallel()
+
+	decoder := NewDecoder(strings.NewReader(`"root" { "a" "1" }`), DecodeOptions{Format: FormatText})
+	var types []EventType
+	for event, err := range decoder.Events() {
+		if err != nil {
+			t.Fatalf("Events() returned error: %v", err)
+		}
+
+		types = append(types, event.Type)
+	}
+
+	want := []EventType{EventDocumentStart, EventObjectStart, EventString, EventObjectEnd, EventDocumentEnd}
+	if !slices.Equal(types, want) {
+		t.Fatalf("Events() types = %v, want %v", types, want)
+	}
+
+	decoder = NewDecoder(strings.NewReader(`"root" { "a" `), DecodeOptions{Format: FormatText})
+	var failed error
+	for _, err := range decoder.Events() {
+		failed = err
+	}
+
+	if failed == nil {
+		t.Fatal("Events() on malformed input yielded no error")
+	}
+}
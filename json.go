@@ -0,0 +1,453 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// JSONDuplicates defines how ToJSON represents keys repeated within one object.
+type JSONDuplicates uint8
+
+const (
+	// JSONDuplicateArray writes a repeated key once, at its first position,
+	// with an array of all its values in source order.
+	JSONDuplicateArray JSONDuplicates = iota
+	// JSONDuplicateSuffix writes later occurrences as "key[n]" members,
+	// matching the occurrence syntax of node paths.
+	JSONDuplicateSuffix
+	// JSONDuplicateLast writes a repeated key once, at its first position,
+	// with the value of its last occurrence, like ToMapLossy.
+	JSONDuplicateLast
+)
+
+// JSONNumbers defines how FromJSON maps JSON numbers to nodes.
+type JSONNumbers uint8
+
+const (
+	// JSONNumberString keeps numbers as string nodes with their JSON text,
+	// as text VDF stores them.
+	JSONNumberString JSONNumbers = iota
+	// JSONNumberTyped maps integers to uint32, uint64 or int64 nodes by range
+	// and other numbers to float32 nodes, as binary VDF stores them.
+	JSONNumberTyped
+)
+
+// JSONBools defines how FromJSON maps JSON booleans to nodes.
+type JSONBools uint8
+
+const (
+	// JSONBoolNumeric maps booleans to "1" and "0" string nodes, the Steam convention.
+	JSONBoolNumeric JSONBools = iota
+	// JSONBoolString maps booleans to "true" and "false" string nodes.
+	JSONBoolString
+)
+
+// JSONOptions controls Document.ToJSON and FromJSON.
+type JSONOptions struct {
+	// Indent is the indentation of one nesting level; empty writes compact JSON.
+	Indent string
+	// Duplicates selects how repeated keys are represented.
+	Duplicates JSONDuplicates
+	// Numbers selects how FromJSON maps numbers.
+	Numbers JSONNumbers
+	// Bools selects how FromJSON maps booleans.
+	Bools JSONBools
+}
+
+// validate rejects unknown option modes.
+func (o JSONOptions) validate() error {
+	switch {
+	case o.Duplicates > JSONDuplicateLast:
+		return fmt.Errorf("%w: JSON duplicates mode %d", ErrValueConversion, o.Duplicates)
+	case o.Numbers > JSONNumberTyped:
+		return fmt.Errorf("%w: JSON numbers mode %d", ErrValueConversion, o.Numbers)
+	case o.Bools > JSONBoolString:
+		return fmt.Errorf("%w: JSON bools mode %d", ErrValueConversion, o.Bools)
+	}
+
+	return nil
+}
+
+// ToJSON encodes the document as nested JSON objects keyed by VDF keys,
+// preserving key order. Objects become JSON objects, string leaves strings,
+// numeric leaves numbers, colors "R G B A" strings and raw binary values
+// base64 strings. Conditionals are dropped.
+// Unlike ToMapLossy, repeated keys are kept according to opts.Duplicates.
+func (d *Document) ToJSON(opts JSONOptions) ([]byte, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := writeJSONObject(&buf, documentRoots(d), opts.Duplicates); err != nil {
+		return nil, err
+	}
+
+	if opts.Indent == "" {
+		return buf.Bytes(), nil
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", opts.Indent); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// writeJSONObject writes sibling nodes as one JSON object.
+func writeJSONObject(buf *bytes.Buffer, nodes []*Node, duplicates JSONDuplicates) error {
+	groups := groupByKey(nodes)
+	seen := make(map[string]int, len(nodes))
+
+	buf.WriteByte('{')
+	first := true
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+
+		occurrence := seen[node.Key]
+		seen[node.Key]++
+
+		group := groups[node.Key]
+		if occurrence > 0 && duplicates != JSONDuplicateSuffix {
+			continue
+		}
+
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		name := node.Key
+		if occurrence > 0 {
+			name = joinOccurrencePath("", node.Key, occurrence)
+		}
+
+		if err := writeJSONString(buf, name); err != nil {
+			return err
+		}
+
+		buf.WriteByte(':')
+		switch {
+		case len(group) > 1 && duplicates == JSONDuplicateArray:
+			buf.WriteByte('[')
+			for i, item := range group {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+
+				if err := writeJSONValue(buf, item, duplicates); err != nil {
+					return err
+				}
+			}
+
+			buf.WriteByte(']')
+		case duplicates == JSONDuplicateLast:
+			if err := writeJSONValue(buf, group[len(group)-1], duplicates); err != nil {
+				return err
+			}
+		default:
+			if err := writeJSONValue(buf, node, duplicates); err != nil {
+				return err
+			}
+		}
+	}
+
+	buf.WriteByte('}')
+	return nil
+}
+
+// writeJSONValue writes one object or leaf value.
+func writeJSONValue(buf *bytes.Buffer, node *Node, duplicates JSONDuplicates) error {
+	switch node.Kind {
+	case NodeObject:
+		return writeJSONObject(buf, node.Children, duplicates)
+	case NodeColor:
+		if node.ColorValue == nil {
+			return fmt.Errorf("%w: color node %q missing value", ErrInvalidNodeState, node.Key)
+		}
+
+		return writeJSONString(buf, node.ColorValue.String())
+	case NodeFloat32:
+		if node.Float32Value == nil {
+			return fmt.Errorf("%w: float32 node %q missing value", ErrInvalidNodeState, node.Key)
+		}
+
+		// JSON has no NaN or infinities, so those are written as text.
+		if value := float64(*node.Float32Value); math.IsNaN(value) || math.IsInf(value, 0) {
+			return writeJSONString(buf, formatFloat32(*node.Float32Value))
+		}
+	}
+
+	value := node.Value()
+	if value == nil {
+		return fmt.Errorf("%w: node %q of kind %d has no value", ErrInvalidNodeState, node.Key, node.Kind)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("%w: %q: %w", ErrValueConversion, node.Key, err)
+	}
+
+	buf.Write(data)
+	return nil
+}
+
+// writeJSONString writes s as a JSON string.
+func writeJSONString(buf *bytes.Buffer, s string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	buf.Write(data)
+	return nil
+}
+
+// FromJSON builds a document from a JSON object, one root per member in
+// source order. Nested objects become object nodes, strings string nodes,
+// and numbers and booleans are mapped per opts.Numbers and opts.Bools.
+// Null members are dropped. Arrays become repeated keys, one per element,
+// so output of ToJSON with JSONDuplicateArray converts back losslessly.
+// JSONDuplicateSuffix also folds "key[n]" members back into repeated keys,
+// and JSONDuplicateLast keeps only the last occurrence of each key.
+func FromJSON(data []byte, opts JSONOptions) (*Document, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+	}
+
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("%w: top-level value is not an object", ErrInvalidJSON)
+	}
+
+	roots, err := readJSONMembers(dec, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: trailing data after top-level object", ErrInvalidJSON)
+	}
+
+	doc := NewDocumentWithFormat(FormatAuto)
+	for _, root := range roots {
+		doc.AddRoot(root)
+	}
+
+	if opts.Duplicates == JSONDuplicateLast {
+		if _, err := doc.Dedupe(DedupeKeepLast); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// readJSONMembers reads object members up to and including the closing brace.
+func readJSONMembers(dec *json.Decoder, opts JSONOptions) ([]*Node, error) {
+	var nodes []*Node
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+		}
+
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: unexpected token %v", ErrInvalidJSON, tok)
+		}
+
+		if opts.Duplicates == JSONDuplicateSuffix {
+			if base, _, ok := splitOccurrence(key); ok {
+				key = base
+			}
+		}
+
+		nodes, err = readJSONValue(dec, nodes, key, opts, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+	}
+
+	return nodes, nil
+}
+
+// readJSONValue reads one member value and appends the nodes it maps to.
+func readJSONValue(dec *json.Decoder, nodes []*Node, key string, opts JSONOptions, inArray bool) ([]*Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+	}
+
+	switch value := tok.(type) {
+	case json.Delim:
+		if value == json.Delim('{') {
+			children, err := readJSONMembers(dec, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			obj := NewObjectNode(key)
+			obj.Children = children
+			return append(nodes, obj), nil
+		}
+
+		// Only '[' can start a value here; the decoder rejects misplaced closers.
+		if inArray {
+			return nil, fmt.Errorf("%w: nested array in key %q", ErrInvalidJSON, key)
+		}
+
+		for dec.More() {
+			if nodes, err = readJSONValue(dec, nodes, key, opts, true); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, err := dec.Token(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+		}
+
+		return nodes, nil
+	case string:
+		return append(nodes, NewStringNode(key, value)), nil
+	case json.Number:
+		node, err := jsonNumberNode(key, value, opts.Numbers)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(nodes, node), nil
+	case bool:
+		text := strconv.FormatBool(value)
+		if opts.Bools == JSONBoolNumeric {
+			text = "0"
+			if value {
+				text = "1"
+			}
+		}
+
+		return append(nodes, NewStringNode(key, text)), nil
+	default:
+		// JSON null has no VDF counterpart.
+		return nodes, nil
+	}
+}
+
+// jsonNumberNode maps one JSON number to a node.
+func jsonNumberNode(key string, number json.Number, mode JSONNumbers) (*Node, error) {
+	text := number.String()
+	if mode == JSONNumberString {
+		return NewStringNode(key, text), nil
+	}
+
+	if value, err := strconv.ParseUint(text, 10, 64); err == nil {
+		if value <= math.MaxUint32 {
+			return NewUint32Node(key, uint32(value)), nil
+		}
+
+		return NewUint64Node(key, value), nil
+	}
+
+	if value, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return NewInt64Node(key, value), nil
+	}
+
+	value, err := strconv.ParseFloat(text, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: key %q number %s: %w", ErrValueConversion, key, text, err)
+	}
+
+	return NewFloat32Node(key, float32(value)), nil
+}
+
+// documentAST is Document without methods, encoding its fields as the AST shape.
+type documentAST Document
+
+// MarshalJSON implements json.Marshaler with the AST shape of the Document
+// fields, which takes precedence over MarshalText.
+func (d *Document) MarshalJSON() ([]byte, error) {
+	return json.Marshal((*documentAST)(d))
+}
+
+// UnmarshalJSON implements json.Unmarshaler for the AST shape of MarshalJSON.
+func (d *Document) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, (*documentAST)(d))
+}
+
+// MarshalYAML keeps the AST shape for YAML encoders honoring
+// a MarshalYAML() (any, error) method, such as gopkg.in/yaml.v3.
+func (d *Document) MarshalYAML() (any, error) {
+	return (*documentAST)(d), nil
+}
+
+// UnmarshalYAML keeps the AST shape for YAML decoders honoring
+// an UnmarshalYAML(func(any) error) error method, such as gopkg.in/yaml.v3.
+func (d *Document) UnmarshalYAML(unmarshal func(any) error) error {
+	return unmarshal((*documentAST)(d))
+}
+
+// NaturalJSON wraps a document so encoding/json marshals it with ToJSON,
+// as plain nested objects, instead of the AST shape of the Document fields.
+// Unmarshaling builds Document with FromJSON. Options.Indent has no effect,
+// as encoding/json compacts marshaler output.
+type NaturalJSON struct {
+	Document *Document   // Wrapped document.
+	Options  JSONOptions // Conversion options.
+}
+
+// Natural returns the document wrapped for natural-shape JSON marshaling with opts.
+func (d *Document) Natural(opts JSONOptions) NaturalJSON {
+	return NaturalJSON{Document: d, Options: opts}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (n NaturalJSON) MarshalJSON() ([]byte, error) {
+	if n.Document == nil {
+		return []byte("null"), nil
+	}
+
+	return n.Document.ToJSON(n.Options)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *NaturalJSON) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		n.Document = nil
+		return nil
+	}
+
+	doc, err := FromJSON(data, n.Options)
+	if err != nil {
+		return err
+	}
+
+	n.Document = doc
+	return nil
+}
@@ -0,0 +1,171 @@
+package vdf
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDocumentToJSON(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"root" { "z" "1" "tag" "a" "sub" { "x" "y" } "tag" "b" "a" "2" }`)
+	doc.Roots[0].Add(NewUint32Node("n", 440))
+	doc.Roots[0].Add(NewColorNode("c", Color{R: 1, G: 2, B: 3, A: 4}))
+
+	cases := []struct {
+		name string
+		opts JSONOptions
+		want string
+	}{
+		{
+			name: "array",
+			want: `{"root":{"z":"1","tag":["a","b"],"sub":{"x":"y"},"a":"2","n":440,"c":"1 2 3 4"}}`,
+		},
+		{
+			name: "suffix",
+			opts: JSONOptions{Duplicates: JSONDuplicateSuffix},
+			want: `{"root":{"z":"1","tag":"a","sub":{"x":"y"},"tag[1]":"b","a":"2","n":440,"c":"1 2 3 4"}}`,
+		},
+		{
+			name: "last",
+			opts: JSONOptions{Duplicates: JSONDuplicateLast},
+			want: `{"root":{"z":"1","tag":"b","sub":{"x":"y"},"a":"2","n":440,"c":"1 2 3 4"}}`,
+		},
+	}
+
+	for _, tc := range cases {
+		out, err := doc.ToJSON(tc.opts)
+		if err != nil || string(out) != tc.want {
+			t.Fatalf("ToJSON(%s) = %s, %v, want %s", tc.name, out, err, tc.want)
+		}
+	}
+
+	out, err := doc.ToJSON(JSONOptions{Indent: "  "})
+	if err != nil || !strings.HasPrefix(string(out), "{\n  \"root\": {\n    \"z\": \"1\",") {
+		t.Fatalf("ToJSON(indent) = %s, %v", out, err)
+	}
+
+	if _, err := doc.ToJSON(JSONOptions{Duplicates: JSONDuplicateLast + 1}); err == nil {
+		t.Fatal("ToJSON(bad duplicates) returned no error")
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	t.Parallel()
+
+	input := `{"root":{"name":"x","tag":["a","b"],"n":440,"big":5000000000,"neg":-1,"f":1.5,"on":true,"gone":null,"sub":{"k":"v"}}}`
+
+	doc, err := FromJSON([]byte(input), JSONOptions{})
+	if err != nil {
+		t.Fatalf("FromJSON: %v", err)
+	}
+
+	root := doc.Roots[0]
+	if got := childKeys(root); got != "name,tag,tag,n,big,neg,f,on,sub" {
+		t.Fatalf("keys = %v", got)
+	}
+
+	if v := doc.Get("root/n"); v.Kind != NodeString || *v.StringValue != "440" {
+		t.Fatalf("n = %+v", v)
+	}
+
+	if v := doc.Get("root/on"); *v.StringValue != "1" {
+		t.Fatalf("on = %q", *v.StringValue)
+	}
+
+	out, err := doc.ToJSON(JSONOptions{})
+	if err != nil || !strings.Contains(string(out), `"tag":["a","b"]`) {
+		t.Fatalf("round trip = %s, %v", out, err)
+	}
+
+	typed, err := FromJSON([]byte(input), JSONOptions{Numbers: JSONNumberTyped, Bools: JSONBoolString})
+	if err != nil {
+		t.Fatalf("FromJSON(typed): %v", err)
+	}
+
+	kinds := map[string]NodeKind{"n": NodeUint32, "big": NodeUint64, "neg": NodeInt64, "f": NodeFloat32}
+	for key, kind := range kinds {
+		if v := typed.Get("root/" + key); v == nil || v.Kind != kind {
+			t.Fatalf("%s = %+v, want kind %d", key, v, kind)
+		}
+	}
+
+	if v := typed.Get("root/on"); *v.StringValue != "true" {
+		t.Fatalf("on = %q", *v.StringValue)
+	}
+
+	suffix, err := FromJSON([]byte(`{"a":"1","b":"2","a[1]":"3"}`), JSONOptions{Duplicates: JSONDuplicateSuffix})
+	if err != nil || len(suffix.Roots) != 3 || suffix.Roots[2].Key != "a" {
+		t.Fatalf("FromJSON(suffix) = %+v, %v", suffix, err)
+	}
+
+	last, err := FromJSON([]byte(`{"a":["1","2"],"b":"3"}`), JSONOptions{Duplicates: JSONDuplicateLast})
+	if err != nil || len(last.Roots) != 2 || *last.Roots[0].StringValue != "2" {
+		t.Fatalf("FromJSON(last) = %+v, %v", last, err)
+	}
+
+	for _, bad := range []string{`[]`, `{"a":[["x"]]}`, `{"a":"1"} {}`, `{"a":`, `{"a":1e999}`} {
+		if _, err := FromJSON([]byte(bad), JSONOptions{Numbers: JSONNumberTyped}); err == nil {
+			t.Fatalf("FromJSON(%s) returned no error", bad)
+		}
+	}
+}
+
+func TestNaturalJSON(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"root" { "a" "1" "sub" { "b" "2" } }`)
+	payload := struct {
+		Config NaturalJSON `json:"config"`
+		Name   string      `json:"name"`
+	}{Config: doc.Natural(JSONOptions{}), Name: "x"}
+
+	out, err := json.Marshal(payload)
+	want := `{"config":{"root":{"a":"1","sub":{"b":"2"}}},"name":"x"}`
+	if err != nil || string(out) != want {
+		t.Fatalf("Marshal = %s, %v, want %s", out, err, want)
+	}
+
+	payload.Config = NaturalJSON{}
+	if err := json.Unmarshal(out, &payload); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if !payload.Config.Document.Equal(doc) {
+		t.Fatalf("Unmarshal document = %+v", payload.Config.Document)
+	}
+
+	if out, err := json.Marshal(NaturalJSON{}); err != nil || string(out) != "null" {
+		t.Fatalf("Marshal(nil) = %s, %v", out, err)
+	}
+}
+
+func TestDocumentASTJSON(t *testing.T) {
+	t.Parallel()
+
+	// AST JSON as produced before Document gained MarshalText.
+	const ast = `{"roots":[{"key":"root","children":[{"string_value":"x","key":"name","kind":2},` +
+		`{"uint32_value":440,"key":"appid","kind":3}],"kind":1}],"format":1}`
+
+	var doc Document
+	if err := json.Unmarshal([]byte(ast), &doc); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if v := doc.Get("root/appid"); v == nil || *v.Uint32Value != 440 {
+		t.Fatalf("appid = %+v", v)
+	}
+
+	out, err := json.Marshal(&doc)
+	if err != nil || string(out) != ast {
+		t.Fatalf("Marshal = %s, %v, want %s", out, err, ast)
+	}
+
+	wrapped, err := json.Marshal(struct {
+		Doc *Document `json:"doc"`
+	}{Doc: &doc})
+	if err != nil || string(wrapped) != `{"doc":`+ast+`}` {
+		t.Fatalf("Marshal(field) = %s, %v", wrapped, err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // textTokenKind defines internal token categories for text VDF parsing.
@@ -24,14 +25,19 @@ const (
 	textTokenLBrace
 	// textTokenRBrace marks '}'.
 	textTokenRBrace
+	// textTokenCondition marks a "[...]" conditional; the value excludes the brackets.
+	textTokenCondition
 )
 
 // textToken stores one lexical token with source position.
 type textToken struct {
-	value string        // Value of the token.
-	line  int           // Line number of the token.
-	col   int           // Column number of the token.
-	kind  textTokenKind // Type of the token.
+	value  string        // Value of the token.
+	start  int64         // Byte offset of the first token byte.
+	end    int64         // Byte offset just past the token.
+	line   int           // Line number of the token.
+	col    int           // Column number of the token.
+	kind   textTokenKind // Type of the token.
+	quoted bool          // Whether a string token was written in double quotes.
 }
 
 // runeReader is a minimal rune-scanning reader contract.
@@ -39,13 +45,26 @@ type runeReader interface {
 	ReadRune() (r rune, size int, err error)
 }
 
+// rawByteReader recovers the byte behind an invalid UTF-8 rune;
+// bufio.Reader, bytes.Reader and strings.Reader implement it.
+type rawByteReader interface {
+	UnreadRune() error
+	ReadByte() (byte, error)
+}
+
 // textLexer tokenizes text VDF input.
 type textLexer struct {
-	reader    runeReader // Reader for the input.
-	peeked    rune       // Peeked rune value.
-	hasPeeked bool       // Whether peeked rune is set.
-	line      int        // Line number of the current position.
-	col       int        // Column number of the current position.
+	reader     runeReader        // Reader for the input.
+	offset     int64             // Byte offset of the current position.
+	peeked     rune              // Peeked rune value.
+	peekedSize int               // Encoded size of the peeked rune.
+	hasPeeked  bool              // Whether peeked rune is set.
+	noEscapes  bool              // Whether backslashes in quoted strings are literal.
+	invalid    InvalidUTF8Policy // Handling of invalid UTF-8 bytes in tokens.
+	maxToken   int               // Token size limit in bytes (0 means unlimited).
+	recover    func(err error)   // Receives errors recovered with DecodeOptions.Recover, nil when disabled.
+	line       int               // Line number of the current position.
+	col        int               // Column number of the current position.
 }
 
 // newTextLexer creates a text lexer.
@@ -62,26 +81,83 @@ func newTextLexer(r io.Reader) *textLexer {
 	}
 }
 
+// applyOptions configures escapes, invalid UTF-8 handling and the token size limit.
+// Tokens are capped by MaxKeyBytes; the parser switches the cap to MaxStringBytes
+// while reading a value, so either limit alone stops an oversized token early.
+func (l *textLexer) applyOptions(opts DecodeOptions) {
+	l.noEscapes = opts.DisableEscapes
+	l.invalid = opts.InvalidUTF8
+	l.maxToken = opts.MaxKeyBytes
+}
+
 // readRune consumes one rune and updates source position.
 func (l *textLexer) readRune() (rune, error) {
 	if l.hasPeeked {
 		r := l.peeked
 		l.hasPeeked = false
-		l.advancePosition(r)
+		l.advancePosition(r, l.peekedSize)
 		return r, nil
 	}
 
-	r, _, err := l.reader.ReadRune()
+	r, size, err := l.scanRune()
 	if err != nil {
 		return 0, err
 	}
 
-	l.advancePosition(r)
+	l.advancePosition(r, size)
 	return r, nil
 }
 
-// advancePosition updates line and column after consuming rune.
-func (l *textLexer) advancePosition(r rune) {
+// scanRune reads one rune from the input.
+// Unless invalid bytes are replaced, each one is returned as invalidByteRune.
+func (l *textLexer) scanRune() (rune, int, error) {
+	r, size, err := l.reader.ReadRune()
+	if err != nil || r != utf8.RuneError || size != 1 || l.invalid == InvalidUTF8Replace {
+		return r, size, err
+	}
+
+	raw, ok := l.reader.(rawByteReader)
+	if !ok || raw.UnreadRune() != nil {
+		return r, size, nil
+	}
+
+	b, err := raw.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return invalidByteRune(b), 1, nil
+}
+
+// invalidByteRune encodes an invalid UTF-8 input byte as a negative rune,
+// which never matches token delimiters.
+func invalidByteRune(b byte) rune {
+	return -1 - rune(b)
+}
+
+// appendRune adds a token rune to sb, applying the token size limit
+// and the invalid UTF-8 policy to invalid bytes.
+func (l *textLexer) appendRune(sb *strings.Builder, r rune) error {
+	if l.maxToken > 0 && sb.Len() >= l.maxToken {
+		return l.errorHere(fmt.Errorf("%w: token exceeds %d bytes", ErrStringLimitExceeded, l.maxToken))
+	}
+
+	if r >= 0 {
+		sb.WriteRune(r)
+		return nil
+	}
+
+	if l.invalid == InvalidUTF8Reject {
+		return l.errorHere(fmt.Errorf("%w: byte 0x%02X", ErrInvalidUTF8, byte(-1-r)))
+	}
+
+	sb.WriteByte(byte(-1 - r))
+	return nil
+}
+
+// advancePosition updates byte offset, line, and column after consuming rune.
+func (l *textLexer) advancePosition(r rune, size int) {
+	l.offset += int64(size)
 	if r == '\n' {
 		l.line++
 		l.col = 0
@@ -97,12 +173,13 @@ func (l *textLexer) peekRune() (rune, error) {
 		return l.peeked, nil
 	}
 
-	r, _, err := l.reader.ReadRune()
+	r, size, err := l.scanRune()
 	if err != nil {
 		return 0, err
 	}
 
 	l.peeked = r
+	l.peekedSize = size
 	l.hasPeeked = true
 	return r, nil
 }
@@ -147,7 +224,7 @@ func (l *textLexer) skipLineComment() error {
 	}
 }
 
-// readQuotedString reads one quoted string and decodes escapes.
+// readQuotedString reads one quoted string and decodes escapes unless they are disabled.
 func (l *textLexer) readQuotedString() (string, error) {
 	if _, err := l.readRune(); err != nil {
 		return "", err
@@ -157,7 +234,7 @@ func (l *textLexer) readQuotedString() (string, error) {
 	for {
 		r, err := l.readRune()
 		if err == io.EOF {
-			return "", ErrUnexpectedEOFInQuotedString
+			return l.recoverEOF(sb.String(), ErrUnexpectedEOFInQuotedString)
 		}
 
 		if err != nil {
@@ -168,10 +245,10 @@ func (l *textLexer) readQuotedString() (string, error) {
 			return sb.String(), nil
 		}
 
-		if r == '\\' {
+		if r == '\\' && !l.noEscapes {
 			next, err := l.readRune()
 			if err == io.EOF {
-				return "", ErrUnexpectedEOFInEscapeSequence
+				return l.recoverEOF(sb.String(), ErrUnexpectedEOFInEscapeSequence)
 			}
 
 			if err != nil {
@@ -180,24 +257,76 @@ func (l *textLexer) readQuotedString() (string, error) {
 
 			switch next {
 			case 'n':
-				sb.WriteRune('\n')
+				r = '\n'
 			case 't':
-				sb.WriteRune('\t')
+				r = '\t'
 			case 'r':
-				sb.WriteRune('\r')
-			case '\\':
-				sb.WriteRune('\\')
-			case '"':
-				sb.WriteRune('"')
+				r = '\r'
+			case '\\', '"':
+				r = next
 			default:
-				sb.WriteRune('\\')
-				sb.WriteRune(next)
+				// Unknown escapes keep the backslash.
+				if err := l.appendRune(&sb, '\\'); err != nil {
+					return "", err
+				}
+
+				r = next
 			}
+		}
 
-			continue
+		if err := l.appendRune(&sb, r); err != nil {
+			return "", err
 		}
+	}
+}
 
-		sb.WriteRune(r)
+// recoverEOF handles input ending inside a token: with recovery enabled it reports
+// err and returns the partial value as the token, otherwise it fails with err.
+func (l *textLexer) recoverEOF(value string, err error) (string, error) {
+	err = l.errorHere(err)
+	if l.recover == nil {
+		return "", err
+	}
+
+	l.recover(err)
+	return value, nil
+}
+
+// errorHere wraps err in a ParseError at the current lexer position.
+func (l *textLexer) errorHere(err error) error {
+	return &ParseError{Err: err, Offset: l.offset, Line: l.line, Col: l.col + 1}
+}
+
+// errorAt wraps err in a ParseError at the token start.
+func (t textToken) errorAt(err error) error {
+	return &ParseError{Err: err, Offset: t.start, Line: t.line, Col: t.col + 1}
+}
+
+// readCondition reads one bracketed conditional and returns its trimmed body.
+func (l *textLexer) readCondition() (string, error) {
+	if _, err := l.readRune(); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for {
+		r, err := l.readRune()
+		if err == io.EOF {
+			value, err := l.recoverEOF(sb.String(), ErrUnexpectedEOFInCondition)
+			return strings.TrimSpace(value), err
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		if r == ']' {
+			return strings.TrimSpace(sb.String()), nil
+		}
+
+		if err := l.appendRune(&sb, r); err != nil {
+			return "", err
+		}
 	}
 }
 
@@ -222,7 +351,9 @@ func (l *textLexer) readUnquotedString() (string, error) {
 			return "", err
 		}
 
-		sb.WriteRune(r)
+		if err := l.appendRune(&sb, r); err != nil {
+			return "", err
+		}
 	}
 
 	return sb.String(), nil
@@ -251,7 +382,7 @@ func (l *textLexer) nextToken() (textToken, error) {
 
 		r, err := l.peekRune()
 		if err == io.EOF {
-			return textToken{kind: textTokenEOF, line: l.line, col: l.col}, nil
+			return textToken{kind: textTokenEOF, start: l.offset, end: l.offset, line: l.line, col: l.col}, nil
 		}
 
 		if err != nil {
@@ -260,6 +391,7 @@ func (l *textLexer) nextToken() (textToken, error) {
 
 		startLine := l.line
 		startCol := l.col
+		startOffset := l.offset
 
 		switch r {
 		case '/':
@@ -283,26 +415,33 @@ func (l *textLexer) nextToken() (textToken, error) {
 				return textToken{}, err
 			}
 
-			return textToken{kind: textTokenString, value: "/" + rest, line: startLine, col: startCol}, nil
+			return textToken{kind: textTokenString, value: "/" + rest, start: startOffset, end: l.offset, line: startLine, col: startCol}, nil
 		case '{':
 			if _, err := l.readRune(); err != nil {
 				return textToken{}, err
 			}
 
-			return textToken{kind: textTokenLBrace, value: "{", line: startLine, col: startCol}, nil
+			return textToken{kind: textTokenLBrace, value: "{", start: startOffset, end: l.offset, line: startLine, col: startCol}, nil
 		case '}':
 			if _, err := l.readRune(); err != nil {
 				return textToken{}, err
 			}
 
-			return textToken{kind: textTokenRBrace, value: "}", line: startLine, col: startCol}, nil
+			return textToken{kind: textTokenRBrace, value: "}", start: startOffset, end: l.offset, line: startLine, col: startCol}, nil
+		case '[':
+			value, err := l.readCondition()
+			if err != nil {
+				return textToken{}, err
+			}
+
+			return textToken{kind: textTokenCondition, value: value, start: startOffset, end: l.offset, line: startLine, col: startCol}, nil
 		case '"':
 			value, err := l.readQuotedString()
 			if err != nil {
 				return textToken{}, err
 			}
 
-			return textToken{kind: textTokenString, value: value, line: startLine, col: startCol}, nil
+			return textToken{kind: textTokenString, value: value, start: startOffset, end: l.offset, line: startLine, col: startCol, quoted: true}, nil
 		default:
 			value, err := l.readUnquotedString()
 			if err != nil {
@@ -310,10 +449,10 @@ func (l *textLexer) nextToken() (textToken, error) {
 			}
 
 			if value == "" {
-				return textToken{}, fmt.Errorf("%w at line %d, col %d", ErrUnexpectedCharacter, startLine, startCol)
+				return textToken{}, &ParseError{Err: ErrUnexpectedCharacter, Offset: startOffset, Line: startLine, Col: startCol + 1}
 			}
 
-			return textToken{kind: textTokenString, value: value, line: startLine, col: startCol}, nil
+			return textToken{kind: textTokenString, value: value, start: startOffset, end: l.offset, line: startLine, col: startCol}, nil
 		}
 	}
 }
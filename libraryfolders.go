@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// libraryFoldersRootKey is the root key of Steam libraryfolders.vdf.
+const libraryFoldersRootKey = "libraryfolders"
+
+// LibraryFolder is one Steam library entry of libraryfolders.vdf.
+type LibraryFolder struct {
+	// AppIDs lists the installed app ids in source order; old layouts carry none.
+	AppIDs []uint32 `json:"app_ids,omitempty" yaml:"app_ids,omitempty"`
+	// Path is the library root directory.
+	Path string `json:"path" yaml:"path"`
+	// Label is the user-visible library name.
+	Label string `json:"label,omitempty" yaml:"label,omitempty"`
+	// ContentID identifies the library volume.
+	ContentID int64 `json:"content_id,omitempty" yaml:"content_id,omitempty"`
+	// TotalSize is the volume size in bytes, or zero when unknown.
+	TotalSize uint64 `json:"total_size,omitempty" yaml:"total_size,omitempty"`
+	// Index is the numeric key of the entry.
+	Index int `json:"index" yaml:"index"`
+}
+
+// ReadLibraryFolders decodes a libraryfolders.vdf stream into its library entries.
+func ReadLibraryFolders(r io.Reader) ([]LibraryFolder, error) {
+	doc, err := NewDecoder(r, DecodeOptions{Format: FormatText}).DecodeDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	return LibraryFoldersFromDocument(doc)
+}
+
+// LibraryFoldersFromDocument reads the library entries of a decoded libraryfolders.vdf document.
+// Both layouts are accepted: current clients write an object per library, while
+// older ones wrote the path directly under the numeric key.
+// Non-numeric keys such as "contentstatsid" are skipped.
+func LibraryFoldersFromDocument(doc *Document) ([]LibraryFolder, error) {
+	var root *Node
+	if doc != nil {
+		root = fieldChild(documentObject(doc), libraryFoldersRootKey)
+	}
+
+	if root == nil || root.Kind != NodeObject {
+		return nil, fmt.Errorf("%w: missing %q object", ErrValueConversion, libraryFoldersRootKey)
+	}
+
+	folders := make([]LibraryFolder, 0, len(root.Children))
+	for _, entry := range root.Children {
+		if entry == nil {
+			continue
+		}
+
+		index, err := strconv.Atoi(entry.Key)
+		if err != nil || index < 0 {
+			continue
+		}
+
+		folder := LibraryFolder{Index: index}
+		if entry.Kind == NodeObject {
+			err = folder.readEntry(entry)
+		} else {
+			folder.Path, err = steamText(entry)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("library %q: %w", entry.Key, err)
+		}
+
+		folders = append(folders, folder)
+	}
+
+	return folders, nil
+}
+
+// readEntry fills the folder from a current-layout library object.
+func (f *LibraryFolder) readEntry(entry *Node) error {
+	for _, child := range entry.Children {
+		if child == nil {
+			continue
+		}
+
+		var err error
+		switch strings.ToLower(child.Key) {
+		case "path":
+			f.Path, err = steamText(child)
+		case "label":
+			f.Label, err = steamText(child)
+		case "contentid":
+			f.ContentID, err = steamInt64(child)
+		case "totalsize":
+			f.TotalSize, err = steamUint64(child)
+		case "apps":
+			f.AppIDs, err = libraryAppIDs(child)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// libraryAppIDs reads the app ids keying the children of the apps object.
+func libraryAppIDs(node *Node) ([]uint32, error) {
+	if node.Kind != NodeObject {
+		return nil, fmt.Errorf("%w: key %q is not an object", ErrValueConversion, node.Key)
+	}
+
+	ids := make([]uint32, 0, len(node.Children))
+	for _, child := range node.Children {
+		if child == nil {
+			continue
+		}
+
+		id, err := strconv.ParseUint(child.Key, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: app key %q is not an app id", ErrValueConversion, child.Key)
+		}
+
+		ids = append(ids, uint32(id))
+	}
+
+	return ids, nil
+}
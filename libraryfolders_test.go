@@ -0,0 +1,80 @@
+package vdf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadLibraryFolders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  []LibraryFolder
+	}{
+		{
+			name: "current",
+			input: `"libraryfolders"
+{
+	"contentstatsid"	"-1234"
+	"0"
+	{
+		"path"		"C:\\Program Files (x86)\\Steam"
+		"label"		""
+		"contentid"		"-5907520591528364045"
+		"totalsize"		"0"
+		"apps"
+		{
+			"228980"		"251822471"
+			"440"		"26749236131"
+		}
+	}
+	"1"
+	{
+		"path"		"D:\\SteamLibrary"
+		"label"		"Games"
+		"contentid"		"42"
+		"totalsize"		"1000204886016"
+		"apps"
+		{
+		}
+	}
+}`,
+			want: []LibraryFolder{
+				{Index: 0, Path: `C:\Program Files (x86)\Steam`, ContentID: -5907520591528364045, AppIDs: []uint32{228980, 440}},
+				{Index: 1, Path: `D:\SteamLibrary`, Label: "Games", ContentID: 42, TotalSize: 1000204886016, AppIDs: []uint32{}},
+			},
+		},
+		{
+			name: "legacy",
+			input: `"LibraryFolders"
+{
+	"TimeNextStatsReport"		"1561832478"
+	"ContentStatsID"		"-158337411110787451"
+	"1"		"D:\\Games\\Steam"
+	"2"		"E:\\SteamLibrary"
+}`,
+			want: []LibraryFolder{
+				{Index: 1, Path: `D:\Games\Steam`},
+				{Index: 2, Path: `E:\SteamLibrary`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ReadLibraryFolders(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ReadLibraryFolders() returned error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ReadLibraryFolders() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
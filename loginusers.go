@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// loginUsersRootKey is the root key of Steam loginusers.vdf.
+const loginUsersRootKey = "users"
+
+// LoginUser is one account entry of loginusers.vdf.
+type LoginUser struct {
+	// Extra holds keys without a dedicated field, kept in source order for write-back.
+	Extra []*Node `json:"extra,omitempty" yaml:"extra,omitempty"`
+	// AccountName is the login name.
+	AccountName string `json:"account_name" yaml:"account_name"`
+	// PersonaName is the display name.
+	PersonaName string `json:"persona_name" yaml:"persona_name"`
+	// Timestamp is the Unix time of the last login.
+	Timestamp uint32 `json:"timestamp" yaml:"timestamp"`
+	// RememberPassword keeps the login token.
+	RememberPassword bool `json:"remember_password" yaml:"remember_password"`
+	// WantsOfflineMode starts the client in offline mode.
+	WantsOfflineMode bool `json:"wants_offline_mode" yaml:"wants_offline_mode"`
+	// SkipOfflineModeWarning suppresses the offline mode prompt.
+	SkipOfflineModeWarning bool `json:"skip_offline_mode_warning" yaml:"skip_offline_mode_warning"`
+	// AllowAutoLogin allows signing in without a prompt.
+	AllowAutoLogin bool `json:"allow_auto_login" yaml:"allow_auto_login"`
+	// MostRecent marks the account used last.
+	MostRecent bool `json:"most_recent" yaml:"most_recent"`
+}
+
+// ReadLoginUsers decodes a loginusers.vdf stream into accounts keyed by SteamID64.
+func ReadLoginUsers(r io.Reader) (map[uint64]LoginUser, error) {
+	doc, err := NewDecoder(r, DecodeOptions{Format: FormatText}).DecodeDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	return LoginUsersFromDocument(doc)
+}
+
+// WriteLoginUsers encodes accounts as loginusers.vdf text.
+func WriteLoginUsers(w io.Writer, users map[uint64]LoginUser) error {
+	return NewEncoder(w, EncodeOptions{Format: FormatText}).EncodeDocument(LoginUsersDocument(users))
+}
+
+// LoginUsersFromDocument reads the accounts of a decoded loginusers.vdf document.
+// Account keys are SteamID64 values, which do not fit a uint32.
+func LoginUsersFromDocument(doc *Document) (map[uint64]LoginUser, error) {
+	var root *Node
+	if doc != nil {
+		root = fieldChild(documentObject(doc), loginUsersRootKey)
+	}
+
+	if root == nil || root.Kind != NodeObject {
+		return nil, fmt.Errorf("%w: missing %q object", ErrValueConversion, loginUsersRootKey)
+	}
+
+	users := make(map[uint64]LoginUser, len(root.Children))
+	for _, entry := range root.Children {
+		if entry == nil {
+			continue
+		}
+
+		id, err := strconv.ParseUint(entry.Key, 10, 64)
+		if err != nil || entry.Kind != NodeObject {
+			return nil, fmt.Errorf("%w: user %q is not a SteamID64 object", ErrValueConversion, entry.Key)
+		}
+
+		user, err := loginUserFromNode(entry)
+		if err != nil {
+			return nil, fmt.Errorf("user %q: %w", entry.Key, err)
+		}
+
+		users[id] = user
+	}
+
+	return users, nil
+}
+
+// LoginUsersDocument builds a loginusers.vdf document with accounts sorted by SteamID64.
+func LoginUsersDocument(users map[uint64]LoginUser) *Document {
+	root := NewObjectNode(loginUsersRootKey)
+	for _, id := range slices.Sorted(maps.Keys(users)) {
+		user := users[id]
+		entry := NewObjectNode(strconv.FormatUint(id, 10))
+		entry.Add(NewStringNode("AccountName", user.AccountName))
+		entry.Add(NewStringNode("PersonaName", user.PersonaName))
+		entry.Add(NewStringNode("RememberPassword", loginUserFlag(user.RememberPassword)))
+		entry.Add(NewStringNode("WantsOfflineMode", loginUserFlag(user.WantsOfflineMode)))
+		entry.Add(NewStringNode("SkipOfflineModeWarning", loginUserFlag(user.SkipOfflineModeWarning)))
+		entry.Add(NewStringNode("AllowAutoLogin", loginUserFlag(user.AllowAutoLogin)))
+		entry.Add(NewStringNode("MostRecent", loginUserFlag(user.MostRecent)))
+		entry.Add(NewStringNode("Timestamp", strconv.FormatUint(uint64(user.Timestamp), 10)))
+		for _, extra := range user.Extra {
+			entry.Add(cloneNode(extra))
+		}
+
+		root.Add(entry)
+	}
+
+	doc := NewDocumentWithFormat(FormatText)
+	doc.AddRoot(root)
+	return doc
+}
+
+// loginUserFromNode maps one account object to a LoginUser.
+func loginUserFromNode(entry *Node) (LoginUser, error) {
+	var user LoginUser
+	for _, child := range entry.Children {
+		if child == nil {
+			continue
+		}
+
+		var err error
+		switch strings.ToLower(child.Key) {
+		case "accountname":
+			user.AccountName, err = steamText(child)
+		case "personaname":
+			user.PersonaName, err = steamText(child)
+		case "timestamp":
+			user.Timestamp, err = steamUint32(child)
+		case "rememberpassword":
+			user.RememberPassword, err = steamBool(child)
+		case "wantsofflinemode":
+			user.WantsOfflineMode, err = steamBool(child)
+		case "skipofflinemodewarning":
+			user.SkipOfflineModeWarning, err = steamBool(child)
+		case "allowautologin":
+			user.AllowAutoLogin, err = steamBool(child)
+		case "mostrecent":
+			user.MostRecent, err = steamBool(child)
+		default:
+			user.Extra = append(user.Extra, cloneNode(child))
+		}
+
+		if err != nil {
+			return LoginUser{}, err
+		}
+	}
+
+	return user, nil
+}
+
+// loginUserFlag formats a flag as the "0"/"1" text loginusers.vdf stores.
+func loginUserFlag(value bool) string {
+	return strconv.FormatUint(uint64(steamFlag(value)), 10)
+}
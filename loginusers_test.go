@@ -0,0 +1,68 @@
+package vdf
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoginUsersRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	input := `"users"
+{
+	"76561197960287930"
+	{
+		"AccountName"		"gaben"
+		"PersonaName"		"Rabscuttle"
+		"RememberPassword"		"1"
+		"WantsOfflineMode"		"0"
+		"SkipOfflineModeWarning"		"0"
+		"AllowAutoLogin"		"1"
+		"MostRecent"		"1"
+		"Timestamp"		"1700000000"
+	}
+	"76561198000000001"
+	{
+		"AccountName"		"other"
+		"PersonaName"		"Other"
+		"mostrecent"		"0"
+	}
+}`
+
+	users, err := ReadLoginUsers(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadLoginUsers() returned error: %v", err)
+	}
+
+	want := map[uint64]LoginUser{
+		76561197960287930: {
+			AccountName:      "gaben",
+			PersonaName:      "Rabscuttle",
+			Timestamp:        1700000000,
+			RememberPassword: true,
+			AllowAutoLogin:   true,
+			MostRecent:       true,
+		},
+		76561198000000001: {AccountName: "other", PersonaName: "Other"},
+	}
+
+	if !reflect.DeepEqual(users, want) {
+		t.Fatalf("ReadLoginUsers() = %+v, want %+v", users, want)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteLoginUsers(&buf, users); err != nil {
+		t.Fatalf("WriteLoginUsers() returned error: %v", err)
+	}
+
+	again, err := ReadLoginUsers(&buf)
+	if err != nil {
+		t.Fatalf("ReadLoginUsers() after write returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(again, want) {
+		t.Fatalf("round trip = %+v, want %+v", again, want)
+	}
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+)
+
+// Unmarshal decodes VDF data into the value pointed to by v.
+// The input format is auto-detected.
+// Document roots are matched like the children of an object, so a struct
+// for a file with an "AppState" root needs an AppState field.
+// Struct fields match keys by their `vdf` tag or Go name, preferring an exact
+// match over a case-insensitive one; keys without a field are ignored.
+func Unmarshal(data []byte, v any) error {
+	return NewDecoder(bytes.NewReader(data), DecodeOptions{}).Decode(v)
+}
+
+// Decode decodes the full input stream and stores it in the value pointed to by v.
+// Mapping rules follow Unmarshal.
+func (d *Decoder) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("%w: decode target must be a non-nil pointer, got %T", ErrUnsupportedGoType, v)
+	}
+
+	doc, err := d.DecodeDocument()
+	if err != nil {
+		return err
+	}
+
+	return decodeNodeValue(documentObject(doc), rv.Elem())
+}
+
+// documentObject wraps document roots into a keyless object node sharing the roots slice.
+func documentObject(doc *Document) *Node {
+	return &Node{Kind: NodeObject, Children: doc.Roots}
+}
+
+// Marshal encodes a Go struct or string-keyed map as VDF.
+// Fields and map entries become document roots; nested structs and maps become
+// objects, and slices or arrays become repeated keys in order.
+// Unsigned values that fit uint32 are stored as uint32 leaves and other scalars
+// as strings, with bools written as "1" and "0".
+// Without options the output is text; options are honored like Encoder.
+func Marshal(v any, opts ...EncodeOptions) ([]byte, error) {
+	effective := EncodeOptions{Format: FormatText}
+	if len(opts) > 0 {
+		effective = opts[0]
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, effective).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Encode writes v as a complete document following Marshal mapping rules.
+func (e *Encoder) Encode(v any) error {
+	doc, err := valueDocument(v)
+	if err != nil {
+		return err
+	}
+
+	return e.EncodeDocument(doc)
+}
+
+// valueDocument builds a document whose roots are the mapped entries of v.
+func valueDocument(v any) (*Document, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("%w: nil %T", ErrUnsupportedGoType, v)
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct && rv.Kind() != reflect.Map {
+		return nil, fmt.Errorf("%w: encode requires a struct or map, got %T", ErrUnsupportedGoType, v)
+	}
+
+	// An addressable copy lets fields with pointer-receiver MarshalVDF methods use them.
+	if !rv.CanAddr() {
+		addressable := reflect.New(rv.Type()).Elem()
+		addressable.Set(rv)
+		rv = addressable
+	}
+
+	root := &Node{Kind: NodeObject}
+	if err := encodeNodeValue(root, rv); err != nil {
+		return nil, err
+	}
+
+	return &Document{Roots: root.Children}, nil
+}
@@ -0,0 +1,358 @@
+package vdf
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalStruct(t *testing.T) {
+	t.Parallel()
+
+	type userConfig struct {
+		Language string `vdf:"language"`
+		Beta     bool   `vdf:"betakey"`
+	}
+
+	type appState struct {
+		Config    *userConfig       `vdf:"UserConfig"`
+		Depots    map[string]string `vdf:"InstalledDepots"`
+		Name      string
+		AppID     uint32 `vdf:"appid"`
+		SizeOnDsk int64  `vdf:"SizeOnDisk"`
+	}
+
+	var manifest struct {
+		AppState appState
+	}
+
+	input := `"AppState"
+{
+	"appid"		"730"
+	"name"		"Counter-Strike 2"
+	"SizeOnDisk"		"35000000000"
+	"InstalledDepots"
+	{
+		"731"		"manifest-a"
+		"732"		"manifest-b"
+	}
+	"UserConfig"
+	{
+		"language"		"english"
+		"betakey"		"1"
+	}
+	"unknown"		"ignored"
+}`
+
+	if err := Unmarshal([]byte(input), &manifest); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+
+	got := manifest.AppState
+	if got.AppID != 730 || got.Name != "Counter-Strike 2" || got.SizeOnDsk != 35000000000 {
+		t.Fatalf("unexpected scalars: %+v", got)
+	}
+
+	if got.Config == nil || got.Config.Language != "english" || !got.Config.Beta {
+		t.Fatalf("unexpected user config: %+v", got.Config)
+	}
+
+	if len(got.Depots) != 2 || got.Depots["732"] != "manifest-b" {
+		t.Fatalf("unexpected depots: %v", got.Depots)
+	}
+
+	bin, err := AppendBinary(nil, mustParseString(t, input), EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	var fromBinary map[string]any
+	if err := NewDecoder(bytes.NewReader(bin), DecodeOptions{}).Decode(&fromBinary); err != nil {
+		t.Fatalf("Decode(binary) returned error: %v", err)
+	}
+
+	if _, ok := fromBinary["AppState"].(Map); !ok {
+		t.Fatalf("Decode(binary) = %#v, want AppState object", fromBinary)
+	}
+
+	if err := Unmarshal([]byte(input), manifest); !errors.Is(err, ErrUnsupportedGoType) {
+		t.Fatalf("Unmarshal(non-pointer) error = %v, want ErrUnsupportedGoType", err)
+	}
+
+	var bad struct {
+		AppState struct {
+			AppID uint8 `vdf:"appid"`
+		}
+	}
+
+	if err := Unmarshal([]byte(input), &bad); !errors.Is(err, ErrValueConversion) {
+		t.Fatalf("Unmarshal(overflow) error = %v, want ErrValueConversion", err)
+	}
+}
+
+func TestMarshalStruct(t *testing.T) {
+	t.Parallel()
+
+	type shortcut struct {
+		Tags    map[string]string `vdf:"tags"`
+		AppName string
+		Exe     string
+		AppID   uint32 `vdf:"appid"`
+		Hidden  bool   `vdf:"IsHidden"`
+	}
+
+	value := struct {
+		Shortcuts map[string]shortcut `vdf:"shortcuts"`
+		Paths     []string            `vdf:"path"`
+	}{
+		Shortcuts: map[string]shortcut{
+			"0": {AppName: "Game", Exe: `"C:\game.exe"`, AppID: 0xFF000001, Tags: map[string]string{"0": "favorite"}},
+		},
+		Paths: []string{"/a", "/b"},
+	}
+
+	got, err := Marshal(value, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+
+	want := `"shortcuts" { "0" { "tags" { "0" "favorite" } "AppName" "Game" "Exe" "\"C:\\game.exe\"" "appid" "4278190081" "IsHidden" "0" } } "path" "/a" "path" "/b" `
+	if string(got) != want {
+		t.Fatalf("Marshal() = %q, want %q", got, want)
+	}
+
+	bin, err := Marshal(&value, EncodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("Marshal(binary) returned error: %v", err)
+	}
+
+	doc, err := ParseBytes(bin, DecodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("ParseBytes() returned error: %v", err)
+	}
+
+	appid := doc.Roots[0].First("0").First("appid")
+	if appid.Kind != NodeUint32 || *appid.Uint32Value != 0xFF000001 {
+		t.Fatalf("binary appid = %+v, want uint32 leaf", appid)
+	}
+
+	if _, err := Marshal([]string{"x"}); !errors.Is(err, ErrUnsupportedGoType) {
+		t.Fatalf("Marshal(slice) error = %v, want ErrUnsupportedGoType", err)
+	}
+}
+
+func TestMarshalTagOptions(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Secret  string            `vdf:"-"`
+		Dash    string            `vdf:"-,"`
+		Name    string            `vdf:"AppName,omitempty"`
+		Extra   map[string]string `vdf:",omitempty"`
+		Tags    []string          `vdf:"tag,omitempty"`
+		Count   int               `vdf:"count,omitempty"`
+		Enabled bool              `vdf:"enabled,omitempty"`
+		Kept    int               `vdf:"kept"`
+	}
+
+	got, err := Marshal(struct{ Config config }{Config: config{Secret: "s", Dash: "d"}}, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+
+	want := `"Config" { "-" "d" "kept" "0" } `
+	if string(got) != want {
+		t.Fatalf("Marshal() = %q, want %q", got, want)
+	}
+
+	var decoded struct{ Config config }
+	if err := Unmarshal([]byte(`"Config" { "AppName" "x" "Secret" "s" "-" "d" }`), &decoded); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+
+	if decoded.Config.Name != "x" || decoded.Config.Secret != "" || decoded.Config.Dash != "d" {
+		t.Fatalf("Unmarshal() = %+v", decoded.Config)
+	}
+}
+
+func TestUnmarshalRepeatedKeys(t *testing.T) {
+	t.Parallel()
+
+	type folder struct {
+		Path string `vdf:"path"`
+	}
+
+	var got struct {
+		Root struct {
+			Paths   []string            `vdf:"path"`
+			Folders []folder            `vdf:"folder"`
+			Pair    [2]uint32           `vdf:"n"`
+			Groups  map[string][]string `vdf:"groups"`
+			Single  string              `vdf:"single"`
+		} `vdf:"root"`
+	}
+
+	input := `"root" {
+		"path" "/a" "folder" { "path" "/x" } "path" "/b" "folder" { "path" "/y" }
+		"n" "1" "n" "2" "n" "3"
+		"groups" { "g" "1" "h" "2" "g" "3" }
+		"single" "first" "single" "second"
+	}`
+
+	if err := Unmarshal([]byte(input), &got); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+
+	root := got.Root
+	if !slices.Equal(root.Paths, []string{"/a", "/b"}) {
+		t.Fatalf("Paths = %v", root.Paths)
+	}
+
+	if len(root.Folders) != 2 || root.Folders[1].Path != "/y" {
+		t.Fatalf("Folders = %+v", root.Folders)
+	}
+
+	if root.Pair != [2]uint32{1, 2} {
+		t.Fatalf("Pair = %v", root.Pair)
+	}
+
+	if !slices.Equal(root.Groups["g"], []string{"1", "3"}) || !slices.Equal(root.Groups["h"], []string{"2"}) {
+		t.Fatalf("Groups = %v", root.Groups)
+	}
+
+	if root.Single != "first" {
+		t.Fatalf("Single = %q, want first occurrence", root.Single)
+	}
+
+	out, err := Marshal(got, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+
+	var again struct {
+		Root struct {
+			Paths []string `vdf:"path"`
+		} `vdf:"root"`
+	}
+
+	if err := Unmarshal(out, &again); err != nil || !slices.Equal(again.Root.Paths, root.Paths) {
+		t.Fatalf("round-trip Paths = %v, err = %v", again.Root.Paths, err)
+	}
+}
+
+// testSteamID stores a 64-bit SteamID as its 32-bit account ID.
+type testSteamID uint64
+
+func (id testSteamID) MarshalVDF() (*Node, error) {
+	return NewUint32Node("", uint32(id&0xFFFFFFFF)), nil
+}
+
+func (id *testSteamID) UnmarshalVDF(node *Node) error {
+	text, err := textValueForNode(node)
+	if err != nil {
+		return err
+	}
+
+	account, err := strconv.ParseUint(text, 10, 32)
+	if err != nil {
+		return err
+	}
+
+	*id = testSteamID(76561197960265728 + account)
+	return nil
+}
+
+// testLaunchOptions stores launch options as one space-separated string.
+type testLaunchOptions []string
+
+func (o *testLaunchOptions) MarshalVDF() (*Node, error) {
+	return NewStringNode("", strings.Join(*o, " ")), nil
+}
+
+func (o *testLaunchOptions) UnmarshalVDF(node *Node) error {
+	text, err := textValueForNode(node)
+	if err != nil {
+		return err
+	}
+
+	*o = strings.Fields(text)
+	return nil
+}
+
+func TestMarshalerInterfaces(t *testing.T) {
+	t.Parallel()
+
+	type user struct {
+		Owner   *testSteamID      `vdf:"owner"`
+		Options testLaunchOptions `vdf:"LaunchOptions"`
+		ID      testSteamID       `vdf:"id"`
+	}
+
+	var decoded struct{ User user }
+	input := `"User" { "id" "22202" "owner" "1" "LaunchOptions" "-novid -high" }`
+	if err := Unmarshal([]byte(input), &decoded); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+
+	if decoded.User.ID != 76561197960287930 || decoded.User.Owner == nil || *decoded.User.Owner != 76561197960265729 {
+		t.Fatalf("decoded ids = %d, %v", decoded.User.ID, decoded.User.Owner)
+	}
+
+	if !slices.Equal(decoded.User.Options, []string{"-novid", "-high"}) {
+		t.Fatalf("decoded options = %v", decoded.User.Options)
+	}
+
+	got, err := Marshal(decoded, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+
+	want := `"User" { "owner" "1" "LaunchOptions" "-novid -high" "id" "22202" } `
+	if string(got) != want {
+		t.Fatalf("Marshal() = %q, want %q", got, want)
+	}
+}
+
+func TestUnmarshalByteArray(t *testing.T) {
+	t.Parallel()
+
+	type keys struct {
+		Hash [4]byte `vdf:"hash"`
+	}
+
+	value := keys{Hash: [4]byte{'a', 'b', 'c', 'd'}}
+	data, err := Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+
+	var got keys
+	if err := Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() returned error: %v", err)
+	}
+
+	if got != value {
+		t.Fatalf("Unmarshal() = %+v, want %+v", got, value)
+	}
+
+	if err := Unmarshal([]byte(`"hash" "abc"`), &got); !errors.Is(err, ErrValueConversion) {
+		t.Fatalf("Unmarshal(short array) error = %v, want ErrValueConversion", err)
+	}
+}
+
+func TestDecodeInterfaceNilPayload(t *testing.T) {
+	t.Parallel()
+
+	out := any("stale")
+	if err := decodeNodeValue(&Node{Key: "k", Kind: NodeString}, reflect.ValueOf(&out).Elem()); err != nil {
+		t.Fatalf("decodeNodeValue() returned error: %v", err)
+	}
+
+	if out != nil {
+		t.Fatalf("decodeNodeValue() = %#v, want nil", out)
+	}
+}
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// MergeStrategy controls how merged entries interact with existing keys.
+// Objects present on both sides are always merged recursively.
+type MergeStrategy uint8
+
+const (
+	// MergeReplace overwrites existing entries with incoming ones.
+	MergeReplace MergeStrategy = iota
+	// MergeKeepExisting adds only entries whose keys are missing.
+	MergeKeepExisting
+	// MergeAppend adds incoming leaves as duplicate keys next to existing ones.
+	MergeAppend
+)
+
+// MergeMapAt converts m to nodes and deep-merges them into the object at path,
+// creating the path when missing. Incoming keys are merged in sorted order.
+func (d *Document) MergeMapAt(path string, m Map, strategy MergeStrategy) error {
+	if strategy > MergeAppend {
+		return fmt.Errorf("%w: %d", ErrInvalidMergeStrategy, strategy)
+	}
+
+	children, err := mapToNodeChildren(m)
+	if err != nil {
+		return err
+	}
+
+	target, err := d.EnsurePath(path)
+	if err != nil {
+		return err
+	}
+
+	if target.Kind != NodeObject {
+		return fmt.Errorf("%w: %q is not an object", ErrPathNotObject, target.Key)
+	}
+
+	target.Children = mergeChildren(target.Children, children, strategy)
+	return nil
+}
+
+// ApplyDefaults fills entries missing from d with deep copies taken from template.
+// Existing values are never overwritten; objects present on both sides are
+// filled recursively, and new entries are appended in template order.
+func (d *Document) ApplyDefaults(template *Document) error {
+	if d == nil {
+		return fmt.Errorf("%w: nil document", ErrInvalidNodeState)
+	}
+
+	if template == nil {
+		return nil
+	}
+
+	defaults := make([]*Node, 0, len(template.Roots))
+	for _, root := range template.Roots {
+		if root != nil {
+			defaults = append(defaults, cloneNode(root))
+		}
+	}
+
+	d.Roots = mergeChildren(d.Roots, defaults, MergeKeepExisting)
+	return nil
+}
+
+// mergeChildren merges incoming nodes into an existing sibling list and returns it.
+// Incoming nodes are attached directly, so callers pass nodes they own.
+func mergeChildren(existing, incoming []*Node, strategy MergeStrategy) []*Node {
+	for _, in := range incoming {
+		if in == nil {
+			continue
+		}
+
+		var current *Node
+		for _, node := range existing {
+			if node != nil && node.Key == in.Key {
+				current = node
+				break
+			}
+		}
+
+		switch {
+		case current == nil:
+			existing = append(existing, in)
+		case current.Kind == NodeObject && in.Kind == NodeObject:
+			current.Children = mergeChildren(current.Children, in.Children, strategy)
+		case strategy == MergeReplace:
+			replaceNodeValue(current, in)
+		case strategy == MergeAppend:
+			existing = append(existing, in)
+		}
+	}
+
+	return existing
+}
+
+// cloneNode returns a deep copy of node with its subtree.
+func cloneNode(node *Node) *Node {
+	if node == nil {
+		return nil
+	}
+
+	out := &Node{Key: node.Key, Condition: node.Condition, Layout: node.Layout, Kind: node.Kind, RawType: node.RawType}
+	out.KeyUnquoted, out.ValueUnquoted = node.KeyUnquoted, node.ValueUnquoted
+	if node.StringValue != nil {
+		value := *node.StringValue
+		out.StringValue = &value
+	}
+
+	if node.Uint32Value != nil {
+		value := *node.Uint32Value
+		out.Uint32Value = &value
+	}
+
+	if node.Float32Value != nil {
+		value := *node.Float32Value
+		out.Float32Value = &value
+	}
+
+	if node.Uint64Value != nil {
+		value := *node.Uint64Value
+		out.Uint64Value = &value
+	}
+
+	if node.Int64Value != nil {
+		value := *node.Int64Value
+		out.Int64Value = &value
+	}
+
+	if node.ColorValue != nil {
+		value := *node.ColorValue
+		out.ColorValue = &value
+	}
+
+	if node.RawValue != nil {
+		out.RawValue = bytes.Clone(node.RawValue)
+	}
+
+	if node.Children != nil {
+		out.Children = make([]*Node, len(node.Children))
+		for i, child := range node.Children {
+			out.Children[i] = cloneNode(child)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,89 @@
+package vdf
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDocumentMergeMapAt(t *testing.T) {
+	t.Parallel()
+
+	const input = `"cfg" { "apps" { "440" { "name" "tf2" "launch" "-novid" } } }`
+	patch := Map{
+		"440": Map{"launch": "-high", "beta": "1"},
+		"570": Map{"name": "dota"},
+	}
+
+	tests := []struct {
+		name     string
+		strategy MergeStrategy
+		want     string
+	}{
+		{
+			name:     "replace",
+			strategy: MergeReplace,
+			want:     `"cfg" { "apps" { "440" { "name" "tf2" "launch" "-high" "beta" "1" } "570" { "name" "dota" } } } `,
+		},
+		{
+			name:     "keep existing",
+			strategy: MergeKeepExisting,
+			want:     `"cfg" { "apps" { "440" { "name" "tf2" "launch" "-novid" "beta" "1" } "570" { "name" "dota" } } } `,
+		},
+		{
+			name:     "append",
+			strategy: MergeAppend,
+			want:     `"cfg" { "apps" { "440" { "name" "tf2" "launch" "-novid" "beta" "1" "launch" "-high" } "570" { "name" "dota" } } } `,
+		},
+	}
+
+	for _, tt := range tests {
+		doc := mustParseString(t, input)
+		if err := doc.MergeMapAt("cfg/apps", patch, tt.strategy); err != nil {
+			t.Fatalf("MergeMapAt(%s) returned error: %v", tt.name, err)
+		}
+
+		got, err := AppendText(nil, doc, EncodeOptions{Compact: true})
+		if err != nil {
+			t.Fatalf("AppendText(%s) returned error: %v", tt.name, err)
+		}
+
+		if string(got) != tt.want {
+			t.Fatalf("MergeMapAt(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	doc := mustParseString(t, input)
+	if err := doc.MergeMapAt("cfg/apps/440/name", patch, MergeReplace); !errors.Is(err, ErrPathNotObject) {
+		t.Fatalf("MergeMapAt(leaf) error = %v, want ErrPathNotObject", err)
+	}
+
+	if err := doc.MergeMapAt("cfg", patch, MergeStrategy(9)); !errors.Is(err, ErrInvalidMergeStrategy) {
+		t.Fatalf("MergeMapAt(bad strategy) error = %v, want ErrInvalidMergeStrategy", err)
+	}
+}
+
+func TestDocumentApplyDefaults(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"cfg" { "volume" "3" "video" { "width" "1920" } }`)
+	template := mustParseString(t, `"cfg" { "volume" "10" "video" { "width" "1280" "height" "720" } "lang" "english" } "extra" { "k" "v" }`)
+
+	if err := doc.ApplyDefaults(template); err != nil {
+		t.Fatalf("ApplyDefaults() returned error: %v", err)
+	}
+
+	got, err := AppendText(nil, doc, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	want := `"cfg" { "volume" "3" "video" { "width" "1920" "height" "720" } "lang" "english" } "extra" { "k" "v" } `
+	if string(got) != want {
+		t.Fatalf("ApplyDefaults() = %q, want %q", got, want)
+	}
+
+	doc.Roots[1].Children[0].Key = "changed"
+	if template.Roots[1].Children[0].Key != "k" {
+		t.Fatalf("ApplyDefaults() must not share template nodes")
+	}
+}
@@ -0,0 +1,229 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Len returns the number of children of an object node and 0 for other nodes.
+func (n *Node) Len() int {
+	if n == nil || n.Kind != NodeObject {
+		return 0
+	}
+
+	return len(n.Children)
+}
+
+// At returns the child at index i or nil when i is out of range.
+func (n *Node) At(i int) *Node {
+	if i < 0 || i >= n.Len() {
+		return nil
+	}
+
+	return n.Children[i]
+}
+
+// Insert places child at index i, shifting later children; i may equal Len to append.
+func (n *Node) Insert(i int, child *Node) error {
+	if err := n.checkObject(); err != nil {
+		return err
+	}
+
+	if child == nil {
+		return fmt.Errorf("%w: nil child", ErrInvalidNodeState)
+	}
+
+	if i < 0 || i > len(n.Children) {
+		return fmt.Errorf("%w: %d not in [0, %d] for object %q", ErrIndexOutOfRange, i, len(n.Children), n.Key)
+	}
+
+	n.Children = slices.Insert(n.Children, i, child)
+	return nil
+}
+
+// Remove deletes and returns the child at index i.
+func (n *Node) Remove(i int) (*Node, error) {
+	if err := n.checkIndex(i); err != nil {
+		return nil, err
+	}
+
+	child := n.Children[i]
+	n.Children = slices.Delete(n.Children, i, i+1)
+	return child, nil
+}
+
+// RemoveChild deletes the child reference from the children of n.
+func (n *Node) RemoveChild(child *Node) error {
+	i, err := n.childIndex(child)
+	if err != nil {
+		return err
+	}
+
+	n.Children = slices.Delete(n.Children, i, i+1)
+	return nil
+}
+
+// RemoveKey deletes every child with key, preserving the order of the others,
+// and returns the number removed.
+func (n *Node) RemoveKey(key string) int {
+	if n == nil || n.Kind != NodeObject {
+		return 0
+	}
+
+	before := len(n.Children)
+	n.Children = slices.DeleteFunc(n.Children, func(child *Node) bool {
+		return child != nil && child.Key == key
+	})
+
+	return before - len(n.Children)
+}
+
+// ReplaceChild puts replacement at the position of the child reference old.
+func (n *Node) ReplaceChild(old, replacement *Node) error {
+	if replacement == nil {
+		return fmt.Errorf("%w: nil child", ErrInvalidNodeState)
+	}
+
+	i, err := n.childIndex(old)
+	if err != nil {
+		return err
+	}
+
+	n.Children[i] = replacement
+	return nil
+}
+
+// InsertBefore places child directly before mark among the children of n.
+func (n *Node) InsertBefore(mark, child *Node) error {
+	i, err := n.childIndex(mark)
+	if err != nil {
+		return err
+	}
+
+	return n.Insert(i, child)
+}
+
+// InsertAfter places child directly after mark among the children of n.
+func (n *Node) InsertAfter(mark, child *Node) error {
+	i, err := n.childIndex(mark)
+	if err != nil {
+		return err
+	}
+
+	return n.Insert(i+1, child)
+}
+
+// IndexOf returns the position of child among the children of n or -1.
+// Children are matched by reference, not by key.
+func (n *Node) IndexOf(child *Node) int {
+	if n == nil || n.Kind != NodeObject || child == nil {
+		return -1
+	}
+
+	return slices.Index(n.Children, child)
+}
+
+// Move relocates the child at index from so it ends up at index to,
+// shifting the children in between.
+func (n *Node) Move(from, to int) error {
+	if err := n.checkIndex(from); err != nil {
+		return err
+	}
+
+	if err := n.checkIndex(to); err != nil {
+		return err
+	}
+
+	child := n.Children[from]
+	n.Children = slices.Delete(n.Children, from, from+1)
+	n.Children = slices.Insert(n.Children, to, child)
+	return nil
+}
+
+// MoveBefore relocates child so it directly precedes mark among the children of n.
+func (n *Node) MoveBefore(child, mark *Node) error {
+	return n.moveRelative(child, mark, 0)
+}
+
+// MoveAfter relocates child so it directly follows mark among the children of n.
+func (n *Node) MoveAfter(child, mark *Node) error {
+	return n.moveRelative(child, mark, 1)
+}
+
+// Swap exchanges the children at indices i and j.
+func (n *Node) Swap(i, j int) error {
+	if err := n.checkIndex(i); err != nil {
+		return err
+	}
+
+	if err := n.checkIndex(j); err != nil {
+		return err
+	}
+
+	n.Children[i], n.Children[j] = n.Children[j], n.Children[i]
+	return nil
+}
+
+// moveRelative relocates child next to mark, offset 0 placing it before and 1 after.
+func (n *Node) moveRelative(child, mark *Node, offset int) error {
+	from, err := n.childIndex(child)
+	if err != nil {
+		return err
+	}
+
+	if _, err := n.childIndex(mark); err != nil {
+		return err
+	}
+
+	if child == mark {
+		return nil
+	}
+
+	n.Children = slices.Delete(n.Children, from, from+1)
+	n.Children = slices.Insert(n.Children, slices.Index(n.Children, mark)+offset, child)
+	return nil
+}
+
+// childIndex returns the index of a child reference or ErrChildNotFound.
+func (n *Node) childIndex(child *Node) (int, error) {
+	if err := n.checkObject(); err != nil {
+		return -1, err
+	}
+
+	i := n.IndexOf(child)
+	if i < 0 {
+		return -1, fmt.Errorf("%w: %q in object %q", ErrChildNotFound, nodeKey(child), n.Key)
+	}
+
+	return i, nil
+}
+
+// checkIndex validates that i addresses an existing child of an object node.
+func (n *Node) checkIndex(i int) error {
+	if err := n.checkObject(); err != nil {
+		return err
+	}
+
+	if i < 0 || i >= len(n.Children) {
+		return fmt.Errorf("%w: %d not in [0, %d) for object %q", ErrIndexOutOfRange, i, len(n.Children), n.Key)
+	}
+
+	return nil
+}
+
+// checkObject validates that n is a non-nil object node.
+func (n *Node) checkObject() error {
+	if n == nil {
+		return fmt.Errorf("%w: nil node", ErrInvalidNodeState)
+	}
+
+	if n.Kind != NodeObject {
+		return fmt.Errorf("%w: %q is not an object", ErrInvalidNodeState, n.Key)
+	}
+
+	return nil
+}
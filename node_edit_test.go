@@ -0,0 +1,197 @@
+package vdf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// childKeys joins child keys of an object node for compact assertions.
+func childKeys(n *Node) string {
+	keys := make([]string, 0, len(n.Children))
+	for _, child := range n.Children {
+		keys = append(keys, child.Key)
+	}
+
+	return strings.Join(keys, ",")
+}
+
+func TestNodeReorder(t *testing.T) {
+	t.Parallel()
+
+	root := mustParseString(t, `"r" { "a" "1" "b" "2" "appid" "3" "c" "4" }`).Roots[0]
+	a, appid, c := root.Children[0], root.Children[2], root.Children[3]
+
+	if err := root.MoveBefore(appid, a); err != nil {
+		t.Fatalf("MoveBefore() returned error: %v", err)
+	}
+	if got := childKeys(root); got != "appid,a,b,c" {
+		t.Fatalf("after MoveBefore keys = %q", got)
+	}
+
+	if err := root.MoveAfter(a, c); err != nil {
+		t.Fatalf("MoveAfter() returned error: %v", err)
+	}
+	if got := childKeys(root); got != "appid,b,c,a" {
+		t.Fatalf("after MoveAfter keys = %q", got)
+	}
+
+	if err := root.Swap(1, 2); err != nil {
+		t.Fatalf("Swap() returned error: %v", err)
+	}
+	if got := childKeys(root); got != "appid,c,b,a" {
+		t.Fatalf("after Swap keys = %q", got)
+	}
+
+	if err := root.Move(0, 3); err != nil {
+		t.Fatalf("Move() returned error: %v", err)
+	}
+	if got := childKeys(root); got != "c,b,a,appid" {
+		t.Fatalf("after Move keys = %q", got)
+	}
+
+	if err := root.Swap(0, 4); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Fatalf("Swap(out of range) error = %v, want ErrIndexOutOfRange", err)
+	}
+
+	if err := root.MoveBefore(NewStringNode("x", "y"), a); !errors.Is(err, ErrChildNotFound) {
+		t.Fatalf("MoveBefore(foreign) error = %v, want ErrChildNotFound", err)
+	}
+
+	if err := a.Swap(0, 0); !errors.Is(err, ErrInvalidNodeState) {
+		t.Fatalf("Swap(leaf) error = %v, want ErrInvalidNodeState", err)
+	}
+}
+
+func TestNodePositionalAccessors(t *testing.T) {
+	t.Parallel()
+
+	root := NewObjectNode("r")
+	if root.Len() != 0 || root.At(0) != nil {
+		t.Fatalf("empty object Len/At mismatch")
+	}
+
+	if err := root.Insert(0, NewStringNode("b", "2")); err != nil {
+		t.Fatalf("Insert(0) returned error: %v", err)
+	}
+	if err := root.Insert(0, NewStringNode("a", "1")); err != nil {
+		t.Fatalf("Insert(0) returned error: %v", err)
+	}
+	if err := root.Insert(2, NewStringNode("c", "3")); err != nil {
+		t.Fatalf("Insert(Len) returned error: %v", err)
+	}
+
+	if got := childKeys(root); got != "a,b,c" || root.Len() != 3 {
+		t.Fatalf("after Insert keys = %q, len = %d", got, root.Len())
+	}
+
+	if root.At(1).Key != "b" || root.At(-1) != nil || root.At(3) != nil {
+		t.Fatalf("At() bounds mismatch")
+	}
+
+	removed, err := root.Remove(1)
+	if err != nil || removed.Key != "b" {
+		t.Fatalf("Remove(1) = %+v, %v, want b", removed, err)
+	}
+
+	if got := childKeys(root); got != "a,c" {
+		t.Fatalf("after Remove keys = %q", got)
+	}
+
+	if err := root.Insert(5, NewStringNode("x", "y")); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Fatalf("Insert(out of range) error = %v, want ErrIndexOutOfRange", err)
+	}
+
+	if _, err := root.Remove(2); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Fatalf("Remove(out of range) error = %v, want ErrIndexOutOfRange", err)
+	}
+
+	if removed.Len() != 0 {
+		t.Fatalf("leaf Len() = %d, want 0", removed.Len())
+	}
+}
+
+func TestNodeChildEditing(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"r" { "a" "1" "b" "2" "a" "3" "c" "4" }`)
+	root := doc.Roots[0]
+	b, c := root.First("b"), root.First("c")
+
+	if err := root.InsertBefore(b, NewStringNode("x", "5")); err != nil {
+		t.Fatalf("InsertBefore() returned error: %v", err)
+	}
+
+	if err := root.InsertAfter(c, NewStringNode("y", "6")); err != nil {
+		t.Fatalf("InsertAfter() returned error: %v", err)
+	}
+
+	if got := childKeys(root); got != "a,x,b,a,c,y" {
+		t.Fatalf("after Insert keys = %q", got)
+	}
+
+	if err := root.ReplaceChild(b, NewStringNode("z", "7")); err != nil {
+		t.Fatalf("ReplaceChild() returned error: %v", err)
+	}
+
+	if err := root.RemoveChild(c); err != nil {
+		t.Fatalf("RemoveChild() returned error: %v", err)
+	}
+
+	if removed := root.RemoveKey("a"); removed != 2 {
+		t.Fatalf("RemoveKey(a) = %d, want 2", removed)
+	}
+
+	if got := childKeys(root); got != "x,z,y" {
+		t.Fatalf("after edits keys = %q, want x,z,y", got)
+	}
+
+	if err := root.RemoveChild(c); !errors.Is(err, ErrChildNotFound) {
+		t.Fatalf("RemoveChild(removed) error = %v, want ErrChildNotFound", err)
+	}
+
+	if err := root.ReplaceChild(root.First("x"), nil); !errors.Is(err, ErrInvalidNodeState) {
+		t.Fatalf("ReplaceChild(nil) error = %v, want ErrInvalidNodeState", err)
+	}
+}
+
+func TestDocumentSort(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"b" { "z" "1" "a" { "y" "2" "x" "3" } "a" "4" } "a" "5"`)
+	doc.Sort(nil, false)
+	if doc.Roots[0].Key != "a" || childKeys(doc.Roots[1]) != "z,a,a" {
+		t.Fatalf("Sort(flat) roots = %s, %s", doc.Roots[0].Key, childKeys(doc.Roots[1]))
+	}
+
+	doc.Sort(nil, true)
+	b := doc.Roots[1]
+	if childKeys(b) != "a,a,z" || childKeys(b.Children[0]) != "x,y" || b.Children[1].Kind != NodeString {
+		t.Fatalf("Sort(recursive) b = %s, nested %s", childKeys(b), childKeys(b.Children[0]))
+	}
+
+	byKeyDesc := func(x, y *Node) int { return strings.Compare(y.Key, x.Key) }
+	b.SortChildren(byKeyDesc, false)
+	if childKeys(b) != "z,a,a" || childKeys(b.Children[1]) != "x,y" {
+		t.Fatalf("SortChildren(desc) = %s, nested %s", childKeys(b), childKeys(b.Children[1]))
+	}
+
+	out, err := AppendText(nil, doc, EncodeOptions{Compact: true})
+	if err != nil || !strings.HasPrefix(string(out), `"a" "5" "b" { "z" "1" "a" {`) {
+		t.Fatalf("encoded sorted document = %q, %v", out, err)
+	}
+}
+
+func TestDocumentSortCyclic(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"root" { "b" { "y" "1" "x" "2" } "a" "3" }`)
+	root := doc.Roots[0]
+	inner := root.First("b")
+	inner.Children = append(inner.Children, root)
+
+	doc.Sort(nil, true)
+	if childKeys(root) != "a,b" || childKeys(inner) != "root,x,y" {
+		t.Fatalf("Sort(cyclic) = %s, nested %s", childKeys(root), childKeys(inner))
+	}
+}
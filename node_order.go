@@ -4,10 +4,116 @@
 
 package vdf
 
-import "slices"
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
 
-// orderedNodes returns nodes in source order or deterministic key order.
-func orderedNodes(in []*Node, deterministic bool) []*Node {
+// encodePathScanLimit is the open-object count above which cycle checks switch to a set.
+const encodePathScanLimit = 32
+
+// encodeFrame stores encode traversal progress for one open object node.
+type encodeFrame struct {
+	node     *Node   // Object node being encoded.
+	children []*Node // Children in effective encode order.
+	index    int     // Index of the next child to encode.
+}
+
+// encodeStack tracks open objects during iterative encode and rejects cyclic paths.
+type encodeStack struct {
+	onPath       map[*Node]struct{} // Lazily built set of open objects for deep paths.
+	frames       []encodeFrame      // Open object frames from outermost to innermost.
+	sortDepth    int                // Deepest level sorted by key (0 means all levels).
+	collation    Collation          // Key comparison used for sorted levels.
+	sortChildren bool               // Whether children are emitted in key order.
+}
+
+// newEncodeStack creates an encode stack for a subtree whose root is at level 1.
+func newEncodeStack(deterministic bool, deterministicDepth int, collation Collation) *encodeStack {
+	return &encodeStack{
+		frames:       make([]encodeFrame, 0, 8),
+		sortChildren: deterministic,
+		sortDepth:    deterministicDepth,
+		collation:    collation,
+	}
+}
+
+// push opens an object node or fails when it is already open on the current path.
+func (s *encodeStack) push(node *Node) error {
+	if s.contains(node) {
+		return fmt.Errorf("%w: cyclic node %q", ErrInvalidNodeState, node.Key)
+	}
+
+	s.frames = append(s.frames, encodeFrame{
+		node:     node,
+		children: orderedNodes(node.Children, s.sortsLevel(len(s.frames)+2), s.collation),
+	})
+
+	if s.onPath != nil {
+		s.onPath[node] = struct{}{}
+		return nil
+	}
+
+	// Shallow paths are scanned linearly; deep paths switch to a set to keep checks O(1).
+	if len(s.frames) > encodePathScanLimit {
+		s.onPath = make(map[*Node]struct{}, len(s.frames)*2)
+		for _, frame := range s.frames {
+			s.onPath[frame.node] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// sortsLevel reports whether node lists at the given level use key order.
+func (s *encodeStack) sortsLevel(level int) bool {
+	return sortsLevel(s.sortChildren, s.sortDepth, level)
+}
+
+// pop closes the innermost open object.
+func (s *encodeStack) pop() {
+	last := len(s.frames) - 1
+	if s.onPath != nil {
+		delete(s.onPath, s.frames[last].node)
+	}
+
+	s.frames = s.frames[:last]
+}
+
+// top returns the innermost open object frame.
+func (s *encodeStack) top() *encodeFrame {
+	return &s.frames[len(s.frames)-1]
+}
+
+// depth returns the number of open objects.
+func (s *encodeStack) depth() int {
+	return len(s.frames)
+}
+
+// contains reports whether node is currently open on the traversal path.
+func (s *encodeStack) contains(node *Node) bool {
+	if s.onPath != nil {
+		_, ok := s.onPath[node]
+		return ok
+	}
+
+	for i := range s.frames {
+		if s.frames[i].node == node {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortsLevel reports whether deterministic ordering applies at a 1-based node level.
+func sortsLevel(deterministic bool, maxDepth, level int) bool {
+	return deterministic && (maxDepth <= 0 || level <= maxDepth)
+}
+
+// orderedNodes returns nodes in source order or deterministic key order under collation.
+func orderedNodes(in []*Node, deterministic bool, collation Collation) []*Node {
 	if !deterministic {
 		return in
 	}
@@ -26,15 +132,93 @@ func orderedNodes(in []*Node, deterministic bool) []*Node {
 		if b == nil {
 			return -1
 		}
-		if a.Key < b.Key {
-			return -1
-		}
-		if a.Key > b.Key {
-			return 1
-		}
 
-		return 0
+		return collation.Compare(a.Key, b.Key)
 	})
 
 	return out
 }
+
+// Sort reorders document roots with cmp, and with recursive all object
+// children below them, so sorted order persists through later edits and
+// encoding. A nil cmp orders by raw key bytes. The sort is stable, keeping
+// duplicate keys in their relative order; nil nodes sort last.
+func (d *Document) Sort(cmp func(a, b *Node) int, recursive bool) {
+	if d == nil {
+		return
+	}
+
+	sortNodes(d.Roots, cmp)
+	if recursive {
+		sortDescendants(d.Roots, cmp)
+	}
+}
+
+// SortChildren reorders the children of an object node with cmp, and with
+// recursive all object children below them, following Document.Sort.
+func (n *Node) SortChildren(cmp func(a, b *Node) int, recursive bool) {
+	if n == nil || n.Kind != NodeObject {
+		return
+	}
+
+	sortNodes(n.Children, cmp)
+	if recursive {
+		sortDescendants(n.Children, cmp)
+	}
+}
+
+// sortDescendants sorts the children of every object below nodes using an explicit stack.
+// Cyclic paths are not descended again; every object on them is already sorted once.
+func sortDescendants(nodes []*Node, cmp func(a, b *Node) int) {
+	for _, node := range nodes {
+		if node == nil || node.Kind != NodeObject {
+			continue
+		}
+
+		stack := newEncodeStack(false, 0, 0)
+		if err := stack.push(node); err != nil {
+			continue
+		}
+
+		sortNodes(node.Children, cmp)
+		for stack.depth() > 0 {
+			top := stack.top()
+			if top.index < len(top.children) {
+				child := top.children[top.index]
+				top.index++
+
+				if child == nil || child.Kind != NodeObject {
+					continue
+				}
+
+				if err := stack.push(child); err != nil {
+					continue
+				}
+
+				sortNodes(child.Children, cmp)
+
+				continue
+			}
+
+			stack.pop()
+		}
+	}
+}
+
+// sortNodes stably sorts one sibling list in place, placing nil nodes last.
+func sortNodes(nodes []*Node, cmp func(a, b *Node) int) {
+	slices.SortStableFunc(nodes, func(a, b *Node) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return 1
+		case b == nil:
+			return -1
+		case cmp == nil:
+			return strings.Compare(a.Key, b.Key)
+		default:
+			return cmp(a, b)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import "fmt"
+
+// Get returns the value of the first pair with key.
+func (m OrderedMap) Get(key string) (any, bool) {
+	for _, pair := range m {
+		if pair.Key == key {
+			return pair.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// GetAll returns the values of all pairs with key in order.
+func (m OrderedMap) GetAll(key string) []any {
+	var out []any
+	for _, pair := range m {
+		if pair.Key == key {
+			out = append(out, pair.Value)
+		}
+	}
+
+	return out
+}
+
+// Keys returns pair keys in order, including repeated keys.
+func (m OrderedMap) Keys() []string {
+	out := make([]string, 0, len(m))
+	for _, pair := range m {
+		out = append(out, pair.Key)
+	}
+
+	return out
+}
+
+// ToOrderedMap converts document roots to an OrderedMap, keeping key order
+// and repeated keys. Objects become nested OrderedMap values and leaves their
+// Node.Value. Nil nodes and conditionals are dropped, and pointer, wide string
+// and directive nodes convert like uint32 and string nodes.
+func (d *Document) ToOrderedMap() OrderedMap {
+	return nodesToOrderedMap(documentRoots(d))
+}
+
+// nodesToOrderedMap converts sibling nodes to ordered pairs.
+func nodesToOrderedMap(nodes []*Node) OrderedMap {
+	out := make(OrderedMap, 0, len(nodes))
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+
+		var value any
+		if node.Kind == NodeObject {
+			value = nodesToOrderedMap(node.Children)
+		} else {
+			value = node.Value()
+		}
+
+		out = append(out, KeyValue{Key: node.Key, Value: value})
+	}
+
+	return out
+}
+
+// FromOrderedMap builds a document with one root per pair, in order.
+// Values may be OrderedMap for objects, int64 for int64 nodes, or any value
+// accepted by FromMap. Raw binary payloads are not supported, as their type
+// byte is not kept.
+func FromOrderedMap(m OrderedMap) (*Document, error) {
+	roots, err := orderedMapToNodes(m)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := NewDocumentWithFormat(FormatAuto)
+	for _, root := range roots {
+		doc.AddRoot(root)
+	}
+
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// orderedMapToNodes converts ordered pairs to sibling nodes.
+func orderedMapToNodes(m OrderedMap) ([]*Node, error) {
+	nodes := make([]*Node, 0, len(m))
+	for _, pair := range m {
+		var node *Node
+		switch value := pair.Value.(type) {
+		case OrderedMap:
+			children, err := orderedMapToNodes(value)
+			if err != nil {
+				return nil, err
+			}
+
+			node = NewObjectNode(pair.Key)
+			node.Children = children
+		case int64:
+			node = NewInt64Node(pair.Key, value)
+		case []byte:
+			return nil, fmt.Errorf("%w: key %q raw payload", ErrUnsupportedMapValueType, pair.Key)
+		default:
+			var err error
+			if node, err = mapValueToNode(pair.Key, value); err != nil {
+				return nil, err
+			}
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
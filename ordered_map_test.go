@@ -0,0 +1,51 @@
+package vdf
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestOrderedMapRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"root" { "z" "1" "tag" "a" "sub" { "x" "y" } "tag" "b" }`)
+	root := doc.Roots[0]
+	root.Add(NewInt64Node("neg", -5))
+	root.Add(NewUint64Node("big", 1<<40))
+	root.Add(NewFloat32Node("f", 1.5))
+	root.Add(NewColorNode("c", Color{R: 1}))
+
+	m := doc.ToOrderedMap()
+	value, ok := m.Get("root")
+	if !ok {
+		t.Fatal("Get(root) missing")
+	}
+
+	inner := value.(OrderedMap)
+	if got := inner.Keys(); !slices.Equal(got, []string{"z", "tag", "sub", "tag", "neg", "big", "f", "c"}) {
+		t.Fatalf("Keys = %v", got)
+	}
+
+	if got := inner.GetAll("tag"); !slices.Equal(got, []any{"a", "b"}) {
+		t.Fatalf("GetAll(tag) = %v", got)
+	}
+
+	if _, ok := inner.Get("missing"); ok {
+		t.Fatal("Get(missing) found a value")
+	}
+
+	back, err := FromOrderedMap(m)
+	if err != nil {
+		t.Fatalf("FromOrderedMap: %v", err)
+	}
+
+	if !back.Equal(doc) {
+		t.Fatalf("round trip mismatch: %+v", back.ToOrderedMap())
+	}
+
+	_, err = FromOrderedMap(OrderedMap{{Key: "r", Value: []byte{1}}})
+	if !errors.Is(err, ErrUnsupportedMapValueType) {
+		t.Fatalf("FromOrderedMap(raw) error = %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+const (
+	// PackageInfoMagic27 identifies packageinfo.vdf version 27.
+	PackageInfoMagic27 uint32 = 0x06565527
+	// PackageInfoMagic28 identifies packageinfo.vdf version 28, which adds the PICS token.
+	PackageInfoMagic28 uint32 = 0x06565528
+)
+
+// packageInfoEnd is the package id that terminates the record list.
+const packageInfoEnd uint32 = 0xFFFFFFFF
+
+// PackageInfoHeader is the packageinfo.vdf container header.
+type PackageInfoHeader struct {
+	// Magic identifies the container version, see PackageInfoMagic27 and later.
+	Magic uint32 `json:"magic" yaml:"magic"`
+	// Universe is the Steam universe the cache belongs to.
+	Universe uint32 `json:"universe" yaml:"universe"`
+}
+
+// PackageInfoRecord is one package entry of packageinfo.vdf.
+type PackageInfoRecord struct {
+	// Doc is the decoded binary KeyValues payload.
+	Doc *Document `json:"doc" yaml:"doc"`
+	// PICSToken is the access token used to request the package info; version 28 only.
+	PICSToken uint64 `json:"pics_token" yaml:"pics_token"`
+	// PackageID is the Steam package (subscription) id.
+	PackageID uint32 `json:"package_id" yaml:"package_id"`
+	// ChangeNumber is the PICS change number of the entry.
+	ChangeNumber uint32 `json:"change_number" yaml:"change_number"`
+	// Hash is the SHA-1 stored for the entry.
+	Hash [20]byte `json:"hash" yaml:"hash"`
+}
+
+// PackageInfoReader reads package records from a packageinfo.vdf stream one at a time.
+type PackageInfoReader struct {
+	r      *bufio.Reader     // Buffered source shared by all records, positioned at the next one.
+	err    error             // First error met by Packages.
+	opts   DecodeOptions     // Decode options for record payloads.
+	header PackageInfoHeader // Container header.
+	done   bool              // Whether the end marker was read.
+}
+
+// NewPackageInfoReader reads the packageinfo.vdf header from r and returns a record reader.
+// Record payloads are decoded as binary VDF with opts; opts.Format is ignored.
+// It fails with ErrUnsupportedAppInfoVersion for unknown container versions.
+func NewPackageInfoReader(r io.Reader, opts DecodeOptions) (*PackageInfoReader, error) {
+	// Records are decoded from one buffered reader, so no record decode
+	// wraps the source again and reads ahead into the records after it.
+	br := ensureBufferedReader(r)
+
+	var raw [8]byte
+	if _, err := io.ReadFull(br, raw[:]); err != nil {
+		return nil, fmt.Errorf("%w: header: %w", ErrInvalidAppInfo, err)
+	}
+
+	header := PackageInfoHeader{
+		Magic:    binary.LittleEndian.Uint32(raw[0:4]),
+		Universe: binary.LittleEndian.Uint32(raw[4:8]),
+	}
+
+	if header.Magic != PackageInfoMagic27 && header.Magic != PackageInfoMagic28 {
+		return nil, fmt.Errorf("%w: magic 0x%08x", ErrUnsupportedAppInfoVersion, header.Magic)
+	}
+
+	opts.Format = FormatBinary
+	return &PackageInfoReader{r: br, opts: normalizeDecodeOptions(opts), header: header}, nil
+}
+
+// Header returns the container header.
+func (p *PackageInfoReader) Header() PackageInfoHeader {
+	return p.header
+}
+
+// Next reads and decodes the next record and returns io.EOF after the last one.
+func (p *PackageInfoReader) Next() (*PackageInfoRecord, error) {
+	if p.done {
+		return nil, io.EOF
+	}
+
+	var raw [4]byte
+	if _, err := io.ReadFull(p.r, raw[:]); err != nil {
+		return nil, fmt.Errorf("%w: record: %w", ErrInvalidAppInfo, err)
+	}
+
+	record := &PackageInfoRecord{PackageID: binary.LittleEndian.Uint32(raw[:])}
+	if record.PackageID == packageInfoEnd {
+		p.done = true
+		return nil, io.EOF
+	}
+
+	fixed := make([]byte, 20+4, 20+4+8)
+	if p.header.Magic == PackageInfoMagic28 {
+		fixed = fixed[:cap(fixed)]
+	}
+
+	if _, err := io.ReadFull(p.r, fixed); err != nil {
+		return nil, fmt.Errorf("%w: package %d: %w", ErrInvalidAppInfo, record.PackageID, err)
+	}
+
+	copy(record.Hash[:], fixed[:20])
+	record.ChangeNumber = binary.LittleEndian.Uint32(fixed[20:24])
+	if len(fixed) > 24 {
+		record.PICSToken = binary.LittleEndian.Uint64(fixed[24:32])
+	}
+
+	// Records carry no size field; the payload ends at its document end marker.
+	doc, err := parseBinaryDocument(p.r, p.opts)
+	if err != nil {
+		return nil, fmt.Errorf("package %d: %w", record.PackageID, err)
+	}
+
+	record.Doc = doc
+	return record, nil
+}
+
+// Packages returns an iterator over package ids and decoded payloads.
+// Iteration stops at the first error, which is then reported by Err.
+func (p *PackageInfoReader) Packages() iter.Seq2[uint32, *Document] {
+	return func(yield func(uint32, *Document) bool) {
+		for {
+			record, err := p.Next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if err != nil {
+				p.err = err
+				return
+			}
+
+			if !yield(record.PackageID, record.Doc) {
+				return
+			}
+		}
+	}
+}
+
+// Err returns the first error met while iterating Packages.
+func (p *PackageInfoReader) Err() error {
+	return p.err
+}
+
+// PackageInfoWriter writes a packageinfo.vdf container record by record.
+type PackageInfoWriter struct {
+	w      io.Writer         // Destination stream.
+	header PackageInfoHeader // Container header.
+	closed bool              // Whether the end marker was written.
+}
+
+// NewPackageInfoWriter writes the packageinfo.vdf header to w and returns a record writer.
+// Close must be called to write the end marker.
+func NewPackageInfoWriter(w io.Writer, header PackageInfoHeader) (*PackageInfoWriter, error) {
+	if header.Magic != PackageInfoMagic27 && header.Magic != PackageInfoMagic28 {
+		return nil, fmt.Errorf("%w: magic 0x%08x", ErrUnsupportedAppInfoVersion, header.Magic)
+	}
+
+	var raw [8]byte
+	binary.LittleEndian.PutUint32(raw[0:4], header.Magic)
+	binary.LittleEndian.PutUint32(raw[4:8], header.Universe)
+	if _, err := w.Write(raw[:]); err != nil {
+		return nil, err
+	}
+
+	return &PackageInfoWriter{w: w, header: header}, nil
+}
+
+// Write encodes one record. Hash is written as stored and is not recomputed.
+func (p *PackageInfoWriter) Write(record *PackageInfoRecord) error {
+	if p.closed {
+		return fmt.Errorf("%w: packageinfo writer is closed", ErrInvalidNodeState)
+	}
+
+	if record == nil || record.Doc == nil {
+		return fmt.Errorf("%w: nil package record", ErrInvalidNodeState)
+	}
+
+	if record.PackageID == packageInfoEnd {
+		return fmt.Errorf("%w: package id 0x%08x is reserved", ErrInvalidNodeState, record.PackageID)
+	}
+
+	fixed := make([]byte, 0, 4+20+4+8)
+	fixed = binary.LittleEndian.AppendUint32(fixed, record.PackageID)
+	fixed = append(fixed, record.Hash[:]...)
+	fixed = binary.LittleEndian.AppendUint32(fixed, record.ChangeNumber)
+	if p.header.Magic == PackageInfoMagic28 {
+		fixed = binary.LittleEndian.AppendUint64(fixed, record.PICSToken)
+	}
+
+	if _, err := p.w.Write(fixed); err != nil {
+		return err
+	}
+
+	return NewEncoder(p.w, EncodeOptions{Format: FormatBinary}).EncodeDocument(record.Doc)
+}
+
+// Close writes the end marker. It does not close the underlying writer.
+func (p *PackageInfoWriter) Close() error {
+	if p.closed {
+		return nil
+	}
+
+	p.closed = true
+	var raw [4]byte
+	binary.LittleEndian.PutUint32(raw[:], packageInfoEnd)
+	_, err := p.w.Write(raw[:])
+	return err
+}
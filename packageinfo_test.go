@@ -0,0 +1,127 @@
+package vdf
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestPackageInfoRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	pkg := func(id uint32, app uint32) *PackageInfoRecord {
+		doc := NewDocumentWithFormat(FormatBinary)
+		root := NewObjectNode("")
+		root.Add(NewUint32Node("packageid", id))
+		apps := NewObjectNode("appids")
+		apps.Add(NewUint32Node("0", app))
+		root.Add(apps)
+		doc.AddRoot(root)
+
+		return &PackageInfoRecord{Doc: doc, PackageID: id, ChangeNumber: id * 10, PICSToken: 7, Hash: [20]byte{byte(id)}}
+	}
+
+	var buf bytes.Buffer
+	writer, err := NewPackageInfoWriter(&buf, PackageInfoHeader{Magic: PackageInfoMagic28, Universe: 1})
+	if err != nil {
+		t.Fatalf("NewPackageInfoWriter() returned error: %v", err)
+	}
+
+	for _, record := range []*PackageInfoRecord{pkg(0, 7), pkg(469, 440)} {
+		if err := writer.Write(record); err != nil {
+			t.Fatalf("Write() returned error: %v", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	data := buf.Bytes()
+
+	// A source with ReadByte but no UnreadByte must not lose later records.
+	reader, err := NewPackageInfoReader(byteReader{bytes.NewReader(data)}, DecodeOptions{})
+	if err != nil {
+		t.Fatalf("NewPackageInfoReader(byteReader) returned error: %v", err)
+	}
+
+	count := 0
+	for range reader.Packages() {
+		count++
+	}
+
+	if count != 2 || reader.Err() != nil {
+		t.Fatalf("byteReader input: %d packages, Err() = %v", count, reader.Err())
+	}
+
+	reader, err = NewPackageInfoReader(bytes.NewReader(data), DecodeOptions{})
+	if err != nil {
+		t.Fatalf("NewPackageInfoReader() returned error: %v", err)
+	}
+
+	if header := reader.Header(); header.Magic != PackageInfoMagic28 || header.Universe != 1 {
+		t.Fatalf("Header() = %+v", header)
+	}
+
+	var out bytes.Buffer
+	rewriter, err := NewPackageInfoWriter(&out, reader.Header())
+	if err != nil {
+		t.Fatalf("NewPackageInfoWriter() returned error: %v", err)
+	}
+
+	var ids []uint32
+	for {
+		record, err := reader.Next()
+		if err != nil {
+			break
+		}
+
+		ids = append(ids, record.PackageID)
+		if record.ChangeNumber != record.PackageID*10 || record.PICSToken != 7 || record.Hash[0] != byte(record.PackageID) {
+			t.Fatalf("Next() = %+v", record)
+		}
+
+		if err := rewriter.Write(record); err != nil {
+			t.Fatalf("Write() returned error: %v", err)
+		}
+	}
+
+	if err := rewriter.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	if len(ids) != 2 || ids[0] != 0 || ids[1] != 469 {
+		t.Fatalf("package ids = %v", ids)
+	}
+
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Fatalf("rewrite mismatch:\n got %x\nwant %x", out.Bytes(), data)
+	}
+
+	reader, err = NewPackageInfoReader(bytes.NewReader(data[:len(data)-6]), DecodeOptions{})
+	if err != nil {
+		t.Fatalf("NewPackageInfoReader() returned error: %v", err)
+	}
+
+	count = 0
+	for range reader.Packages() {
+		count++
+	}
+
+	if count != 1 || reader.Err() == nil {
+		t.Fatalf("truncated input: %d packages, Err() = %v", count, reader.Err())
+	}
+
+	if _, err := NewPackageInfoReader(bytes.NewReader([]byte{1, 2, 3, 4, 0, 0, 0, 0}), DecodeOptions{}); !errors.Is(err, ErrUnsupportedAppInfoVersion) {
+		t.Fatalf("NewPackageInfoReader(bad magic) error = %v, want ErrUnsupportedAppInfoVersion", err)
+	}
+}
+
+// byteReader exposes only Read and ReadByte of a bytes.Reader.
+type byteReader struct {
+	r *bytes.Reader
+}
+
+func (b byteReader) Read(p []byte) (int, error) { return b.r.Read(p) }
+
+func (b byteReader) ReadByte() (byte, error) { return b.r.ReadByte() }
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 )
 
@@ -21,17 +22,49 @@ type Decoder struct {
 	buffered  *bufio.Reader  // Lazy buffered reader for auto-detect and generic streams.
 	decoded   *Document      // Decoded document.
 	events    *eventIterator // Event iterator.
+	stream    *streamReader  // Incremental event reader for Token.
 	opts      DecodeOptions  // Decode options.
 }
 
 // NewDecoder creates a decoder with normalized options.
 func NewDecoder(r io.Reader, opts DecodeOptions) *Decoder {
+	if opts.MaxInputBytes > 0 {
+		r = &inputLimitReader{r: r, limit: opts.MaxInputBytes, remaining: opts.MaxInputBytes}
+	}
+
 	return &Decoder{
 		reader: r,
 		opts:   normalizeDecodeOptions(opts),
 	}
 }
 
+// inputLimitReader fails with ErrInputLimitExceeded once more than the allowed bytes are read.
+type inputLimitReader struct {
+	r         io.Reader // Decoder input.
+	limit     int64     // Configured limit for error messages.
+	remaining int64     // Bytes still allowed.
+}
+
+// Read implements io.Reader.
+func (l *inputLimitReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, fmt.Errorf("%w: more than %d bytes", ErrInputLimitExceeded, l.limit)
+	}
+
+	// One byte past the limit is enough to tell input that ends exactly at it.
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), fmt.Errorf("%w: more than %d bytes", ErrInputLimitExceeded, l.limit)
+	}
+
+	return n, err
+}
+
 // DecodeDocument decodes the full input stream into a document.
 func (d *Decoder) DecodeDocument() (*Document, error) {
 	if d.decoded != nil || d.decodeErr != nil {
@@ -43,29 +76,27 @@ func (d *Decoder) DecodeDocument() (*Document, error) {
 		return nil, err
 	}
 
-	format := d.opts.Format
-	source := d.reader
-
-	if format == FormatAuto {
-		br := d.bufferedReader()
-		detected, err := detectStreamFormat(br)
-		if err != nil {
-			d.decodeErr = err
-			return nil, err
-		}
-
-		format = detected
-		source = br
+	format, source, err := d.resolveSource()
+	if err != nil {
+		d.decodeErr = err
+		return nil, err
 	}
 
 	var (
-		doc *Document
-		err error
+		doc       *Document
+		recovered ErrorList
 	)
 
 	switch format {
 	case FormatText:
 		doc, err = parseTextDocument(source, d.opts)
+		if errors.As(err, &recovered) {
+			err = nil
+		}
+
+		if err == nil && d.opts.IncludeFS != nil {
+			err = resolveIncludes(doc, d.opts)
+		}
 	case FormatBinary:
 		doc, err = parseBinaryDocument(source, d.opts)
 	default:
@@ -79,6 +110,12 @@ func (d *Decoder) DecodeDocument() (*Document, error) {
 
 	doc.Format = format
 	d.decoded = doc
+	if len(recovered) > 0 {
+		// Recover mode keeps the best-effort document along with its errors.
+		d.decodeErr = recovered
+		return doc, recovered
+	}
+
 	return doc, nil
 }
 
@@ -161,15 +198,70 @@ func detectStreamFormat(r *bufio.Reader) (Format, error) {
 	return FormatText, nil
 }
 
+// SafeDecodeOptions returns conservative limits for untrusted input:
+// nesting depth 64, 1,000,000 nodes, 1 KiB keys, 1 MiB strings, 64 MiB of input,
+// include depth 8, and rejection of invalid UTF-8 in text.
+// The input format is auto-detected; adjust fields on the result as needed.
+func SafeDecodeOptions() DecodeOptions {
+	return DecodeOptions{
+		Format:          FormatAuto,
+		InvalidUTF8:     InvalidUTF8Reject,
+		MaxDepth:        64,
+		MaxNodes:        1_000_000,
+		MaxIncludeDepth: 8,
+		MaxKeyBytes:     1 << 10,
+		MaxStringBytes:  1 << 20,
+		MaxInputBytes:   64 << 20,
+	}
+}
+
 // normalizeDecodeOptions fills default values for decode options.
 func normalizeDecodeOptions(opts DecodeOptions) DecodeOptions {
 	if opts.Format == 0 {
 		opts.Format = FormatAuto
 	}
+	if opts.MaxDepth == 0 {
+		opts.MaxDepth = DefaultMaxDepth
+	}
+	if opts.MaxIncludeDepth == 0 {
+		opts.MaxIncludeDepth = DefaultMaxIncludeDepth
+	}
+	if opts.Strict && opts.DuplicatePolicy == DuplicateKeepAll {
+		opts.DuplicatePolicy = DuplicateError
+	}
 
 	return opts
 }
 
+// addDecodedNode appends a decoded node to siblings following DuplicatePolicy.
+// It reports false when the node duplicates a sibling under DuplicateError.
+func addDecodedNode(siblings []*Node, node *Node, policy DuplicatePolicy) ([]*Node, bool) {
+	if policy == DuplicateKeepAll {
+		return append(siblings, node), true
+	}
+
+	i := slices.IndexFunc(siblings, func(sibling *Node) bool {
+		return sibling != nil && sibling.Key == node.Key
+	})
+	if i < 0 {
+		return append(siblings, node), true
+	}
+
+	switch policy {
+	case DuplicateError:
+		return siblings, false
+	case DuplicateLastWins:
+		siblings[i] = node
+	}
+
+	return siblings, true
+}
+
+// skipDuplicate reports whether an entry with key is dropped before being built.
+func skipDuplicate(siblings []*Node, key string, policy DuplicatePolicy) bool {
+	return policy == DuplicateFirstWins && containsKey(siblings, key)
+}
+
 // validateDecodeFormat checks whether decode format value is supported.
 func validateDecodeFormat(format Format) error {
 	if format < FormatAuto || format > FormatBinary {
@@ -179,6 +271,32 @@ func validateDecodeFormat(format Format) error {
 	return nil
 }
 
+// resolveSource returns the effective input format and the reader to decode from.
+// Auto-detection peeks through a shared buffered reader, which then becomes the source.
+func (d *Decoder) resolveSource() (Format, io.Reader, error) {
+	format, source := d.opts.Format, d.reader
+	if format == FormatAuto {
+		br := d.bufferedReader()
+		detected, err := detectStreamFormat(br)
+		if err != nil {
+			return FormatAuto, nil, err
+		}
+
+		format, source = detected, br
+	}
+
+	if format != FormatText {
+		return format, source, nil
+	}
+
+	source, err := decodeTextInput(source)
+	if err != nil {
+		return FormatAuto, nil, err
+	}
+
+	return format, source, nil
+}
+
 // bufferedReader returns one shared buffered reader instance for the decoder.
 func (d *Decoder) bufferedReader() *bufio.Reader {
 	if d.buffered != nil {
@@ -188,3 +306,16 @@ func (d *Decoder) bufferedReader() *bufio.Reader {
 	d.buffered = ensureBufferedReader(d.reader)
 	return d.buffered
 }
+
+// mapDecodedKey applies the FoldKeys and KeyMap options to a key parsed under path.
+func mapDecodedKey(opts DecodeOptions, path []string, key string) string {
+	if opts.FoldKeys {
+		key = strings.ToLower(key)
+	}
+
+	if opts.KeyMap != nil {
+		key = opts.KeyMap(path, key)
+	}
+
+	return key
+}
@@ -71,7 +71,7 @@ func (it *eventIterator) next() (Event, bool) {
 		case NodeObject:
 			if !frame.started {
 				frame.started = true
-				return Event{Type: EventObjectStart, Key: frame.node.Key, Depth: depth}, true
+				return Event{Type: EventObjectStart, Key: frame.node.Key, Condition: frame.node.Condition, Depth: depth}, true
 			}
 
 			for frame.childIndex < len(frame.node.Children) {
@@ -92,17 +92,13 @@ func (it *eventIterator) next() (Event, bool) {
 			it.stack = it.stack[:topIndex]
 			return Event{Type: EventObjectEnd, Key: frame.node.Key, Depth: depth}, true
 
-		case NodeString:
-			it.stack = it.stack[:topIndex]
-			return Event{Type: EventString, Key: frame.node.Key, Depth: depth, StringValue: frame.node.StringValue}, true
-
-		case NodeUint32:
-			it.stack = it.stack[:topIndex]
-			return Event{Type: EventUint32, Key: frame.node.Key, Depth: depth, Uint32Value: frame.node.Uint32Value}, true
-
 		default:
-			// Unknown node kind is skipped here; document-level validation guards this path.
+			// Leaves emit one event; unknown kinds are skipped here,
+			// document-level validation guards this path.
 			it.stack = it.stack[:topIndex]
+			if event, ok := leafEvent(frame.node, depth); ok {
+				return event, true
+			}
 		}
 	}
 }
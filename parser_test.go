@@ -1,6 +1,7 @@
 package vdf
 
 import (
+	"bytes"
 	"errors"
 	"io"
 	"path/filepath"
@@ -136,6 +137,69 @@ func TestDecodeOptionsLimits(t *testing.T) {
 	}
 }
 
+func TestDecodeOptionsStringLimits(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"root" { "longer_key" "value" "k" "a longer value" }`)
+	bin, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	text, err := AppendText(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	for _, data := range [][]byte{text, bin} {
+		if _, err := ParseBytes(data, DecodeOptions{MaxKeyBytes: 10, MaxStringBytes: 14}); err != nil {
+			t.Fatalf("ParseBytes(limits at sizes) returned error: %v", err)
+		}
+
+		if _, err := ParseBytes(data, DecodeOptions{MaxKeyBytes: 9}); !errors.Is(err, ErrStringLimitExceeded) {
+			t.Fatalf("ParseBytes(MaxKeyBytes) error = %v, want ErrStringLimitExceeded", err)
+		}
+
+		if _, err := ParseBytes(data, DecodeOptions{MaxStringBytes: 13}); !errors.Is(err, ErrStringLimitExceeded) {
+			t.Fatalf("ParseBytes(MaxStringBytes) error = %v, want ErrStringLimitExceeded", err)
+		}
+	}
+
+	// An unterminated text token stops at the limit instead of reading to EOF.
+	huge := `"root" "` + strings.Repeat("x", 1<<16)
+	_, err = ParseBytes([]byte(huge), DecodeOptions{Format: FormatText, MaxKeyBytes: 64, MaxStringBytes: 64})
+	if !errors.Is(err, ErrStringLimitExceeded) {
+		t.Fatalf("ParseBytes(unterminated) error = %v, want ErrStringLimitExceeded", err)
+	}
+
+	// A single limit also stops early, and only caps its own token role.
+	limits := []struct {
+		input string
+		opts  DecodeOptions
+	}{
+		{input: `"root" "` + strings.Repeat("x", 1<<20) + `"`, opts: DecodeOptions{MaxStringBytes: 1024}},
+		{input: `"` + strings.Repeat("x", 1<<20) + `" "v"`, opts: DecodeOptions{MaxKeyBytes: 1024}},
+	}
+
+	for _, tc := range limits {
+		counter := &countingReader{r: strings.NewReader(tc.input)}
+		tc.opts.Format = FormatText
+		_, err := NewDecoder(counter, tc.opts).DecodeDocument()
+		if !errors.Is(err, ErrStringLimitExceeded) {
+			t.Fatalf("DecodeDocument(%+v) error = %v, want ErrStringLimitExceeded", tc.opts, err)
+		}
+
+		if counter.n > 1<<16 {
+			t.Fatalf("DecodeDocument(%+v) consumed %d bytes before failing", tc.opts, counter.n)
+		}
+	}
+
+	longKey := `"` + strings.Repeat("k", 100) + `" "v"`
+	if _, err := ParseBytes([]byte(longKey), DecodeOptions{Format: FormatText, MaxStringBytes: 8}); err != nil {
+		t.Fatalf("ParseBytes(long key, MaxStringBytes) returned error: %v", err)
+	}
+}
+
 func TestDecoderNextEvent(t *testing.T) {
 	t.Parallel()
 
@@ -166,3 +230,752 @@ func TestDecoderNextEvent(t *testing.T) {
 		}
 	}
 }
+
+func TestParseDeeplyNestedText(t *testing.T) {
+	t.Parallel()
+
+	const depth = 100000
+
+	var sb strings.Builder
+	for i := 0; i < depth; i++ {
+		sb.WriteString(`"k" { `)
+	}
+	sb.WriteString(`"leaf" "v" `)
+	for i := 0; i < depth; i++ {
+		sb.WriteString(`} `)
+	}
+
+	doc, err := ParseBytes([]byte(sb.String()), DecodeOptions{Format: FormatText, MaxDepth: Unlimited})
+	if err != nil {
+		t.Fatalf("ParseBytes(deep, Unlimited) returned error: %v", err)
+	}
+
+	levels := 0
+	for node := doc.Roots[0]; node.Kind == NodeObject; node = node.Children[0] {
+		levels++
+	}
+
+	if levels != depth {
+		t.Fatalf("nesting levels = %d, want %d", levels, depth)
+	}
+
+	_, err = ParseString(sb.String())
+	if !errors.Is(err, ErrDepthLimitExceeded) {
+		t.Fatalf("ParseString(deep) error = %v, want ErrDepthLimitExceeded", err)
+	}
+}
+
+func TestDecodeOptionsNodeFilter(t *testing.T) {
+	t.Parallel()
+
+	src := NewDocument()
+	root := NewObjectNode("root")
+	keep := NewObjectNode("keep")
+	keep.Add(NewStringNode("name", "a"))
+	keep.Add(NewUint32Node("id", 1))
+	drop := NewObjectNode("drop")
+	drop.Add(NewStringNode("name", "b"))
+	root.Add(keep)
+	root.Add(drop)
+	root.Add(NewStringNode("skip", "x"))
+	src.AddRoot(root)
+
+	var paths []string
+	filter := func(path []string, key string, kind NodeKind) bool {
+		paths = append(paths, strings.Join(append(path[:len(path):len(path)], key), "/"))
+		return key != "drop" && key != "id" && !(len(path) == 1 && kind == NodeString)
+	}
+
+	for _, format := range []Format{FormatText, FormatBinary} {
+		paths = paths[:0]
+
+		var data []byte
+		var err error
+		if format == FormatText {
+			data, err = AppendText(nil, src, EncodeOptions{})
+		} else {
+			data, err = AppendBinary(nil, src, EncodeOptions{})
+		}
+		if err != nil {
+			t.Fatalf("encode(format=%d) returned error: %v", format, err)
+		}
+
+		doc, err := ParseBytes(data, DecodeOptions{Format: format, NodeFilter: filter})
+		if err != nil {
+			t.Fatalf("ParseBytes(format=%d) returned error: %v", format, err)
+		}
+
+		got, err := AppendText(nil, doc, EncodeOptions{Compact: true})
+		if err != nil {
+			t.Fatalf("AppendText(format=%d) returned error: %v", format, err)
+		}
+
+		if want := `"root" { "keep" { "name" "a" } } `; string(got) != want {
+			t.Fatalf("filtered document (format=%d) = %q, want %q", format, got, want)
+		}
+
+		wantPaths := "root,root/keep,root/keep/name,root/keep/id,root/drop,root/skip"
+		if gotPaths := strings.Join(paths, ","); gotPaths != wantPaths {
+			t.Fatalf("filter paths (format=%d) = %q, want %q", format, gotPaths, wantPaths)
+		}
+	}
+}
+
+func TestDecodeOptionsKeyMap(t *testing.T) {
+	t.Parallel()
+
+	input := `"AppState" { "Name" "x" "UserConfig" { "Language" "english" } }`
+	keyMap := func(path []string, key string) string {
+		if len(path) > 1 {
+			return "cfg_" + strings.ToLower(key)
+		}
+
+		return strings.ToLower(key)
+	}
+
+	doc, err := ParseBytes([]byte(input), DecodeOptions{Format: FormatText, KeyMap: keyMap})
+	if err != nil {
+		t.Fatalf("ParseBytes(text) returned error: %v", err)
+	}
+
+	want := `"appstate" { "name" "x" "userconfig" { "cfg_language" "english" } } `
+	got, err := AppendText(nil, doc, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	if string(got) != want {
+		t.Fatalf("mapped text document = %q, want %q", got, want)
+	}
+
+	bin, err := AppendBinary(nil, mustParseString(t, input), EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	doc, err = ParseBytes(bin, DecodeOptions{Format: FormatBinary, KeyMap: keyMap})
+	if err != nil {
+		t.Fatalf("ParseBytes(binary) returned error: %v", err)
+	}
+
+	got, err = AppendText(nil, doc, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	if string(got) != want {
+		t.Fatalf("mapped binary document = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeOptionsRecordSpans(t *testing.T) {
+	t.Parallel()
+
+	input := "\"root\"\n{\n\t\"name\"\t\"vé\"\n\tcount 7\n\t\"sub\" { }\n}\n"
+	doc, err := ParseBytes([]byte(input), DecodeOptions{Format: FormatText, RecordSpans: true})
+	if err != nil {
+		t.Fatalf("ParseBytes(text) returned error: %v", err)
+	}
+
+	slice := func(r Span, value bool) string {
+		if value {
+			return input[r.ValueStart:r.ValueEnd]
+		}
+
+		return input[r.KeyStart:r.KeyEnd]
+	}
+
+	root := doc.Roots[0]
+	cases := []struct {
+		node      *Node
+		key, want string
+	}{
+		{root, `"root"`, "{\n\t\"name\"\t\"vé\"\n\tcount 7\n\t\"sub\" { }\n}"},
+		{root.First("name"), `"name"`, `"vé"`},
+		{root.First("count"), `count`, `7`},
+		{root.First("sub"), `"sub"`, `{ }`},
+	}
+
+	for _, tc := range cases {
+		if tc.node.Span == nil {
+			t.Fatalf("node %q has no span", tc.node.Key)
+		}
+
+		if got := slice(*tc.node.Span, false); got != tc.key {
+			t.Fatalf("key span of %q = %q, want %q", tc.node.Key, got, tc.key)
+		}
+
+		if got := slice(*tc.node.Span, true); got != tc.want {
+			t.Fatalf("value span of %q = %q, want %q", tc.node.Key, got, tc.want)
+		}
+	}
+
+	bin, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	decoded, err := ParseBytes(bin, DecodeOptions{Format: FormatBinary, RecordSpans: true})
+	if err != nil {
+		t.Fatalf("ParseBytes(binary) returned error: %v", err)
+	}
+
+	name := decoded.Roots[0].First("name").Span
+	if got := string(bin[name.KeyStart:name.KeyEnd]); got != "name" {
+		t.Fatalf("binary key span = %q, want %q", got, "name")
+	}
+
+	if got := string(bin[name.ValueStart:name.ValueEnd]); got != "vé" {
+		t.Fatalf("binary value span = %q, want %q", got, "vé")
+	}
+
+	rootSpan := decoded.Roots[0].Span
+	if rootSpan.ValueEnd != int64(len(bin)-1) || bin[rootSpan.ValueEnd-1] != binaryTypeMapEnd {
+		t.Fatalf("binary root span end = %d, want %d", rootSpan.ValueEnd, len(bin)-1)
+	}
+
+	plain := mustParseString(t, input)
+	if plain.Roots[0].Span != nil {
+		t.Fatal("spans recorded without RecordSpans")
+	}
+}
+
+func TestDecodeOptionsDuplicatePolicy(t *testing.T) {
+	t.Parallel()
+
+	input := `"root" { "a" "1" "b" { "x" "1" } "a" "2" "b" { "y" "2" } "a" "3" } "root" { "c" "4" }`
+	bin, err := AppendBinary(nil, mustParseString(t, input), EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		policy DuplicatePolicy
+		want   string
+	}{
+		{"keep-all", DuplicateKeepAll, `"root" { "a" "1" "b" { "x" "1" } "a" "2" "b" { "y" "2" } "a" "3" } "root" { "c" "4" } `},
+		{"first-wins", DuplicateFirstWins, `"root" { "a" "1" "b" { "x" "1" } } `},
+		{"last-wins", DuplicateLastWins, `"root" { "c" "4" } `},
+	}
+
+	for _, tc := range cases {
+		for format, data := range map[Format][]byte{FormatText: []byte(input), FormatBinary: bin} {
+			doc, err := ParseBytes(data, DecodeOptions{Format: format, DuplicatePolicy: tc.policy})
+			if err != nil {
+				t.Fatalf("%s/%d: ParseBytes() returned error: %v", tc.name, format, err)
+			}
+
+			got, err := AppendText(nil, doc, EncodeOptions{Compact: true})
+			if err != nil {
+				t.Fatalf("%s/%d: AppendText() returned error: %v", tc.name, format, err)
+			}
+
+			if string(got) != tc.want {
+				t.Fatalf("%s/%d: document = %q, want %q", tc.name, format, got, tc.want)
+			}
+		}
+	}
+
+	nested := `"root" { "a" "1" "b" { "x" "1" } "a" "2" "b" { "y" "2" } "a" "3" }`
+	doc, err := ParseBytes([]byte(nested), DecodeOptions{Format: FormatText, DuplicatePolicy: DuplicateLastWins})
+	if err != nil {
+		t.Fatalf("ParseBytes(last-wins) returned error: %v", err)
+	}
+
+	if got, _ := AppendText(nil, doc, EncodeOptions{Compact: true}); string(got) != `"root" { "a" "3" "b" { "y" "2" } } ` {
+		t.Fatalf("last-wins nested document = %q", got)
+	}
+
+	for format, data := range map[Format][]byte{FormatText: []byte(input), FormatBinary: bin} {
+		if _, err := ParseBytes(data, DecodeOptions{Format: format, DuplicatePolicy: DuplicateError}); !errors.Is(err, ErrDuplicateKeyInStrictMode) {
+			t.Fatalf("%d: ParseBytes(error policy) error = %v, want ErrDuplicateKeyInStrictMode", format, err)
+		}
+	}
+}
+
+func TestParseDirectives(t *testing.T) {
+	t.Parallel()
+
+	input := `#base "base.res"
+"#include"	"scheme/common.res"
+"Resource"
+{
+	"#base"		"kept"
+}
+#BASE "other.res"
+`
+
+	doc, err := ParseBytes([]byte(input), DecodeOptions{Format: FormatText, DuplicatePolicy: DuplicateError})
+	if err != nil {
+		t.Fatalf("ParseString() returned error: %v", err)
+	}
+
+	want := []struct {
+		key   string
+		value string
+		kind  NodeKind
+	}{
+		{key: "#base", value: "base.res", kind: NodeDirective},
+		{key: "#include", value: "scheme/common.res", kind: NodeDirective},
+		{key: "Resource", kind: NodeObject},
+		{key: "#BASE", value: "other.res", kind: NodeDirective},
+	}
+
+	if len(doc.Roots) != len(want) {
+		t.Fatalf("root count = %d, want %d", len(doc.Roots), len(want))
+	}
+
+	for i, w := range want {
+		root := doc.Roots[i]
+		if root.Key != w.key || root.Kind != w.kind || (w.kind == NodeDirective && *root.StringValue != w.value) {
+			t.Fatalf("root[%d] = %q kind %d value %v, want %+v", i, root.Key, root.Kind, root.Value(), w)
+		}
+	}
+
+	if inner := doc.Roots[2].First("#base"); inner == nil || inner.Kind != NodeString {
+		t.Fatalf("nested #base = %+v, want string leaf", inner)
+	}
+
+	text, err := WriteString(doc)
+	if err != nil {
+		t.Fatalf("WriteString() returned error: %v", err)
+	}
+
+	if !strings.HasPrefix(text, "#base \"base.res\"\n") {
+		t.Fatalf("WriteString() = %q, want leading directive", text)
+	}
+
+	again, err := ParseString(text)
+	if err != nil {
+		t.Fatalf("ParseString() after write returned error: %v", err)
+	}
+
+	if changed := ChangedPaths(doc, again); len(changed) != 0 {
+		t.Fatalf("round trip changed %v:\n%s", changed, text)
+	}
+
+	if _, err := AppendBinary(nil, doc, EncodeOptions{}); !errors.Is(err, ErrInvalidFormat) {
+		t.Fatalf("AppendBinary() error = %v, want %v", err, ErrInvalidFormat)
+	}
+
+	nested := NewDocument()
+	object := NewObjectNode("root")
+	object.Add(NewDirectiveNode("#base", "x"))
+	nested.AddRoot(object)
+	if err := nested.Validate(); !errors.Is(err, ErrInvalidNodeState) {
+		t.Fatalf("Validate() error = %v, want %v", err, ErrInvalidNodeState)
+	}
+}
+
+func TestParseConditionals(t *testing.T) {
+	t.Parallel()
+
+	input := `"Resource"
+{
+	"font"		"Tahoma" [$WIN32]
+	"font"		"Verdana" [!$WIN32]
+	"tall" [$OSX] "12"
+	"panel" [$X360||$PS3]
+	{
+		"visible"	"0"
+	}
+	"plain"		"[not a tag]"
+	"url"		http://example.com/[path]
+}`
+
+	doc, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("ParseString() returned error: %v", err)
+	}
+
+	root := doc.Roots[0]
+	want := []struct {
+		key       string
+		condition string
+	}{
+		{key: "font", condition: "$WIN32"},
+		{key: "font", condition: "!$WIN32"},
+		{key: "tall", condition: "$OSX"},
+		{key: "panel", condition: "$X360||$PS3"},
+		{key: "plain"},
+		{key: "url"},
+	}
+
+	if len(root.Children) != len(want) {
+		t.Fatalf("child count = %d, want %d", len(root.Children), len(want))
+	}
+
+	for i, w := range want {
+		child := root.Children[i]
+		if child.Key != w.key || child.Condition != w.condition {
+			t.Fatalf("child[%d] = %q [%s], want %q [%s]", i, child.Key, child.Condition, w.key, w.condition)
+		}
+	}
+
+	if value := root.Children[5].Value(); value != "http://example.com/[path]" {
+		t.Fatalf("unquoted value = %v", value)
+	}
+
+	text, err := WriteString(doc)
+	if err != nil {
+		t.Fatalf("WriteString() returned error: %v", err)
+	}
+
+	if !strings.Contains(text, "\"font\"\t\t\"Tahoma\" [$WIN32]\n") || !strings.Contains(text, "\"panel\" [$X360||$PS3]\n") {
+		t.Fatalf("WriteString() lost conditionals:\n%s", text)
+	}
+
+	again, err := ParseString(text)
+	if err != nil {
+		t.Fatalf("ParseString() after write returned error: %v", err)
+	}
+
+	for i, child := range again.Roots[0].Children {
+		if child.Condition != want[i].condition {
+			t.Fatalf("round trip child[%d] condition = %q, want %q", i, child.Condition, want[i].condition)
+		}
+	}
+
+	if _, err := ParseString(`"a" "b" [$WIN32`); !errors.Is(err, ErrUnexpectedEOFInCondition) {
+		t.Fatalf("unterminated conditional error = %v, want %v", err, ErrUnexpectedEOFInCondition)
+	}
+}
+
+func TestDecodeOptionsConditions(t *testing.T) {
+	t.Parallel()
+
+	input := `#base "win.res" [$WIN32]
+"Resource"
+{
+	"font"		"Tahoma" [$WIN32]
+	"font"		"Verdana" [!$WIN32]
+	"deck" [$DECK&&$LINUX]
+	{
+		"scale"	"2"
+	}
+	"console"	"1" [$X360||$PS3]
+	"always"	"1"
+}`
+
+	tests := []struct {
+		conditions map[string]bool
+		name       string
+		want       []string
+		directives int
+	}{
+		{name: "kept without symbols", want: []string{"font=Tahoma", "font=Verdana", "deck", "console=1", "always=1"}, directives: 1},
+		{name: "windows", conditions: map[string]bool{"WIN32": true}, want: []string{"font=Tahoma", "always=1"}, directives: 1},
+		{name: "deck", conditions: map[string]bool{"$linux": true, "Deck": true}, want: []string{"font=Verdana", "deck", "always=1"}},
+		{name: "console", conditions: map[string]bool{"PS3": true, "WIN32": false}, want: []string{"font=Verdana", "console=1", "always=1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := DecodeOptions{Format: FormatText, Conditions: tt.conditions, DuplicatePolicy: DuplicateError}
+			if tt.conditions == nil {
+				opts.DuplicatePolicy = DuplicateKeepAll
+			}
+
+			doc, err := ParseBytes([]byte(input), opts)
+			if err != nil {
+				t.Fatalf("ParseBytes() returned error: %v", err)
+			}
+
+			if got := len(doc.Roots) - 1; got != tt.directives {
+				t.Fatalf("directive count = %d, want %d", got, tt.directives)
+			}
+
+			var got []string
+			for _, child := range doc.Roots[len(doc.Roots)-1].Children {
+				if child.Kind == NodeObject {
+					got = append(got, child.Key)
+					continue
+				}
+
+				got = append(got, child.Key+"="+*child.StringValue)
+			}
+
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("children = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeOptionsDisableEscapes(t *testing.T) {
+	t.Parallel()
+
+	input := `"paths" { "game" "C:\Games\new" "tab" "a\tb" }`
+	doc, err := ParseBytes([]byte(input), DecodeOptions{Format: FormatText, DisableEscapes: true})
+	if err != nil {
+		t.Fatalf("ParseBytes() returned error: %v", err)
+	}
+
+	root := doc.Roots[0]
+	if got := *root.First("game").StringValue; got != `C:\Games\new` {
+		t.Fatalf("game = %q, want literal backslashes", got)
+	}
+
+	if got := *root.First("tab").StringValue; got != `a\tb` {
+		t.Fatalf("tab = %q, want literal backslash", got)
+	}
+
+	out, err := AppendText(nil, doc, EncodeOptions{Compact: true, DisableEscapes: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	if want := `"paths" { "game" "C:\Games\new" "tab" "a\tb" } `; string(out) != want {
+		t.Fatalf("AppendText() = %q, want %q", out, want)
+	}
+
+	root.Add(NewStringNode("quote", `say "hi"`))
+	if _, err := AppendText(nil, doc, EncodeOptions{DisableEscapes: true}); !errors.Is(err, ErrValueConversion) {
+		t.Fatalf("AppendText(quote) error = %v, want ErrValueConversion", err)
+	}
+}
+
+func TestDecodeOptionsInvalidUTF8(t *testing.T) {
+	t.Parallel()
+
+	input := []byte("\"menu\" { \"title\" \"caf\xe9 \\\"bar\\\"\" }")
+
+	doc, err := ParseBytes(input, DecodeOptions{Format: FormatText})
+	if err != nil {
+		t.Fatalf("ParseBytes(replace) returned error: %v", err)
+	}
+
+	if got := *doc.Roots[0].First("title").StringValue; got != "caf� \"bar\"" {
+		t.Fatalf("replaced title = %q", got)
+	}
+
+	if _, err := ParseBytes(input, DecodeOptions{Format: FormatText, InvalidUTF8: InvalidUTF8Reject}); !errors.Is(err, ErrInvalidUTF8) {
+		t.Fatalf("ParseBytes(reject) error = %v, want ErrInvalidUTF8", err)
+	}
+
+	doc, err = ParseBytes(input, DecodeOptions{Format: FormatText, InvalidUTF8: InvalidUTF8PassThrough})
+	if err != nil {
+		t.Fatalf("ParseBytes(pass-through) returned error: %v", err)
+	}
+
+	if got := *doc.Roots[0].First("title").StringValue; got != "caf\xe9 \"bar\"" {
+		t.Fatalf("passed-through title = %q", got)
+	}
+
+	out, err := AppendText(nil, doc, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	if want := "\"menu\" { \"title\" \"caf\xe9 \\\"bar\\\"\" } "; string(out) != want {
+		t.Fatalf("AppendText() = %q, want %q", out, want)
+	}
+}
+
+func TestDecodeOptionsMaxInputBytes(t *testing.T) {
+	t.Parallel()
+
+	input := []byte(`"root" { "key" "value" }`)
+	doc := mustParseString(t, string(input))
+	bin, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	for _, data := range [][]byte{input, bin} {
+		if _, err := ParseBytes(data, DecodeOptions{MaxInputBytes: int64(len(data))}); err != nil {
+			t.Fatalf("ParseBytes(limit at size) returned error: %v", err)
+		}
+
+		_, err := ParseBytes(data, DecodeOptions{MaxInputBytes: int64(len(data) - 1)})
+		if !errors.Is(err, ErrInputLimitExceeded) {
+			t.Fatalf("ParseBytes(limit below size) error = %v, want ErrInputLimitExceeded", err)
+		}
+	}
+}
+
+func TestSafeDecodeOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := SafeDecodeOptions()
+	if _, err := ParseBytes([]byte(`"root" { "key" "value" }`), opts); err != nil {
+		t.Fatalf("ParseBytes(safe) returned error: %v", err)
+	}
+
+	deep := strings.Repeat(`"a" { `, 65) + strings.Repeat("} ", 65)
+	if _, err := ParseBytes([]byte(deep), opts); !errors.Is(err, ErrDepthLimitExceeded) {
+		t.Fatalf("ParseBytes(safe, deep) error = %v, want ErrDepthLimitExceeded", err)
+	}
+
+	if _, err := ParseBytes([]byte("\"key\" \"\xff\""), opts); !errors.Is(err, ErrInvalidUTF8) {
+		t.Fatalf("ParseBytes(safe, invalid UTF-8) error = %v, want ErrInvalidUTF8", err)
+	}
+}
+
+func TestDecodeOptionsRecover(t *testing.T) {
+	t.Parallel()
+
+	input := `} "root" { "a" "1" "bad" } "b" { "c" "2" "tail" "unterminated`
+	doc, err := ParseBytes([]byte(input), DecodeOptions{Format: FormatText, Recover: true})
+	if doc == nil {
+		t.Fatalf("ParseBytes(Recover) returned nil document, error: %v", err)
+	}
+
+	var list ErrorList
+	if !errors.As(err, &list) {
+		t.Fatalf("ParseBytes(Recover) error = %v, want ErrorList", err)
+	}
+
+	if len(list) != 4 {
+		t.Fatalf("ParseBytes(Recover) errors = %v, want 4", list)
+	}
+
+	for _, want := range []error{ErrExpectedStringKey, ErrExpectedValueOrObject, ErrUnexpectedEOFInObject} {
+		if !errors.Is(err, want) {
+			t.Fatalf("ParseBytes(Recover) error = %v, want %v", err, want)
+		}
+	}
+
+	if len(doc.Roots) != 2 || len(doc.Roots[0].Children) != 1 || *doc.Roots[0].Children[0].StringValue != "1" {
+		t.Fatalf("ParseBytes(Recover) roots = %+v, want root with one child", doc.Roots)
+	}
+
+	tail := doc.Roots[1].Children[1]
+	if tail.Key != "tail" || *tail.StringValue != "unterminated" {
+		t.Fatalf("ParseBytes(Recover) tail = %q %q, want partial value", tail.Key, *tail.StringValue)
+	}
+
+	if _, err := ParseBytes([]byte(input), DecodeOptions{Format: FormatText}); err == nil || errors.As(err, &list) {
+		t.Fatalf("ParseBytes(no Recover) error = %v, want first error only", err)
+	}
+}
+
+func TestDecodeErrorPositions(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseString("\"root\"\n{\n  \"key\" }\n")
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("ParseString() error = %v, want *ParseError", err)
+	}
+
+	if !errors.Is(err, ErrExpectedValueOrObject) || parseErr.Line != 3 || parseErr.Col != 9 || parseErr.Offset != 17 {
+		t.Fatalf("ParseError = %+v, want ErrExpectedValueOrObject at 3:9 offset 17", parseErr)
+	}
+
+	doc := mustParseString(t, `"root" { "key" "value" }`)
+	data, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	// Corrupt the type byte of "key" after the root type byte and "root\x00".
+	data[6] = 0x7F
+	for _, decode := range []func() error{
+		func() error {
+			_, err := ParseBytes(data, DecodeOptions{Format: FormatBinary})
+			return err
+		},
+		func() error {
+			decoder := NewDecoder(bytes.NewReader(data), DecodeOptions{Format: FormatBinary})
+			for {
+				if _, err := decoder.Token(); err != nil {
+					return err
+				}
+			}
+		},
+	} {
+		err := decode()
+		var binaryErr *BinaryError
+		if !errors.As(err, &binaryErr) || !errors.Is(err, ErrUnrecognizedType) {
+			t.Fatalf("binary decode error = %v, want *BinaryError with ErrUnrecognizedType", err)
+		}
+
+		if binaryErr.Offset < 7 || binaryErr.Offset > 11 {
+			t.Fatalf("BinaryError.Offset = %d, want offset past the corrupt type byte", binaryErr.Offset)
+		}
+	}
+}
+
+func TestDecodeOptionsTrackPositions(t *testing.T) {
+	t.Parallel()
+
+	input := "\"root\"\n{\n\t\"key\"  \"value\"\n\tsub { }\n}\n"
+	doc, err := ParseBytes([]byte(input), DecodeOptions{Format: FormatText, TrackPositions: true})
+	if err != nil {
+		t.Fatalf("ParseBytes(TrackPositions) returned error: %v", err)
+	}
+
+	root := doc.Roots[0]
+	want := []Pos{{Offset: 0, Line: 1, Col: 1}, {Offset: 10, Line: 3, Col: 2}, {Offset: 26, Line: 4, Col: 2}}
+	for i, node := range []*Node{root, root.Children[0], root.Children[1]} {
+		if node.Pos == nil || *node.Pos != want[i] {
+			t.Fatalf("node %q Pos = %+v, want %+v", node.Key, node.Pos, want[i])
+		}
+	}
+
+	data, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	doc, err = ParseBytes(data, DecodeOptions{Format: FormatBinary, TrackPositions: true})
+	if err != nil {
+		t.Fatalf("ParseBytes(binary, TrackPositions) returned error: %v", err)
+	}
+
+	// Keys follow their type bytes: "root" at 1, "key" after "root\x00" and its type byte.
+	if pos := doc.Roots[0].Children[0].Pos; pos == nil || *pos != (Pos{Offset: 7}) {
+		t.Fatalf("binary key Pos = %+v, want offset 7", pos)
+	}
+
+	if doc.Roots[0].Span != nil {
+		t.Fatalf("binary Span = %+v, want nil without RecordSpans", doc.Roots[0].Span)
+	}
+}
+
+func TestDecodeOptionsFoldKeys(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"AppState" { "Name" "Game" "UserConfig" { "Language" "English" } }`)
+	bin, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	text, err := AppendText(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	for _, data := range [][]byte{text, bin} {
+		folded, err := ParseBytes(data, DecodeOptions{FoldKeys: true})
+		if err != nil {
+			t.Fatalf("ParseBytes(FoldKeys) returned error: %v", err)
+		}
+
+		node, err := folded.lookupPath("appstate/userconfig/language")
+		if err != nil || *node.StringValue != "English" {
+			t.Fatalf("lookupPath(folded) = %v, %v, want English value", node, err)
+		}
+
+		decoder := NewDecoder(bytes.NewReader(data), DecodeOptions{FoldKeys: true})
+		for {
+			event, err := decoder.Token()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				t.Fatalf("Token(FoldKeys) returned error: %v", err)
+			}
+
+			if event.Key != strings.ToLower(event.Key) {
+				t.Fatalf("Token(FoldKeys) key = %q, want lower case", event.Key)
+			}
+		}
+	}
+}
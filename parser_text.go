@@ -5,138 +5,357 @@
 package vdf
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"strings"
 )
 
 // textParser parses text-lexer tokens into AST nodes.
 type textParser struct {
-	lexer     *textLexer    // Lexer for the input.
-	peeked    textToken     // Peeked token value.
-	hasPeeked bool          // Whether peek token is set.
-	opts      DecodeOptions // Decode options.
-	nodeCount int           // Number of nodes parsed.
+	lexer      *textLexer      // Lexer for the input.
+	path       []string        // Keys of currently open objects.
+	peeked     textToken       // Peeked token value.
+	hasPeeked  bool            // Whether peek token is set.
+	symbols    map[string]bool // Lowercased Conditions symbols, nil when conditionals are kept.
+	recovered  []error         // Errors recovered with DecodeOptions.Recover.
+	source     []byte          // Full input kept for layout capture with DecodeOptions.Fidelity.
+	opts       DecodeOptions   // Decode options.
+	nodeCount  int             // Number of nodes parsed.
+	lastEnd    int64           // Byte offset just past the last consumed token.
+	recovering bool            // Whether recoverable errors are collected instead of returned.
+}
+
+// textEntry stores one parsed key with its scalar value or object marker.
+type textEntry struct {
+	key       string      // Entry key.
+	value     string      // Scalar value for NodeString entries.
+	condition string      // Conditional without brackets, or empty.
+	layout    *TextLayout // Source text around the entry with DecodeOptions.Fidelity.
+	span      Span        // Source byte ranges; object values end at "{" until closed.
+	pos       Pos         // Key source position.
+	kind      NodeKind    // Entry kind.
+	keyQuoted bool        // Whether the key was written in double quotes.
+	valQuoted bool        // Whether the scalar value was written in double quotes.
 }
 
 // parseTextDocument parses one full text VDF stream.
 func parseTextDocument(r io.Reader, opts DecodeOptions) (*Document, error) {
 	parser := &textParser{
-		lexer: newTextLexer(r),
-		opts:  opts,
+		symbols: conditionSymbols(opts.Conditions),
+		opts:    opts,
 	}
 
+	if opts.Fidelity {
+		// Layout capture slices the source between token offsets.
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+
+		parser.source = data
+		r = bytes.NewReader(data)
+	}
+
+	parser.lexer = newTextLexer(r)
+	parser.lexer.applyOptions(opts)
+	if opts.Recover {
+		parser.recovering = true
+		parser.lexer.recover = func(err error) { parser.recovered = append(parser.recovered, err) }
+	}
+
+	doc, err := parser.parseDocument()
+	if err == nil && len(parser.recovered) > 0 {
+		return doc, ErrorList(parser.recovered)
+	}
+
+	return doc, err
+}
+
+// parseDocument parses all root entries with an explicit object stack.
+// Nesting depth is bounded only by decode options and available memory.
+// Objects rejected by NodeFilter stay on the stack as nil so their bodies are consumed and dropped.
+func (p *textParser) parseDocument() (*Document, error) {
 	doc := NewDocumentWithFormat(FormatText)
+	stack := make([]*Node, 0, 8)
+	p.path = make([]string, 0, 8)
 
 	for {
-		tok, err := parser.peekToken()
+		tok, err := p.peekToken()
 		if err != nil {
 			return nil, err
 		}
 
-		if tok.kind == textTokenEOF {
+		if len(stack) > 0 {
+			// Closing brace completes the current object scope.
+			if tok.kind == textTokenRBrace {
+				closingStart := p.lastEnd
+				if _, err := p.nextToken(); err != nil {
+					return nil, err
+				}
+
+				if closed := stack[len(stack)-1]; closed != nil && closed.Span != nil {
+					closed.Span.ValueEnd = tok.end
+				}
+
+				if closed := stack[len(stack)-1]; closed != nil && closed.Layout != nil {
+					closed.Layout.closing = string(p.source[closingStart:tok.start])
+				}
+
+				stack = stack[:len(stack)-1]
+				p.path = p.path[:len(p.path)-1]
+				continue
+			}
+
+			if tok.kind == textTokenEOF {
+				err := tok.errorAt(fmt.Errorf("%w for object %q", ErrUnexpectedEOFInObject, p.path[len(p.path)-1]))
+				if !p.recoverError(err) {
+					return nil, err
+				}
+
+				// Unclosed objects end at EOF.
+				stack = stack[:0]
+				p.path = p.path[:0]
+				continue
+			}
+		} else if tok.kind == textTokenRBrace && p.recovering {
+			if _, err := p.nextToken(); err != nil {
+				return nil, err
+			}
+
+			p.recoverError(tok.errorAt(fmt.Errorf("%w: stray '}'", ErrExpectedStringKey)))
+			continue
+		} else if tok.kind == textTokenEOF {
+			if p.source != nil {
+				doc.Layout = &TextLayout{closing: string(p.source[p.lastEnd:])}
+			}
+
 			return doc, nil
 		}
 
-		node, err := parser.parseNode(1)
+		entry, err := p.parseEntry(len(stack) + 1)
 		if err != nil {
 			return nil, err
 		}
 
-		if parser.opts.Strict && containsKey(doc.Roots, node.Key) {
-			return nil, fmt.Errorf("%w: root key %q", ErrDuplicateKeyInStrictMode, node.Key)
+		// Malformed entries dropped by recovery have no kind.
+		if entry.kind == 0 {
+			continue
 		}
 
-		doc.AddRoot(node)
+		// Directives bypass key mapping, filters and duplicate policy, as they are not keys.
+		if entry.kind == NodeDirective {
+			if !p.conditionHolds(entry) {
+				continue
+			}
+
+			doc.Roots = append(doc.Roots, p.newNode(entry))
+			continue
+		}
+
+		entry.key = mapDecodedKey(p.opts, p.path, entry.key)
+
+		var node *Node
+		if p.keepEntry(doc, stack, entry) {
+			node = p.newNode(entry)
+
+			if err := p.attachNode(doc, stack, node); err != nil {
+				return nil, err
+			}
+		}
+
+		if entry.kind == NodeObject {
+			stack = append(stack, node)
+			p.path = append(p.path, entry.key)
+		}
 	}
 }
 
-// parseNode parses either a scalar key/value entry or object entry.
-func (p *textParser) parseNode(depth int) (*Node, error) {
+// parseEntry parses one key followed by a scalar value or an object opening brace.
+// Object children are consumed by parseDocument.
+func (p *textParser) parseEntry(depth int) (textEntry, error) {
 	if err := p.checkDepth(depth); err != nil {
-		return nil, err
+		return textEntry{}, err
 	}
 
+	leadingStart := p.lastEnd
 	keyTok, err := p.nextToken()
 	if err != nil {
-		return nil, err
+		return textEntry{}, err
 	}
 
 	if keyTok.kind != textTokenString {
-		return nil, fmt.Errorf("%w at line %d, col %d", ErrExpectedStringKey, keyTok.line, keyTok.col)
+		err := keyTok.errorAt(ErrExpectedStringKey)
+		if p.recoverError(err) {
+			return textEntry{}, nil
+		}
+
+		return textEntry{}, err
 	}
 
-	nextTok, err := p.peekToken()
+	if p.opts.MaxKeyBytes > 0 && len(keyTok.value) > p.opts.MaxKeyBytes {
+		return textEntry{}, keyTok.errorAt(fmt.Errorf("%w: key exceeds %d bytes", ErrStringLimitExceeded, p.opts.MaxKeyBytes))
+	}
+
+	// The value token is capped by the string limit, other tokens by the key limit.
+	p.lexer.maxToken = p.opts.MaxStringBytes
+
+	nextTok, err := p.nextToken()
 	if err != nil {
-		return nil, err
+		return textEntry{}, err
+	}
+
+	// A conditional may sit between the key and its value or object.
+	var condition string
+	if nextTok.kind == textTokenCondition {
+		condition = nextTok.value
+		if nextTok, err = p.nextToken(); err != nil {
+			return textEntry{}, err
+		}
 	}
 
+	p.lexer.maxToken = p.opts.MaxKeyBytes
+
+	entry := textEntry{
+		condition: condition,
+		key:       keyTok.value,
+		keyQuoted: keyTok.quoted,
+		pos:       Pos{Offset: keyTok.start, Line: keyTok.line, Col: keyTok.col + 1},
+		span: Span{
+			KeyStart:   keyTok.start,
+			KeyEnd:     keyTok.end,
+			ValueStart: nextTok.start,
+			ValueEnd:   nextTok.end,
+		},
+	}
 	switch nextTok.kind {
 	case textTokenString:
-		valueTok, err := p.nextToken()
-		if err != nil {
-			return nil, err
+		if p.opts.MaxStringBytes > 0 && len(nextTok.value) > p.opts.MaxStringBytes {
+			return textEntry{}, nextTok.errorAt(fmt.Errorf("%w: value exceeds %d bytes", ErrStringLimitExceeded, p.opts.MaxStringBytes))
 		}
 
-		node := NewStringNode(keyTok.value, valueTok.value)
-		if err := p.incrementNodeCount(); err != nil {
-			return nil, err
+		entry.kind = NodeString
+		entry.value = nextTok.value
+		entry.valQuoted = nextTok.quoted
+		if depth == 1 && isDirectiveKey(keyTok.value) {
+			entry.kind = NodeDirective
 		}
 
-		return node, nil
+		// A conditional may also follow the scalar value.
+		if entry.condition == "" {
+			tok, err := p.peekToken()
+			if err != nil {
+				return textEntry{}, err
+			}
+
+			if tok.kind == textTokenCondition {
+				if _, err := p.nextToken(); err != nil {
+					return textEntry{}, err
+				}
+
+				entry.condition = tok.value
+			}
+		}
 	case textTokenLBrace:
-		return p.parseObject(keyTok.value, depth)
+		entry.kind = NodeObject
 	default:
-		return nil, fmt.Errorf("%w at line %d, col %d", ErrExpectedValueOrObject, nextTok.line, nextTok.col)
+		err := nextTok.errorAt(ErrExpectedValueOrObject)
+		if !p.recoverError(err) {
+			return textEntry{}, err
+		}
+
+		// Drop the key and leave the token, such as a closing brace, to the caller.
+		p.peeked, p.hasPeeked = nextTok, true
+		p.lastEnd = keyTok.end
+		return textEntry{}, nil
+	}
+
+	if err := p.incrementNodeCount(); err != nil {
+		return textEntry{}, err
 	}
+
+	if p.source != nil {
+		entry.layout = p.entryLayout(entry, leadingStart, keyTok, nextTok)
+	}
+
+	return entry, nil
 }
 
-// parseObject parses an object body until a matching closing brace.
-func (p *textParser) parseObject(key string, depth int) (*Node, error) {
-	lbrace, err := p.nextToken()
-	if err != nil {
-		return nil, err
+// keepEntry reports whether an entry should be built into the AST.
+func (p *textParser) keepEntry(doc *Document, stack []*Node, entry textEntry) bool {
+	// Descendants of a filtered-out object are dropped without consulting the filter.
+	if len(stack) > 0 && stack[len(stack)-1] == nil {
+		return false
 	}
 
-	if lbrace.kind != textTokenLBrace {
-		return nil, fmt.Errorf("%w at line %d, col %d", ErrExpectedObjectStart, lbrace.line, lbrace.col)
+	if !p.conditionHolds(entry) {
+		return false
 	}
 
-	node := NewObjectNode(key)
-	if err := p.incrementNodeCount(); err != nil {
-		return nil, err
+	siblings := doc.Roots
+	if len(stack) > 0 {
+		siblings = stack[len(stack)-1].Children
 	}
 
-	for {
-		tok, err := p.peekToken()
-		if err != nil {
-			return nil, err
-		}
+	if skipDuplicate(siblings, entry.key, p.opts.DuplicatePolicy) {
+		return false
+	}
 
-		// Closing brace completes the current object scope.
-		if tok.kind == textTokenRBrace {
-			if _, err := p.nextToken(); err != nil {
-				return nil, err
-			}
+	return p.opts.NodeFilter == nil || p.opts.NodeFilter(p.path, entry.key, entry.kind)
+}
 
-			return node, nil
-		}
+// node builds an AST node for a parsed entry.
+func (e textEntry) node() *Node {
+	var node *Node
+	switch e.kind {
+	case NodeObject:
+		node = NewObjectNode(e.key)
+	case NodeDirective:
+		node = NewDirectiveNode(e.key, e.value)
+	default:
+		node = NewStringNode(e.key, e.value)
+	}
 
-		if tok.kind == textTokenEOF {
-			return nil, fmt.Errorf("%w for object %q", ErrUnexpectedEOFInObject, key)
-		}
+	node.Condition = e.condition
+	node.Layout = e.layout
+	node.KeyUnquoted = !e.keyQuoted && e.kind != NodeDirective
+	node.ValueUnquoted = !e.valQuoted && e.kind != NodeObject
+	return node
+}
 
-		child, err := p.parseNode(depth + 1)
-		if err != nil {
-			return nil, err
-		}
+// newNode builds the node of entry with the source span and position requested by the options.
+func (p *textParser) newNode(entry textEntry) *Node {
+	node := entry.node()
+	if p.opts.RecordSpans {
+		span := entry.span
+		node.Span = &span
+	}
 
-		// Strict mode rejects duplicate keys at the same object depth.
-		if p.opts.Strict && containsKey(node.Children, child.Key) {
-			return nil, fmt.Errorf("%w: key %q in object %q", ErrDuplicateKeyInStrictMode, child.Key, key)
+	if p.opts.TrackPositions {
+		pos := entry.pos
+		node.Pos = &pos
+	}
+
+	return node
+}
+
+// attachNode appends a parsed node to the innermost open object or document roots.
+func (p *textParser) attachNode(doc *Document, stack []*Node, node *Node) error {
+	var ok bool
+	if len(stack) == 0 {
+		if doc.Roots, ok = addDecodedNode(doc.Roots, node, p.opts.DuplicatePolicy); !ok {
+			return fmt.Errorf("%w: root key %q", ErrDuplicateKeyInStrictMode, node.Key)
 		}
 
-		node.Add(child)
+		return nil
 	}
+
+	parent := stack[len(stack)-1]
+	if parent.Children, ok = addDecodedNode(parent.Children, node, p.opts.DuplicatePolicy); !ok {
+		return fmt.Errorf("%w: key %q in object %q", ErrDuplicateKeyInStrictMode, node.Key, parent.Key)
+	}
+
+	return nil
 }
 
 // nextToken consumes one token from parser stream.
@@ -144,10 +363,16 @@ func (p *textParser) nextToken() (textToken, error) {
 	if p.hasPeeked {
 		tok := p.peeked
 		p.hasPeeked = false
+		p.lastEnd = tok.end
 		return tok, nil
 	}
 
-	return p.lexer.nextToken()
+	tok, err := p.lexer.nextToken()
+	if err == nil {
+		p.lastEnd = tok.end
+	}
+
+	return tok, err
 }
 
 // peekToken peeks one token without consuming it.
@@ -185,6 +410,27 @@ func (p *textParser) incrementNodeCount() error {
 	return nil
 }
 
+// recoverError records err and reports true when DecodeOptions.Recover collects errors.
+func (p *textParser) recoverError(err error) bool {
+	if !p.recovering {
+		return false
+	}
+
+	p.recovered = append(p.recovered, err)
+	return true
+}
+
+// conditionHolds reports whether an entry survives conditional evaluation.
+func (p *textParser) conditionHolds(entry textEntry) bool {
+	return p.symbols == nil || entry.condition == "" || evalCondition(entry.condition, p.symbols)
+}
+
+// isDirectiveKey reports whether a root key names an "#include" or "#base" directive.
+// Valve matches directive names case-insensitively and regardless of quoting.
+func isDirectiveKey(key string) bool {
+	return strings.EqualFold(key, "#include") || strings.EqualFold(key, "#base")
+}
+
 // containsKey checks whether a node list already contains a key.
 func containsKey(nodes []*Node, key string) bool {
 	for _, node := range nodes {
@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// PatchOp defines the kind of one patch operation.
+type PatchOp uint8
+
+const (
+	// PatchAdd appends Value as the next occurrence of its key in the parent object.
+	PatchAdd PatchOp = iota + 1
+	// PatchRemove deletes the node at Path.
+	PatchRemove
+	// PatchReplace puts Value in place of the node at Path.
+	PatchReplace
+)
+
+// String returns the lower-case operation name.
+func (op PatchOp) String() string {
+	switch op {
+	case PatchAdd:
+		return "add"
+	case PatchRemove:
+		return "remove"
+	case PatchReplace:
+		return "replace"
+	default:
+		return "PatchOp(" + strconv.Itoa(int(op)) + ")"
+	}
+}
+
+// PatchOperation is one step of a Patch.
+type PatchOperation struct {
+	// Value is the node added or put in place, nil for PatchRemove.
+	Value *Node `json:"value,omitempty" yaml:"value,omitempty"`
+	// Path addresses the node in the document the patch is applied to,
+	// with "[n]" suffixes selecting repeated keys as in ChangedPaths.
+	Path string `json:"path" yaml:"path"`
+	// Op is the operation kind.
+	Op PatchOp `json:"op" yaml:"op"`
+}
+
+// Patch is an ordered list of operations; each path is valid after the operations before it.
+type Patch []PatchOperation
+
+// Diff returns the operations that turn a into b.
+// Entries are paired by key and occurrence as in ChangedPaths: objects present
+// on both sides are compared child by child, differing leaves and objects
+// replaced by leaves (or vice versa) are replaced, surplus occurrences in a are
+// removed from the last one down, and surplus occurrences in b are added in order.
+// Added entries are appended to their parent, and reordering entries without
+// changing them is not reported. Values are deep copies of nodes in b.
+func Diff(a, b *Document) Patch {
+	patch := make(Patch, 0)
+	diffNodes(documentRoots(a), documentRoots(b), "", &patch)
+	return patch
+}
+
+// diffNodes compares two sibling lists and appends operations turning left into right.
+func diffNodes(left, right []*Node, prefix string, patch *Patch) {
+	rightByKey := groupByKey(right)
+	leftByKey := groupByKey(left)
+	leftSeen := make(map[string]int, len(left))
+
+	for _, node := range left {
+		if node == nil {
+			continue
+		}
+
+		occurrence := leftSeen[node.Key]
+		leftSeen[node.Key]++
+
+		matches := rightByKey[node.Key]
+		if occurrence >= len(matches) {
+			continue
+		}
+
+		path := joinOccurrencePath(prefix, node.Key, occurrence)
+		other := matches[occurrence]
+		if node.Kind == NodeObject && other.Kind == NodeObject {
+			diffNodes(node.Children, other.Children, path, patch)
+			continue
+		}
+
+		if !leafEqual(node, other) || node.Condition != other.Condition {
+			*patch = append(*patch, PatchOperation{Op: PatchReplace, Path: path, Value: cloneNode(other)})
+		}
+	}
+
+	// Surplus left occurrences go from the last one down, so earlier indexes stay valid.
+	for i := len(left) - 1; i >= 0; i-- {
+		node := left[i]
+		if node == nil {
+			continue
+		}
+
+		leftSeen[node.Key]--
+		if occurrence := leftSeen[node.Key]; occurrence >= len(rightByKey[node.Key]) {
+			*patch = append(*patch, PatchOperation{Op: PatchRemove, Path: joinOccurrencePath(prefix, node.Key, occurrence)})
+		}
+	}
+
+	rightSeen := make(map[string]int, len(right))
+	for _, node := range right {
+		if node == nil {
+			continue
+		}
+
+		occurrence := rightSeen[node.Key]
+		rightSeen[node.Key]++
+		if occurrence >= len(leftByKey[node.Key]) {
+			*patch = append(*patch, PatchOperation{Op: PatchAdd, Path: joinOccurrencePath(prefix, node.Key, occurrence), Value: cloneNode(node)})
+		}
+	}
+}
+
+// ApplyPatch applies patch operations to doc in order, all or nothing:
+// the patch is first applied to a copy, and doc is changed only when every
+// operation succeeds, so nodes of doc not touched by the patch keep their identity.
+// Values are copied, so a patch can be applied to many documents.
+func ApplyPatch(doc *Document, patch Patch) error {
+	if doc == nil {
+		return fmt.Errorf("%w: nil document", ErrInvalidNodeState)
+	}
+
+	if err := applyPatch(doc.Clone(), patch); err != nil {
+		return err
+	}
+
+	return applyPatch(doc, patch)
+}
+
+// applyPatch applies operations to doc in order, stopping at the first failure.
+func applyPatch(doc *Document, patch Patch) error {
+	for i, op := range patch {
+		if err := applyPatchOperation(doc, op); err != nil {
+			return fmt.Errorf("%w: operation %d (%s %q): %w", ErrInvalidPatch, i, op.Op, op.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// applyPatchOperation applies one operation to doc.
+func applyPatchOperation(doc *Document, op PatchOperation) error {
+	segments, err := parsePath(op.Path)
+	if err != nil {
+		return err
+	}
+
+	if len(segments) == 0 {
+		return fmt.Errorf("%w: empty document path", ErrInvalidPath)
+	}
+
+	siblings := &doc.Roots
+	if len(segments) > 1 {
+		steps, err := resolvePathSteps(&doc.Roots, segments[:len(segments)-1])
+		if err != nil {
+			return err
+		}
+
+		parent := steps[len(steps)-1].node()
+		if parent.Kind != NodeObject {
+			return fmt.Errorf("%w: %q is not an object", ErrPathNotObject, parent.Key)
+		}
+
+		siblings = &parent.Children
+	}
+
+	last := segments[len(segments)-1]
+	if op.Op != PatchRemove && op.Value == nil {
+		return fmt.Errorf("%w: missing value", ErrInvalidNodeState)
+	}
+
+	switch op.Op {
+	case PatchAdd:
+		if countKey(*siblings, last.key) != last.occurrence {
+			return fmt.Errorf("%w: occurrence %d of %q", ErrPathNotFound, last.occurrence, last.key)
+		}
+
+		value := cloneNode(op.Value)
+		value.Key = last.key
+		*siblings = append(*siblings, value)
+	case PatchRemove, PatchReplace:
+		i := findOccurrence(*siblings, last)
+		if i < 0 {
+			return fmt.Errorf("%w: %q", ErrPathNotFound, op.Path)
+		}
+
+		if op.Op == PatchRemove {
+			*siblings = slices.Delete(*siblings, i, i+1)
+			return nil
+		}
+
+		value := cloneNode(op.Value)
+		value.Key = last.key
+		(*siblings)[i] = value
+	default:
+		return fmt.Errorf("unknown operation %d", op.Op)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package vdf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	a := mustParseString(t, `"cfg" { "name" "old" "tag" "a" "tag" "b" "tag" "c" "sub" { "x" "1" } "gone" "1" }`)
+	b := mustParseString(t, `"cfg" { "name" "new" "tag" "a" "sub" { "x" "1" "y" "2" } "extra" { "k" "v" } }`)
+
+	patch := Diff(a, b)
+	var got []string
+	for _, op := range patch {
+		got = append(got, op.Op.String()+" "+op.Path)
+	}
+
+	want := []string{
+		"replace cfg/name",
+		"add cfg/sub/y",
+		"remove cfg/gone",
+		"remove cfg/tag[2]",
+		"remove cfg/tag[1]",
+		"add cfg/extra",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("Diff() = %v, want %v", got, want)
+	}
+
+	if patch[0].Value == b.Get("cfg/name") || *patch[0].Value.StringValue != "new" {
+		t.Fatal("Diff() replace value is not a copy of the new node")
+	}
+
+	if len(Diff(a, a)) != 0 {
+		t.Fatalf("Diff(a, a) = %v, want empty", Diff(a, a))
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	t.Parallel()
+
+	const before = `"cfg" { "name" "old" "tag" "a" "tag" "b" "tag" "c" "sub" { "x" "1" } "gone" "1" }`
+	a := mustParseString(t, before)
+	b := mustParseString(t, `"cfg" { "name" "new" "tag" "a" "sub" { "x" "1" "y" "2" } "extra" { "k" "v" } }`)
+
+	// Patches survive a JSON round-trip for storage and replay.
+	data, err := json.Marshal(Diff(a, b))
+	if err != nil {
+		t.Fatalf("json.Marshal(patch) returned error: %v", err)
+	}
+
+	var patch Patch
+	if err := json.Unmarshal(data, &patch); err != nil {
+		t.Fatalf("json.Unmarshal(patch) returned error: %v", err)
+	}
+
+	sub := a.Get("cfg/sub")
+	if err := ApplyPatch(a, patch); err != nil {
+		t.Fatalf("ApplyPatch() returned error: %v", err)
+	}
+
+	if changed := ChangedPaths(a, b); len(changed) != 0 {
+		t.Fatalf("ApplyPatch() result differs at %v", changed)
+	}
+
+	if a.Get("cfg/sub") != sub {
+		t.Fatal("ApplyPatch() replaced an untouched node")
+	}
+
+	doc := mustParseString(t, before)
+	bad := Patch{
+		{Op: PatchReplace, Path: "cfg/name", Value: NewStringNode("name", "changed")},
+		{Op: PatchRemove, Path: "cfg/missing"},
+	}
+	if err := ApplyPatch(doc, bad); !errors.Is(err, ErrInvalidPatch) || !errors.Is(err, ErrPathNotFound) {
+		t.Fatalf("ApplyPatch(bad) error = %v, want ErrInvalidPatch and ErrPathNotFound", err)
+	}
+
+	if *doc.Get("cfg/name").StringValue != "old" {
+		t.Fatal("ApplyPatch(bad) changed the document")
+	}
+}
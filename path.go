@@ -0,0 +1,610 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PathSeparator separates keys in slash-delimited node paths.
+const PathSeparator = "/"
+
+// pathSegment is one parsed key selector of a slash-delimited node path.
+type pathSegment struct {
+	key        string // Node key to match.
+	occurrence int    // Zero-based occurrence among siblings with the same key.
+}
+
+// parsePath splits a slash-delimited path into key selectors.
+// A trailing "[n]" selects the n-th occurrence of a repeated key.
+func parsePath(path string) ([]pathSegment, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(path, PathSeparator)
+	segments := make([]pathSegment, 0, len(parts))
+	for _, part := range parts {
+		segment, err := parsePathSegment(part)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", err, path)
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments, nil
+}
+
+// parsePathSegment parses one key with an optional occurrence suffix.
+func parsePathSegment(part string) (pathSegment, error) {
+	if !strings.HasSuffix(part, "]") {
+		return pathSegment{key: part}, nil
+	}
+
+	open := strings.LastIndexByte(part, '[')
+	if open < 0 {
+		return pathSegment{key: part}, nil
+	}
+
+	occurrence, err := strconv.Atoi(part[open+1 : len(part)-1])
+	if err != nil || occurrence < 0 {
+		return pathSegment{}, fmt.Errorf("%w: bad occurrence in segment %q", ErrInvalidPath, part)
+	}
+
+	return pathSegment{key: part[:open], occurrence: occurrence}, nil
+}
+
+// joinOccurrencePath appends one key segment with an optional occurrence suffix.
+func joinOccurrencePath(prefix, key string, occurrence int) string {
+	var sb strings.Builder
+	sb.Grow(len(prefix) + len(key) + 4)
+
+	if prefix != "" {
+		sb.WriteString(prefix)
+		sb.WriteString(PathSeparator)
+	}
+
+	sb.WriteString(key)
+	if occurrence > 0 {
+		sb.WriteByte('[')
+		sb.WriteString(strconv.Itoa(occurrence))
+		sb.WriteByte(']')
+	}
+
+	return sb.String()
+}
+
+// findOccurrence returns the index of the n-th node with key in nodes or -1.
+func findOccurrence(nodes []*Node, segment pathSegment) int {
+	seen := 0
+	for i, node := range nodes {
+		if node == nil || node.Key != segment.key {
+			continue
+		}
+
+		if seen == segment.occurrence {
+			return i
+		}
+
+		seen++
+	}
+
+	return -1
+}
+
+// EnsurePath walks a slash-delimited path below n, creating missing object
+// nodes along the way, and returns the final node.
+// Segments may select repeated keys with a "[n]" suffix; a missing occurrence
+// is created only when it would be the next one.
+// An empty path returns n itself.
+func (n *Node) EnsurePath(path string) (*Node, error) {
+	if n == nil || n.Kind != NodeObject {
+		return nil, fmt.Errorf("%w: %q is not an object", ErrPathNotObject, nodeKey(n))
+	}
+
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ensureSegments(n, segments)
+}
+
+// EnsurePath walks a slash-delimited path from document roots, creating
+// missing object nodes along the way, and returns the final node.
+// The first segment selects or creates a root.
+func (d *Document) EnsurePath(path string) (*Node, error) {
+	if d == nil {
+		return nil, fmt.Errorf("%w: nil document", ErrInvalidNodeState)
+	}
+
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("%w: empty document path", ErrInvalidPath)
+	}
+
+	root, err := ensureChild(&d.Roots, segments[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return ensureSegments(root, segments[1:])
+}
+
+// SetDuplicates defines which existing siblings Document.Set updates
+// when the final path key is repeated.
+type SetDuplicates uint8
+
+const (
+	// SetFirst updates the first sibling with the key, or the n-th with a "[n]" suffix.
+	SetFirst SetDuplicates = iota
+	// SetAll updates every sibling with the key.
+	SetAll
+	// SetReplace updates the first sibling with the key and removes the others.
+	SetReplace
+	// SetAppend always appends a new node, adding a duplicate when the key exists.
+	SetAppend
+)
+
+// SetOptions controls Document.Set.
+type SetOptions struct {
+	// Duplicates selects the siblings updated when the final key is repeated.
+	// SetAll, SetReplace and SetAppend ignore a "[n]" suffix on the final segment.
+	Duplicates SetDuplicates
+	// MustExist fails with ErrPathNotFound instead of creating missing
+	// intermediate objects or the final node.
+	MustExist bool
+}
+
+// Set stores value at a slash-delimited path, walking or creating intermediate
+// objects, and returns the affected node, so simple config edits are one line:
+//
+//	doc.Set("AppState/UserConfig/language", "english")
+//
+// Supported values are those accepted by FromMap: string, uint32, in-range
+// integers, and Map or map[string]any for object subtrees.
+// Existing nodes are updated in place, keeping their position among siblings;
+// with SetAll the first updated node is returned.
+// At most one SetOptions is used.
+func (d *Document) Set(path string, value any, opts ...SetOptions) (*Node, error) {
+	if d == nil {
+		return nil, fmt.Errorf("%w: nil document", ErrInvalidNodeState)
+	}
+
+	var options SetOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	if options.Duplicates > SetAppend {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidSetDuplicates, options.Duplicates)
+	}
+
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("%w: empty document path", ErrInvalidPath)
+	}
+
+	last := segments[len(segments)-1]
+	replacement, err := mapValueToNode(last.key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	siblings, err := setParent(d, segments[:len(segments)-1], options.MustExist)
+	if err != nil {
+		return nil, err
+	}
+
+	switch options.Duplicates {
+	case SetFirst:
+		if i := findOccurrence(*siblings, last); i >= 0 {
+			node := (*siblings)[i]
+			replaceNodeValue(node, replacement)
+			return node, nil
+		}
+
+		if countKey(*siblings, last.key) != last.occurrence {
+			return nil, fmt.Errorf("%w: occurrence %d of %q", ErrPathNotFound, last.occurrence, last.key)
+		}
+	case SetAll, SetReplace:
+		var first *Node
+		kept := (*siblings)[:0]
+		for _, node := range *siblings {
+			if node == nil || node.Key != last.key {
+				kept = append(kept, node)
+				continue
+			}
+
+			if first != nil && options.Duplicates == SetReplace {
+				continue
+			}
+
+			if first == nil {
+				first = node
+				replaceNodeValue(node, replacement)
+			} else {
+				replaceNodeValue(node, cloneNode(replacement))
+			}
+
+			kept = append(kept, node)
+		}
+
+		clear((*siblings)[len(kept):])
+		*siblings = kept
+		if first != nil {
+			return first, nil
+		}
+	}
+
+	if options.MustExist && options.Duplicates != SetAppend {
+		return nil, fmt.Errorf("%w: %q", ErrPathNotFound, path)
+	}
+
+	*siblings = append(*siblings, replacement)
+	return replacement, nil
+}
+
+// setParent returns the sibling list holding the final node of a Set path,
+// creating missing objects unless mustExist is set.
+func setParent(d *Document, segments []pathSegment, mustExist bool) (*[]*Node, error) {
+	if len(segments) == 0 {
+		return &d.Roots, nil
+	}
+
+	var parent *Node
+	if mustExist {
+		steps, err := resolvePathSteps(&d.Roots, segments)
+		if err != nil {
+			return nil, err
+		}
+
+		parent = steps[len(steps)-1].node()
+	} else {
+		root, err := ensureChild(&d.Roots, segments[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if parent, err = ensureSegments(root, segments[1:]); err != nil {
+			return nil, err
+		}
+	}
+
+	if parent.Kind != NodeObject {
+		return nil, fmt.Errorf("%w: %q is not an object", ErrPathNotObject, parent.Key)
+	}
+
+	return &parent.Children, nil
+}
+
+// SetPath stores value at a slash-delimited path, creating intermediate objects
+// as needed, and returns the affected node.
+// It is Set with default options.
+func (d *Document) SetPath(path string, value any) (*Node, error) {
+	return d.Set(path, value)
+}
+
+// DeletePath removes the node addressed by a slash-delimited path and returns it.
+// When prune is true, ancestor objects left without children are removed too,
+// up to and including the root.
+func (d *Document) DeletePath(path string, prune bool) (*Node, error) {
+	if d == nil {
+		return nil, fmt.Errorf("%w: nil document", ErrInvalidNodeState)
+	}
+
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("%w: empty document path", ErrInvalidPath)
+	}
+
+	steps, err := resolvePathSteps(&d.Roots, segments)
+	if err != nil {
+		return nil, err
+	}
+
+	last := steps[len(steps)-1]
+	removed := last.node()
+	last.remove()
+
+	if prune {
+		for i := len(steps) - 2; i >= 0; i-- {
+			ancestor := steps[i].node()
+			if len(ancestor.Children) != 0 {
+				break
+			}
+
+			steps[i].remove()
+		}
+	}
+
+	return removed, nil
+}
+
+// Get returns the node addressed by a slash-delimited path from document roots,
+// or nil when the path is malformed or matches nothing.
+// Each segment selects the first child with its key, or the n-th with a "[n]" suffix,
+// so Get("Steam/Apps/440/name") replaces a chain of First calls with nil checks.
+func (d *Document) Get(path string) *Node {
+	node, err := d.lookupPath(path)
+	if err != nil {
+		return nil
+	}
+
+	return node
+}
+
+// GetKeys is like Get but takes literal keys, so keys holding "/" or "[n]" need no escaping.
+func (d *Document) GetKeys(keys ...string) *Node {
+	if d == nil || len(keys) == 0 {
+		return nil
+	}
+
+	return lookupSegments(d.Roots, keySegments(keys))
+}
+
+// Get returns the node addressed by a slash-delimited path below n,
+// or nil when the path is malformed or matches nothing.
+// An empty path returns n itself.
+func (n *Node) Get(path string) *Node {
+	if n == nil {
+		return nil
+	}
+
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil
+	}
+
+	if len(segments) == 0 {
+		return n
+	}
+
+	if n.Kind != NodeObject {
+		return nil
+	}
+
+	return lookupSegments(n.Children, segments)
+}
+
+// GetKeys is like Get but takes literal keys; no keys return n itself.
+func (n *Node) GetKeys(keys ...string) *Node {
+	if n == nil || len(keys) == 0 {
+		return n
+	}
+
+	if n.Kind != NodeObject {
+		return nil
+	}
+
+	return lookupSegments(n.Children, keySegments(keys))
+}
+
+// keySegments converts literal keys to first-occurrence path segments.
+func keySegments(keys []string) []pathSegment {
+	segments := make([]pathSegment, len(keys))
+	for i, key := range keys {
+		segments[i] = pathSegment{key: key}
+	}
+
+	return segments
+}
+
+// lookupSegments resolves segments in nodes and returns the final node or nil.
+func lookupSegments(nodes []*Node, segments []pathSegment) *Node {
+	steps, err := resolvePathSteps(&nodes, segments)
+	if err != nil {
+		return nil
+	}
+
+	return steps[len(steps)-1].node()
+}
+
+// Delete removes nodes matching a path and returns how many were removed.
+// The path uses the query syntax of CompilePath, so a key without "[n]" matches
+// every duplicate sibling and wildcards are allowed. Without all, only the first
+// match in source order is removed. A path matching nothing removes nothing.
+func (d *Document) Delete(path string, all bool) (int, error) {
+	if d == nil {
+		return 0, fmt.Errorf("%w: nil document", ErrInvalidNodeState)
+	}
+
+	selector, err := CompilePath(path)
+	if err != nil {
+		return 0, err
+	}
+
+	limit := 0
+	if !all {
+		limit = 1
+	}
+
+	matches := selectSegments(d.Roots, selector.segments, limit)
+	if len(matches) == 0 {
+		return 0, nil
+	}
+
+	targets := make(map[*Node]struct{}, len(matches))
+	for _, node := range matches {
+		targets[node] = struct{}{}
+	}
+
+	return removeNodes(&d.Roots, targets), nil
+}
+
+// removeNodes deletes targets from a sibling list and its descendants, preserving order,
+// and returns the number removed. Subtrees of removed nodes are not searched.
+func removeNodes(siblings *[]*Node, targets map[*Node]struct{}) int {
+	removed := 0
+	stack := []*[]*Node{siblings}
+	for len(stack) > 0 {
+		list := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		kept := (*list)[:0]
+		for _, node := range *list {
+			if _, ok := targets[node]; ok && node != nil {
+				removed++
+				continue
+			}
+
+			kept = append(kept, node)
+			if node != nil && node.Kind == NodeObject {
+				stack = append(stack, &node.Children)
+			}
+		}
+
+		clear((*list)[len(kept):])
+		*list = kept
+	}
+
+	return removed
+}
+
+// lookupPath resolves an existing node by slash-delimited path without creating nodes.
+func (d *Document) lookupPath(path string) (*Node, error) {
+	if d == nil {
+		return nil, fmt.Errorf("%w: nil document", ErrInvalidNodeState)
+	}
+
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("%w: empty document path", ErrInvalidPath)
+	}
+
+	steps, err := resolvePathSteps(&d.Roots, segments)
+	if err != nil {
+		return nil, err
+	}
+
+	return steps[len(steps)-1].node(), nil
+}
+
+// pathStep locates one resolved path node within its sibling list.
+type pathStep struct {
+	siblings *[]*Node // Sibling list containing the node.
+	index    int      // Node index within siblings.
+}
+
+// node returns the node addressed by the step.
+func (s pathStep) node() *Node {
+	return (*s.siblings)[s.index]
+}
+
+// remove deletes the addressed node from its sibling list preserving order.
+func (s pathStep) remove() {
+	*s.siblings = slices.Delete(*s.siblings, s.index, s.index+1)
+}
+
+// resolvePathSteps resolves every segment to its sibling list and index without creating nodes.
+func resolvePathSteps(siblings *[]*Node, segments []pathSegment) ([]pathStep, error) {
+	steps := make([]pathStep, 0, len(segments))
+	for i, segment := range segments {
+		index := findOccurrence(*siblings, segment)
+		if index < 0 {
+			return nil, fmt.Errorf("%w: %q", ErrPathNotFound, joinSegments(segments[:i+1]))
+		}
+
+		step := pathStep{siblings: siblings, index: index}
+		steps = append(steps, step)
+
+		if i == len(segments)-1 {
+			break
+		}
+
+		node := step.node()
+		if node.Kind != NodeObject {
+			return nil, fmt.Errorf("%w: %q is not an object", ErrPathNotObject, node.Key)
+		}
+
+		siblings = &node.Children
+	}
+
+	return steps, nil
+}
+
+// joinSegments formats parsed segments back into a slash-delimited path.
+func joinSegments(segments []pathSegment) string {
+	path := ""
+	for _, segment := range segments {
+		path = joinOccurrencePath(path, segment.key, segment.occurrence)
+	}
+
+	return path
+}
+
+// ensureSegments descends from node through segments, creating missing objects.
+func ensureSegments(node *Node, segments []pathSegment) (*Node, error) {
+	for _, segment := range segments {
+		if node.Kind != NodeObject {
+			return nil, fmt.Errorf("%w: %q is not an object", ErrPathNotObject, node.Key)
+		}
+
+		child, err := ensureChild(&node.Children, segment)
+		if err != nil {
+			return nil, err
+		}
+
+		node = child
+	}
+
+	return node, nil
+}
+
+// ensureChild selects a node for segment in a sibling list, appending a new object when missing.
+func ensureChild(nodes *[]*Node, segment pathSegment) (*Node, error) {
+	if i := findOccurrence(*nodes, segment); i >= 0 {
+		return (*nodes)[i], nil
+	}
+
+	if countKey(*nodes, segment.key) != segment.occurrence {
+		return nil, fmt.Errorf("%w: occurrence %d of %q", ErrPathNotFound, segment.occurrence, segment.key)
+	}
+
+	child := NewObjectNode(segment.key)
+	*nodes = append(*nodes, child)
+	return child, nil
+}
+
+// countKey returns the number of nodes with key.
+func countKey(nodes []*Node, key string) int {
+	count := 0
+	for _, node := range nodes {
+		if node != nil && node.Key == key {
+			count++
+		}
+	}
+
+	return count
+}
+
+// nodeKey returns the node key or an empty string for nil nodes.
+func nodeKey(node *Node) string {
+	if node == nil {
+		return ""
+	}
+
+	return node.Key
+}
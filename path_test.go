@@ -0,0 +1,285 @@
+package vdf
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestEnsurePath(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"Registry" { "HKCU" { "Software" { "name" "x" } } }`)
+
+	steam, err := doc.EnsurePath("Registry/HKCU/Software/Valve/Steam")
+	if err != nil {
+		t.Fatalf("EnsurePath() returned error: %v", err)
+	}
+
+	if steam.Kind != NodeObject || steam.Key != "Steam" {
+		t.Fatalf("EnsurePath() node = %+v, want object Steam", steam)
+	}
+
+	again, err := doc.EnsurePath("Registry/HKCU/Software/Valve/Steam")
+	if err != nil || again != steam {
+		t.Fatalf("EnsurePath(existing) = %p, %v, want %p", again, err, steam)
+	}
+
+	software := doc.Roots[0].First("HKCU").First("Software")
+	if len(software.Children) != 2 {
+		t.Fatalf("Software children = %d, want 2", len(software.Children))
+	}
+
+	valve, err := software.EnsurePath("Valve")
+	if err != nil || valve != software.First("Valve") {
+		t.Fatalf("Node.EnsurePath(Valve) = %p, %v", valve, err)
+	}
+
+	if _, err := doc.EnsurePath("Registry/HKCU/Software/name/child"); !errors.Is(err, ErrPathNotObject) {
+		t.Fatalf("EnsurePath(through leaf) error = %v, want ErrPathNotObject", err)
+	}
+
+	second, err := doc.EnsurePath("Registry[1]")
+	if err != nil || second == doc.Roots[0] || len(doc.Roots) != 2 {
+		t.Fatalf("EnsurePath(Registry[1]) = %p, %v, roots=%d", second, err, len(doc.Roots))
+	}
+
+	if _, err := doc.EnsurePath("Registry[5]"); !errors.Is(err, ErrPathNotFound) {
+		t.Fatalf("EnsurePath(Registry[5]) error = %v, want ErrPathNotFound", err)
+	}
+
+	if _, err := doc.EnsurePath("Registry[x]"); !errors.Is(err, ErrInvalidPath) {
+		t.Fatalf("EnsurePath(Registry[x]) error = %v, want ErrInvalidPath", err)
+	}
+}
+
+func TestDocumentSetPath(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"root" { "apps" { "440" { "name" "old" "size" "1" } } }`)
+
+	name, err := doc.SetPath("root/apps/440/name", "tf2")
+	if err != nil {
+		t.Fatalf("SetPath(existing) returned error: %v", err)
+	}
+
+	if name != doc.Roots[0].First("apps").First("440").Children[0] {
+		t.Fatalf("SetPath(existing) must update node in place")
+	}
+
+	if _, err := doc.SetPath("root/apps/440/size", uint32(42)); err != nil {
+		t.Fatalf("SetPath(uint32) returned error: %v", err)
+	}
+
+	if _, err := doc.SetPath("root/apps/570", Map{"name": "dota"}); err != nil {
+		t.Fatalf("SetPath(Map) returned error: %v", err)
+	}
+
+	if _, err := doc.SetPath("top", "v"); err != nil {
+		t.Fatalf("SetPath(root leaf) returned error: %v", err)
+	}
+
+	got, err := AppendText(nil, doc, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	want := `"root" { "apps" { "440" { "name" "tf2" "size" "42" } "570" { "name" "dota" } } } "top" "v" `
+	if string(got) != want {
+		t.Fatalf("SetPath() document = %q, want %q", got, want)
+	}
+
+	if _, err := doc.SetPath("top/child", "v"); !errors.Is(err, ErrPathNotObject) {
+		t.Fatalf("SetPath(through leaf) error = %v, want ErrPathNotObject", err)
+	}
+
+	if _, err := doc.SetPath("root/bad", true); !errors.Is(err, ErrUnsupportedMapValueType) {
+		t.Fatalf("SetPath(bool) error = %v, want ErrUnsupportedMapValueType", err)
+	}
+}
+
+func TestDocumentDeletePath(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"root" { "a" { "b" { "leaf" "1" } } "keep" "x" "dup" "1" "dup" "2" }`)
+
+	removed, err := doc.DeletePath("root/dup[1]", false)
+	if err != nil {
+		t.Fatalf("DeletePath(dup[1]) returned error: %v", err)
+	}
+
+	if removed.StringValue == nil || *removed.StringValue != "2" {
+		t.Fatalf("DeletePath(dup[1]) removed %+v, want value 2", removed)
+	}
+
+	if _, err := doc.DeletePath("root/a/b/leaf", true); err != nil {
+		t.Fatalf("DeletePath(prune) returned error: %v", err)
+	}
+
+	got, err := AppendText(nil, doc, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	if want := `"root" { "keep" "x" "dup" "1" } `; string(got) != want {
+		t.Fatalf("DeletePath() document = %q, want %q", got, want)
+	}
+
+	if _, err := doc.DeletePath("root/missing", false); !errors.Is(err, ErrPathNotFound) {
+		t.Fatalf("DeletePath(missing) error = %v, want ErrPathNotFound", err)
+	}
+
+	if _, err := doc.DeletePath("root/keep", true); err != nil {
+		t.Fatalf("DeletePath(keep) returned error: %v", err)
+	}
+
+	if _, err := doc.DeletePath("root/dup", true); err != nil {
+		t.Fatalf("DeletePath(dup) returned error: %v", err)
+	}
+
+	if len(doc.Roots) != 0 {
+		t.Fatalf("roots after pruning = %d, want 0", len(doc.Roots))
+	}
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"Steam" { "Apps" { "440" { "name" "Team Fortress 2" } "a/b" "slash" "dup" "1" "dup" "2" } }`)
+
+	name := doc.Get("Steam/Apps/440/name")
+	if name == nil || *name.StringValue != "Team Fortress 2" {
+		t.Fatalf("Get(Steam/Apps/440/name) = %+v, want name node", name)
+	}
+
+	if got := doc.GetKeys("Steam", "Apps", "440", "name"); got != name {
+		t.Fatalf("GetKeys() = %p, want %p", got, name)
+	}
+
+	apps := doc.Get("Steam/Apps")
+	if got := apps.Get("440/name"); got != name {
+		t.Fatalf("Node.Get(440/name) = %p, want %p", got, name)
+	}
+
+	if got := apps.Get(""); got != apps {
+		t.Fatalf("Node.Get(\"\") = %p, want node itself", got)
+	}
+
+	if got := apps.GetKeys("a/b"); got == nil || *got.StringValue != "slash" {
+		t.Fatalf("GetKeys(a/b) = %+v, want literal key", got)
+	}
+
+	if got := apps.Get("dup[1]"); got == nil || *got.StringValue != "2" {
+		t.Fatalf("Get(dup[1]) = %+v, want second occurrence", got)
+	}
+
+	for _, path := range []string{"Steam/Apps/570", "Steam/Apps/440/name/x", "Steam[x]", ""} {
+		if got := doc.Get(path); got != nil {
+			t.Fatalf("Get(%q) = %+v, want nil", path, got)
+		}
+	}
+
+	var missing *Node
+	if missing.Get("a") != nil || name.Get("a") != nil || doc.GetKeys() != nil {
+		t.Fatal("Get on nil, leaf or empty keys returned a node")
+	}
+}
+
+func TestDocumentSetOptions(t *testing.T) {
+	t.Parallel()
+
+	newDoc := func() *Document {
+		return mustParseString(t, `"root" { "tag" "a" "other" "x" "tag" "b" }`)
+	}
+
+	values := func(doc *Document) []string {
+		var out []string
+		for _, child := range doc.Roots[0].Children {
+			out = append(out, child.Key+"="+*child.StringValue)
+		}
+
+		return out
+	}
+
+	cases := []struct {
+		name string
+		opts SetOptions
+		want string
+	}{
+		{name: "first", opts: SetOptions{}, want: "[tag=z other=x tag=b]"},
+		{name: "all", opts: SetOptions{Duplicates: SetAll}, want: "[tag=z other=x tag=z]"},
+		{name: "replace", opts: SetOptions{Duplicates: SetReplace}, want: "[tag=z other=x]"},
+		{name: "append", opts: SetOptions{Duplicates: SetAppend}, want: "[tag=a other=x tag=b tag=z]"},
+	}
+
+	for _, tc := range cases {
+		doc := newDoc()
+		node, err := doc.Set("root/tag", "z", tc.opts)
+		if err != nil || *node.StringValue != "z" {
+			t.Fatalf("Set(%s) = %v, %v", tc.name, node, err)
+		}
+
+		if got := fmt.Sprint(values(doc)); got != tc.want {
+			t.Fatalf("Set(%s) children = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+
+	doc := newDoc()
+	if _, err := doc.Set("root/missing/key", "v", SetOptions{MustExist: true}); !errors.Is(err, ErrPathNotFound) {
+		t.Fatalf("Set(MustExist, missing parent) error = %v, want ErrPathNotFound", err)
+	}
+
+	if _, err := doc.Set("root/new", "v", SetOptions{MustExist: true}); !errors.Is(err, ErrPathNotFound) {
+		t.Fatalf("Set(MustExist, missing leaf) error = %v, want ErrPathNotFound", err)
+	}
+
+	if len(doc.Roots[0].Children) != 3 {
+		t.Fatalf("Set(MustExist) changed children: %v", values(doc))
+	}
+
+	if _, err := doc.Set("root/tag", "v", SetOptions{Duplicates: SetAppend + 1}); !errors.Is(err, ErrInvalidSetDuplicates) {
+		t.Fatalf("Set(bad duplicates) error = %v, want ErrInvalidSetDuplicates", err)
+	}
+
+	if node, err := doc.Set("a/b/c", uint32(7)); err != nil || doc.Get("a/b/c") != node {
+		t.Fatalf("Set(create) = %v, %v", node, err)
+	}
+}
+
+func TestDocumentDelete(t *testing.T) {
+	t.Parallel()
+
+	input := `"root" { "tag" "a" "sub" { "tag" "b" } "tag" "c" } "root" { "tag" "d" }`
+
+	doc := mustParseString(t, input)
+	removed, err := doc.Delete("root/tag", false)
+	if err != nil || removed != 1 {
+		t.Fatalf("Delete(first) = %d, %v, want 1", removed, err)
+	}
+
+	if got := doc.Roots[0].All("tag"); len(got) != 1 || *got[0].StringValue != "c" {
+		t.Fatalf("Delete(first) left tags %v, want c", got)
+	}
+
+	doc = mustParseString(t, input)
+	if removed, err = doc.Delete("root/tag", true); err != nil || removed != 3 {
+		t.Fatalf("Delete(all) = %d, %v, want 3", removed, err)
+	}
+
+	if doc.Get("root/sub/tag") == nil || len(doc.Roots[1].Children) != 0 {
+		t.Fatal("Delete(all) removed the wrong nodes")
+	}
+
+	doc = mustParseString(t, input)
+	if removed, err = doc.Delete("**/tag", true); err != nil || removed != 4 {
+		t.Fatalf("Delete(**/tag) = %d, %v, want 4", removed, err)
+	}
+
+	if removed, err = doc.Delete("root/missing", true); err != nil || removed != 0 {
+		t.Fatalf("Delete(missing) = %d, %v, want 0", removed, err)
+	}
+
+	if _, err := doc.Delete("", true); !errors.Is(err, ErrInvalidPath) {
+		t.Fatalf("Delete(empty) error = %v, want ErrInvalidPath", err)
+	}
+}
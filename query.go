@@ -0,0 +1,337 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// querySegmentKind defines how a query segment matches keys.
+type querySegmentKind uint8
+
+const (
+	// queryLiteral matches one key exactly.
+	queryLiteral querySegmentKind = iota
+	// queryGlob matches keys with a path.Match pattern.
+	queryGlob
+	// queryAny matches any key on one level ("*").
+	queryAny
+	// queryDeep matches zero or more levels ("**").
+	queryDeep
+)
+
+// querySegment is one compiled segment of a wildcard path query.
+type querySegment struct {
+	key        string           // Literal key or path.Match pattern.
+	occurrence int              // Zero-based occurrence of a literal key among siblings, -1 for all.
+	kind       querySegmentKind // Matching rule.
+}
+
+// matches reports whether a key at the given sibling occurrence satisfies the segment.
+func (s querySegment) matches(key string, occurrence int) bool {
+	switch s.kind {
+	case queryAny:
+		return true
+	case queryGlob:
+		ok, _ := path.Match(s.key, key)
+		return ok
+	default:
+		return key == s.key && (s.occurrence < 0 || s.occurrence == occurrence)
+	}
+}
+
+// compileQuery parses a slash-delimited query path.
+// Segments are literal keys, literal keys with a "[n]" occurrence suffix,
+// "*" for any key, "**" for zero or more levels, or path.Match patterns.
+func compileQuery(query string) ([]querySegment, error) {
+	if query == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(query, PathSeparator)
+	segments := make([]querySegment, 0, len(parts))
+	for _, part := range parts {
+		segment, err := compileQuerySegment(part)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", err, query)
+		}
+
+		// Adjacent "**" segments match the same levels as one.
+		if segment.kind == queryDeep && len(segments) > 0 && segments[len(segments)-1].kind == queryDeep {
+			continue
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments, nil
+}
+
+// compileQuerySegment parses one query segment.
+func compileQuerySegment(part string) (querySegment, error) {
+	switch part {
+	case "*":
+		return querySegment{kind: queryAny, occurrence: -1}, nil
+	case "**":
+		return querySegment{kind: queryDeep, occurrence: -1}, nil
+	}
+
+	if key, occurrence, ok := splitOccurrence(part); ok {
+		return querySegment{key: key, occurrence: occurrence}, nil
+	}
+
+	if !strings.ContainsAny(part, `*?[\`) {
+		return querySegment{key: part, occurrence: -1}, nil
+	}
+
+	if _, err := path.Match(part, ""); err != nil {
+		return querySegment{}, fmt.Errorf("%w: bad pattern in segment %q", ErrInvalidPath, part)
+	}
+
+	return querySegment{key: part, occurrence: -1, kind: queryGlob}, nil
+}
+
+// splitOccurrence splits a literal "key[n]" segment; patterns are not split.
+func splitOccurrence(part string) (string, int, bool) {
+	open := strings.LastIndexByte(part, '[')
+	if open <= 0 || !strings.HasSuffix(part, "]") || strings.ContainsAny(part[:open], `*?[\`) {
+		return "", 0, false
+	}
+
+	digits := part[open+1 : len(part)-1]
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return "", 0, false
+	}
+
+	occurrence, err := strconv.Atoi(digits)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return part[:open], occurrence, true
+}
+
+// Selector is a compiled query path, safe for concurrent use.
+// Compile once with CompilePath to match many documents without reparsing the path.
+type Selector struct {
+	path     string         // Source query path.
+	segments []querySegment // Compiled segments.
+}
+
+// CompilePath parses a slash-delimited query path into a Selector.
+// Besides literal keys and "[n]" occurrence suffixes, a "*" segment matches
+// any key on one level, "**" matches zero or more levels, and segments with
+// path.Match metacharacters match keys as patterns.
+// A literal key without "[n]" matches all siblings with that key.
+func CompilePath(query string) (*Selector, error) {
+	segments, err := compileQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("%w: empty query path", ErrInvalidPath)
+	}
+
+	return &Selector{path: query, segments: segments}, nil
+}
+
+// MustCompilePath is like CompilePath but panics on a malformed path.
+// It simplifies initialization of global selectors.
+func MustCompilePath(query string) *Selector {
+	selector, err := CompilePath(query)
+	if err != nil {
+		panic(err)
+	}
+
+	return selector
+}
+
+// String returns the source query path.
+func (s *Selector) String() string {
+	return s.path
+}
+
+// Select returns all nodes matching the selector from document roots, in source order.
+func (s *Selector) Select(doc *Document) []*Node {
+	if doc == nil {
+		return nil
+	}
+
+	return selectSegments(doc.Roots, s.segments, 0)
+}
+
+// SelectNode returns all nodes below n matching the selector, in source order.
+func (s *Selector) SelectNode(n *Node) []*Node {
+	if n == nil || n.Kind != NodeObject {
+		return nil
+	}
+
+	return selectSegments(n.Children, s.segments, 0)
+}
+
+// First returns the first node in source order matching the selector from
+// document roots, or nil. It stops at the first match.
+func (s *Selector) First(doc *Document) *Node {
+	if doc == nil {
+		return nil
+	}
+
+	if matches := selectSegments(doc.Roots, s.segments, 1); len(matches) > 0 {
+		return matches[0]
+	}
+
+	return nil
+}
+
+// Match reports whether any node below n matches the selector.
+// It stops at the first match.
+func (s *Selector) Match(n *Node) bool {
+	if n == nil || n.Kind != NodeObject {
+		return false
+	}
+
+	return len(selectSegments(n.Children, s.segments, 1)) > 0
+}
+
+// Query returns all nodes matching a slash-delimited query path from document
+// roots, in source order, with the syntax of CompilePath,
+// so Query("libraryfolders/*/apps/*") lists every installed app.
+// Compile the path once with CompilePath when it is applied to many documents.
+func (d *Document) Query(query string) ([]*Node, error) {
+	if d == nil {
+		return nil, fmt.Errorf("%w: nil document", ErrInvalidNodeState)
+	}
+
+	selector, err := CompilePath(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return selector.Select(d), nil
+}
+
+// Query returns all nodes below n matching a slash-delimited query path,
+// in source order, with the syntax of CompilePath.
+// An empty query returns n itself.
+func (n *Node) Query(query string) ([]*Node, error) {
+	if n == nil {
+		return nil, fmt.Errorf("%w: nil node", ErrInvalidNodeState)
+	}
+
+	if query == "" {
+		return []*Node{n}, nil
+	}
+
+	selector, err := CompilePath(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return selector.SelectNode(n), nil
+}
+
+// queryFrame is one sibling list being matched by selectSegments.
+type queryFrame struct {
+	nodes  []*Node        // Sibling nodes.
+	seen   map[string]int // Occurrences of keys already visited, nil when unused.
+	states []int          // Indexes of segments the siblings may match.
+	index  int            // Next sibling to visit.
+}
+
+// selectSegments returns nodes in pre-order whose path below nodes matches segments,
+// stopping after limit matches when limit is positive.
+// Matching runs all alternatives of "**" at once, so every node is visited at most once
+// and reported at most once. Traversal uses an explicit stack.
+func selectSegments(nodes []*Node, segments []querySegment, limit int) []*Node {
+	counted := slices.ContainsFunc(segments, func(s querySegment) bool { return s.occurrence >= 0 })
+	newFrame := func(nodes []*Node, states []int) queryFrame {
+		frame := queryFrame{nodes: nodes, states: states}
+		if counted {
+			frame.seen = make(map[string]int)
+		}
+
+		return frame
+	}
+
+	var matches []*Node
+	stack := []queryFrame{newFrame(nodes, expandDeep(segments, []int{0}))}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.index >= len(top.nodes) {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		node := top.nodes[top.index]
+		top.index++
+		if node == nil {
+			continue
+		}
+
+		occurrence := 0
+		if top.seen != nil {
+			occurrence = top.seen[node.Key]
+			top.seen[node.Key]++
+		}
+
+		matched := false
+		var next []int
+		for _, state := range top.states {
+			segment := segments[state]
+			last := state == len(segments)-1
+			switch {
+			case segment.kind == queryDeep:
+				// "**" consumes this level and stays active below it.
+				next = appendState(next, state)
+				matched = matched || last
+			case segment.matches(node.Key, occurrence):
+				if last {
+					matched = true
+				} else {
+					next = appendState(next, state+1)
+				}
+			}
+		}
+
+		if matched {
+			matches = append(matches, node)
+			if limit > 0 && len(matches) >= limit {
+				break
+			}
+		}
+
+		if node.Kind == NodeObject && len(next) > 0 && len(node.Children) > 0 {
+			stack = append(stack, newFrame(node.Children, expandDeep(segments, next)))
+		}
+	}
+
+	return matches
+}
+
+// expandDeep adds the segments after each "**" state, as "**" may match zero levels.
+func expandDeep(segments []querySegment, states []int) []int {
+	for i := 0; i < len(states); i++ {
+		state := states[i]
+		if segments[state].kind == queryDeep && state+1 < len(segments) {
+			states = appendState(states, state+1)
+		}
+	}
+
+	return states
+}
+
+// appendState adds state to states unless already present.
+func appendState(states []int, state int) []int {
+	if slices.Contains(states, state) {
+		return states
+	}
+
+	return append(states, state)
+}
@@ -0,0 +1,114 @@
+package vdf
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDocumentQuery(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"libraryfolders" {
+		"0" { "path" "C:\\Steam" "apps" { "440" "100" "570" "200" } }
+		"1" { "path" "D:\\Games" "apps" { "730" "300" } }
+	}`)
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{query: "libraryfolders/*/apps/*", want: []string{"440", "570", "730"}},
+		{query: "libraryfolders/*/path", want: []string{"path", "path"}},
+		{query: "**/apps", want: []string{"apps", "apps"}},
+		{query: "libraryfolders/**", want: []string{"0", "path", "apps", "440", "570", "1", "path", "apps", "730"}},
+		{query: "**/5*", want: []string{"570"}},
+		{query: "libraryfolders/[01]/apps/7?0", want: []string{"730"}},
+		{query: "**/**/apps/**/730", want: []string{"730"}},
+		{query: "libraryfolders/*/missing", want: nil},
+	}
+
+	for _, tc := range cases {
+		nodes, err := doc.Query(tc.query)
+		if err != nil {
+			t.Fatalf("Query(%q) returned error: %v", tc.query, err)
+		}
+
+		var keys []string
+		for _, node := range nodes {
+			keys = append(keys, node.Key)
+		}
+
+		if len(keys) != len(tc.want) {
+			t.Fatalf("Query(%q) = %v, want %v", tc.query, keys, tc.want)
+		}
+
+		for i := range keys {
+			if keys[i] != tc.want[i] {
+				t.Fatalf("Query(%q) = %v, want %v", tc.query, keys, tc.want)
+			}
+		}
+	}
+
+	if _, err := doc.Query("libraryfolders/[/x"); !errors.Is(err, ErrInvalidPath) {
+		t.Fatalf("Query(bad pattern) error = %v, want ErrInvalidPath", err)
+	}
+}
+
+func TestNodeQueryDuplicates(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"root" { "item" "a" "item" "b" "other" "c" "item" "d" }`)
+	root := doc.Roots[0]
+
+	all, err := root.Query("item")
+	if err != nil || len(all) != 3 {
+		t.Fatalf("Query(item) = %d nodes, %v, want 3", len(all), err)
+	}
+
+	second, err := root.Query("item[1]")
+	if err != nil || len(second) != 1 || *second[0].StringValue != "b" {
+		t.Fatalf("Query(item[1]) = %v, %v, want b", second, err)
+	}
+
+	self, err := root.Query("")
+	if err != nil || len(self) != 1 || self[0] != root {
+		t.Fatalf("Query(\"\") = %v, %v, want node itself", self, err)
+	}
+}
+
+func TestCompilePath(t *testing.T) {
+	t.Parallel()
+
+	apps := MustCompilePath("**/apps/*")
+	if apps.String() != "**/apps/*" {
+		t.Fatalf("String() = %q", apps.String())
+	}
+
+	docs := []*Document{
+		mustParseString(t, `"libraryfolders" { "0" { "apps" { "440" "1" "570" "2" } } }`),
+		mustParseString(t, `"libraryfolders" { "0" { "apps" { } } }`),
+	}
+
+	if got := apps.Select(docs[0]); len(got) != 2 || got[0].Key != "440" || got[1].Key != "570" {
+		t.Fatalf("Select(doc 0) = %v, want 440 and 570", got)
+	}
+
+	if got := apps.First(docs[0]); got == nil || got.Key != "440" {
+		t.Fatalf("First(doc 0) = %v, want 440", got)
+	}
+
+	if !apps.Match(docs[0].Roots[0]) || apps.Match(docs[1].Roots[0]) {
+		t.Fatal("Match() did not report apps presence per document")
+	}
+
+	folder := docs[0].Get("libraryfolders/0")
+	if got := MustCompilePath("apps/440").SelectNode(folder); len(got) != 1 {
+		t.Fatalf("SelectNode(apps/440) = %v, want one node", got)
+	}
+
+	for _, bad := range []string{"", "a/[b"} {
+		if _, err := CompilePath(bad); !errors.Is(err, ErrInvalidPath) {
+			t.Fatalf("CompilePath(%q) error = %v, want ErrInvalidPath", bad, err)
+		}
+	}
+}
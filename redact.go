@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Redact replaces values of entries whose key matches any of patterns with replacement.
+// Patterns use path.Match syntax and are matched case-insensitively against keys.
+// When an object key matches, every leaf value in its subtree is replaced.
+// Numeric and raw leaves become string leaves holding replacement.
+func (d *Document) Redact(patterns []string, replacement string) error {
+	if d == nil {
+		return fmt.Errorf("%w: nil document", ErrInvalidNodeState)
+	}
+
+	matcher, err := newKeyMatcher(patterns)
+	if err != nil {
+		return err
+	}
+
+	type redactItem struct {
+		node    *Node
+		matched bool
+	}
+
+	stack := make([]redactItem, 0, len(d.Roots))
+	for i := len(d.Roots) - 1; i >= 0; i-- {
+		stack = append(stack, redactItem{node: d.Roots[i]})
+	}
+
+	for len(stack) > 0 {
+		item := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := item.node
+		if node == nil {
+			continue
+		}
+
+		matched := item.matched || matcher.match(node.Key)
+
+		switch node.Kind {
+		case NodeObject:
+			for i := len(node.Children) - 1; i >= 0; i-- {
+				stack = append(stack, redactItem{node: node.Children[i], matched: matched})
+			}
+
+		case NodeWideString:
+			if matched {
+				value := replacement
+				setLeaf(node, NodeWideString, &value, nil)
+			}
+
+		case NodeDirective:
+			// Directives reference files rather than hold values, so they are kept.
+
+		default:
+			if matched && node.Kind.IsLeaf() {
+				value := replacement
+				setLeaf(node, NodeString, &value, nil)
+			}
+		}
+	}
+
+	return nil
+}
+
+// RedactStream copies src to dst in the input format, replacing values of
+// entries whose key matches any of patterns with replacement.
+// Matching follows Document.Redact; input is streamed with memory bounded by nesting depth.
+func RedactStream(dst io.Writer, src io.Reader, patterns []string, replacement string, opts DecodeOptions) error {
+	matcher, err := newKeyMatcher(patterns)
+	if err != nil {
+		return err
+	}
+
+	stream, err := openStreamReader(src, opts)
+	if err != nil {
+		return err
+	}
+
+	enc := NewEncoder(dst, EncodeOptions{Format: stream.format})
+	matchDepth := 0 // Depth of the matched object being masked (0 means none).
+
+	for {
+		event, err := stream.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		switch event.Type {
+		case EventObjectStart:
+			if matchDepth == 0 && matcher.match(event.Key) {
+				matchDepth = event.Depth
+			}
+
+			err = enc.WriteEvent(event)
+
+		case EventObjectEnd:
+			if matchDepth == event.Depth {
+				matchDepth = 0
+			}
+
+			err = enc.EndObject()
+
+		case EventDirective:
+			err = enc.WriteEvent(event)
+
+		default:
+			if matchDepth > 0 || matcher.match(event.Key) {
+				err = enc.WriteEvent(redactedEvent(event, replacement))
+			} else {
+				err = enc.WriteEvent(event)
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return enc.Close()
+}
+
+// redactedEvent returns a string leaf event holding replacement in place of event.
+// Wide strings keep their kind, as in Document.Redact.
+func redactedEvent(event Event, replacement string) Event {
+	kind := EventString
+	if event.Type == EventWideString {
+		kind = EventWideString
+	}
+
+	return Event{Type: kind, Key: event.Key, Condition: event.Condition, StringValue: &replacement}
+}
+
+// keyMatcher matches keys against case-insensitive glob patterns.
+type keyMatcher struct {
+	patterns []string // Lowercased path.Match patterns.
+}
+
+// newKeyMatcher validates and normalizes glob patterns.
+func newKeyMatcher(patterns []string) (*keyMatcher, error) {
+	m := &keyMatcher{patterns: make([]string, 0, len(patterns))}
+	for _, pattern := range patterns {
+		lower := strings.ToLower(pattern)
+		if _, err := path.Match(lower, ""); err != nil {
+			return nil, fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+		}
+
+		m.patterns = append(m.patterns, lower)
+	}
+
+	return m, nil
+}
+
+// match reports whether key matches any pattern.
+func (m *keyMatcher) match(key string) bool {
+	if len(m.patterns) == 0 {
+		return false
+	}
+
+	lower := strings.ToLower(key)
+	for _, pattern := range m.patterns {
+		// Patterns are validated up front, so match errors cannot occur here.
+		if ok, _ := path.Match(pattern, lower); ok {
+			return true
+		}
+	}
+
+	return false
+}
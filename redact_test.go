@@ -0,0 +1,106 @@
+package vdf
+
+import (
+	"bytes"
+	"errors"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestDocumentRedact(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"cfg" { "name" "srv" "AuthToken" "secret" "Credentials" { "user" "u" "pass" "p" } }`)
+	doc.Roots[0].Add(NewUint32Node("pin", 1234))
+
+	if err := doc.Redact([]string{"*token", "credentials", "PIN"}, "***"); err != nil {
+		t.Fatalf("Redact() returned error: %v", err)
+	}
+
+	got, err := AppendText(nil, doc, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	want := `"cfg" { "name" "srv" "AuthToken" "***" "Credentials" { "user" "***" "pass" "***" } "pin" "***" } `
+	if string(got) != want {
+		t.Fatalf("redacted document = %q, want %q", got, want)
+	}
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("Validate() returned error: %v", err)
+	}
+
+	if err := doc.Redact([]string{"["}, "x"); !errors.Is(err, path.ErrBadPattern) {
+		t.Fatalf("Redact(bad pattern) error = %v, want path.ErrBadPattern", err)
+	}
+}
+
+func TestRedactStream(t *testing.T) {
+	t.Parallel()
+
+	input := `"cfg" { "name" "srv" "AuthToken" "secret" "Credentials" { "user" "u" } }`
+
+	var out bytes.Buffer
+	err := RedactStream(&out, strings.NewReader(input), []string{"*token", "credentials"}, "***", DecodeOptions{Format: FormatText})
+	if err != nil {
+		t.Fatalf("RedactStream() returned error: %v", err)
+	}
+
+	got, err := AppendText(nil, mustParseString(t, out.String()), EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	want := `"cfg" { "name" "srv" "AuthToken" "***" "Credentials" { "user" "***" } } `
+	if string(got) != want {
+		t.Fatalf("redacted stream = %q, want %q", got, want)
+	}
+
+	input = `"sub" [$OSX] { "pass" "x" [$WIN32] "keep" "y" }`
+
+	out.Reset()
+	if err := RedactStream(&out, strings.NewReader(input), []string{"pass"}, "***", DecodeOptions{Format: FormatText}); err != nil {
+		t.Fatalf("RedactStream(conditional) returned error: %v", err)
+	}
+
+	got, err = AppendText(nil, mustParseString(t, out.String()), EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	want = `"sub" [$OSX] { "pass" "***" [$WIN32] "keep" "y" } `
+	if string(got) != want {
+		t.Fatalf("redacted conditional = %q, want %q", got, want)
+	}
+
+	doc := NewDocument()
+	root := NewObjectNode("cfg")
+	root.Add(NewWideStringNode("token", "secret"))
+	root.Add(NewUint32Node("pin", 1234))
+	doc.AddRoot(root)
+
+	bin, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	out.Reset()
+	if err := RedactStream(&out, bytes.NewReader(bin), []string{"token", "pin"}, "***", DecodeOptions{}); err != nil {
+		t.Fatalf("RedactStream(binary) returned error: %v", err)
+	}
+
+	redacted, err := ParseBytes(out.Bytes(), DecodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("ParseBytes(redacted) returned error: %v", err)
+	}
+
+	if err := doc.Redact([]string{"token", "pin"}, "***"); err != nil {
+		t.Fatalf("Redact() returned error: %v", err)
+	}
+
+	if !redacted.Equal(doc) {
+		t.Fatalf("RedactStream(binary) = %+v, want %+v", redacted.Roots[0].Children, doc.Roots[0].Children)
+	}
+}
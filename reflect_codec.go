@@ -0,0 +1,629 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// structTagName is the struct tag key used to map fields to VDF keys.
+const structTagName = "vdf"
+
+// structField describes one exported struct field mapped to a VDF key.
+type structField struct {
+	name      string // VDF key for the field.
+	index     []int  // Field index path for reflect.Value.FieldByIndex.
+	omitEmpty bool   // Whether empty values are skipped on encode.
+}
+
+// Marshaler is implemented by types that build their own VDF representation.
+// The returned node's key is replaced by the key of the field or map entry.
+type Marshaler interface {
+	MarshalVDF() (*Node, error)
+}
+
+// Unmarshaler is implemented by types that decode their own VDF representation.
+// UnmarshalVDF receives the matched node, which may be an object or a leaf.
+type Unmarshaler interface {
+	UnmarshalVDF(node *Node) error
+}
+
+// Reflected interface types for codec hook lookups.
+var (
+	marshalerType   = reflect.TypeFor[Marshaler]()
+	unmarshalerType = reflect.TypeFor[Unmarshaler]()
+)
+
+// structFieldCache stores resolved field lists per struct type.
+var structFieldCache sync.Map // map[reflect.Type][]structField
+
+// cachedStructFields returns mapped fields of a struct type in declaration order.
+// Fields use their tag name or Go name; a "-" tag skips the field and "-,"
+// names it "-". The "omitempty" option skips empty values on encode.
+// Untagged embedded structs contribute their fields to the outer struct.
+func cachedStructFields(t reflect.Type) []structField {
+	if cached, ok := structFieldCache.Load(t); ok {
+		return cached.([]structField)
+	}
+
+	fields := collectStructFields(t, nil)
+	actual, _ := structFieldCache.LoadOrStore(t, fields)
+	return actual.([]structField)
+}
+
+// collectStructFields resolves mapped fields of t with index paths prefixed by parent.
+func collectStructFields(t reflect.Type, parent []int) []structField {
+	fields := make([]structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, hasTag := field.Tag.Lookup(structTagName)
+		if tag == "-" {
+			continue
+		}
+
+		index := append(slices.Clone(parent), i)
+		if field.Anonymous && !hasTag && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, collectStructFields(field.Type, index)...)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, structField{
+			name:      name,
+			index:     index,
+			omitEmpty: hasTagOption(options, "omitempty"),
+		})
+	}
+
+	return fields
+}
+
+// hasTagOption reports whether a comma-separated tag option list contains option.
+func hasTagOption(options, option string) bool {
+	for options != "" {
+		var current string
+		current, options, _ = strings.Cut(options, ",")
+		if current == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isEmptyValue reports whether v is empty under omitempty rules:
+// false, zero numbers, empty strings, and empty slices, arrays, and maps.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// fieldChild returns the first child matching a struct field key,
+// preferring an exact match over a case-insensitive one.
+func fieldChild(node *Node, name string) *Node {
+	if child := node.First(name); child != nil {
+		return child
+	}
+
+	for _, child := range node.Children {
+		if child != nil && strings.EqualFold(child.Key, name) {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// fieldChildren returns all children matching a struct field key in source order,
+// falling back to case-insensitive matches when no key matches exactly.
+func fieldChildren(node *Node, name string) []*Node {
+	if children := node.All(name); len(children) != 0 {
+		return children
+	}
+
+	children := make([]*Node, 0)
+	for _, child := range node.Children {
+		if child != nil && strings.EqualFold(child.Key, name) {
+			children = append(children, child)
+		}
+	}
+
+	return children
+}
+
+// decodeNodeValue stores node contents into a settable Go value.
+func decodeNodeValue(node *Node, v reflect.Value) error {
+	if v.Kind() != reflect.Pointer && v.CanAddr() && v.Addr().Type().Implements(unmarshalerType) {
+		return v.Addr().Interface().(Unmarshaler).UnmarshalVDF(node)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return decodeNodeValue(node, v.Elem())
+
+	case reflect.Struct:
+		if node.Kind != NodeObject {
+			return fmt.Errorf("%w: key %q is not an object for %s", ErrValueConversion, node.Key, v.Type())
+		}
+
+		for _, field := range cachedStructFields(v.Type()) {
+			fv := v.FieldByIndex(field.index)
+			if isRepeatedValue(fv) {
+				children := fieldChildren(node, field.name)
+				if len(children) == 0 {
+					continue
+				}
+
+				if err := decodeRepeatedValue(children, fv); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			child := fieldChild(node, field.name)
+			if child == nil {
+				continue
+			}
+
+			if err := decodeNodeValue(child, fv); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case reflect.Map:
+		if node.Kind != NodeObject {
+			return fmt.Errorf("%w: key %q is not an object for %s", ErrValueConversion, node.Key, v.Type())
+		}
+
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("%w: %s", ErrUnsupportedGoType, v.Type())
+		}
+
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(v.Type(), len(node.Children)))
+		}
+
+		for _, child := range node.Children {
+			if child == nil {
+				continue
+			}
+
+			key := reflect.ValueOf(child.Key).Convert(v.Type().Key())
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if elem.Kind() == reflect.Slice && isRepeatedValue(elem) {
+				// Repeated keys accumulate into the slice stored under the key.
+				if current := v.MapIndex(key); current.IsValid() {
+					elem.Set(current)
+				}
+
+				item := reflect.New(elem.Type().Elem()).Elem()
+				if err := decodeNodeValue(child, item); err != nil {
+					return err
+				}
+
+				v.SetMapIndex(key, reflect.Append(elem, item))
+				continue
+			}
+
+			if err := decodeNodeValue(child, elem); err != nil {
+				return err
+			}
+
+			v.SetMapIndex(key, elem)
+		}
+
+		return nil
+
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return fmt.Errorf("%w: %s", ErrUnsupportedGoType, v.Type())
+		}
+
+		value := nodeToLossyValue(node)
+		if value == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+
+		v.Set(reflect.ValueOf(value))
+		return nil
+
+	default:
+		return decodeLeafValue(node, v)
+	}
+}
+
+// decodeRepeatedValue stores repeated children into a slice or array in source order.
+// Arrays keep at most their length of children and zero the remaining elements.
+func decodeRepeatedValue(children []*Node, v reflect.Value) error {
+	if v.Kind() == reflect.Slice {
+		v.Set(reflect.MakeSlice(v.Type(), len(children), len(children)))
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if i >= len(children) {
+			v.Index(i).SetZero()
+			continue
+		}
+
+		if err := decodeNodeValue(children[i], v.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeLeafValue converts a scalar node into a scalar Go value.
+func decodeLeafValue(node *Node, v reflect.Value) error {
+	if node.Kind == NodeObject {
+		return fmt.Errorf("%w: key %q is an object for %s", ErrValueConversion, node.Key, v.Type())
+	}
+
+	text, err := textValueForNode(node)
+	if err != nil {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return fmt.Errorf("%w: key %q value %q for %s", ErrValueConversion, node.Key, text, v.Type())
+		}
+		v.SetBool(parsed)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: key %q value %q for %s", ErrValueConversion, node.Key, text, v.Type())
+		}
+		v.SetInt(parsed)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		parsed, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: key %q value %q for %s", ErrValueConversion, node.Key, text, v.Type())
+		}
+		v.SetUint(parsed)
+
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w: key %q value %q for %s", ErrValueConversion, node.Key, text, v.Type())
+		}
+		v.SetFloat(parsed)
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("%w: %s", ErrUnsupportedGoType, v.Type())
+		}
+
+		v.SetBytes([]byte(text))
+
+	case reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("%w: %s", ErrUnsupportedGoType, v.Type())
+		}
+
+		if len(text) != v.Len() {
+			return fmt.Errorf("%w: key %q value length %d for %s", ErrValueConversion, node.Key, len(text), v.Type())
+		}
+
+		reflect.Copy(v, reflect.ValueOf([]byte(text)))
+
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedGoType, v.Type())
+	}
+
+	return nil
+}
+
+// encodeNodeValue writes a Go value into node, updating it in place.
+// Existing object children that are not mapped by the value are preserved in order,
+// and existing uint32 leaves keep their kind when the new value still fits.
+func encodeNodeValue(node *Node, v reflect.Value) error {
+	if marshaler, ok := asMarshaler(v); ok {
+		out, err := marshaler.MarshalVDF()
+		if err != nil {
+			return err
+		}
+
+		if out == nil {
+			return fmt.Errorf("%w: MarshalVDF of %s returned nil node for key %q", ErrValueConversion, v.Type(), node.Key)
+		}
+
+		replaceNodeValue(node, out)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return fmt.Errorf("%w: nil %s for key %q", ErrValueConversion, v.Type(), node.Key)
+		}
+
+		return encodeNodeValue(node, v.Elem())
+
+	case reflect.Struct:
+		makeObjectNode(node)
+		for _, field := range cachedStructFields(v.Type()) {
+			fv := v.FieldByIndex(field.index)
+			if (fv.Kind() == reflect.Pointer || fv.Kind() == reflect.Interface) && fv.IsNil() {
+				continue
+			}
+
+			if field.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+
+			if isRepeatedValue(fv) {
+				if fv.Kind() == reflect.Slice && fv.IsNil() {
+					continue
+				}
+
+				if err := encodeRepeatedValue(node, field.name, fv); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if err := encodeChildValue(node, fieldChild(node, field.name), field.name, fv); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("%w: %s", ErrUnsupportedGoType, v.Type())
+		}
+
+		makeObjectNode(node)
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(a.String(), b.String())
+		})
+
+		for _, key := range keys {
+			value := v.MapIndex(key)
+			if isRepeatedValue(value) {
+				if err := encodeRepeatedValue(node, key.String(), value); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if err := encodeChildValue(node, node.First(key.String()), key.String(), value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	default:
+		return encodeLeafValue(node, v)
+	}
+}
+
+// encodeChildValue updates the existing child or appends a new one with key.
+func encodeChildValue(parent, child *Node, key string, v reflect.Value) error {
+	if child == nil {
+		child = &Node{Key: key}
+		if err := encodeNodeValue(child, v); err != nil {
+			return err
+		}
+
+		parent.Add(child)
+		return nil
+	}
+
+	return encodeNodeValue(child, v)
+}
+
+// isRepeatedValue reports whether v maps to repeated keys rather than one node.
+// Byte slices and types with their own Marshaler or Unmarshaler map to one node.
+func isRepeatedValue(v reflect.Value) bool {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array || v.Type().Elem().Kind() == reflect.Uint8 {
+		return false
+	}
+
+	ptr := reflect.PointerTo(v.Type())
+	return !ptr.Implements(marshalerType) && !ptr.Implements(unmarshalerType)
+}
+
+// asMarshaler returns the Marshaler implemented by v or its address.
+func asMarshaler(v reflect.Value) (Marshaler, bool) {
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil, false
+	}
+
+	if v.Type().Implements(marshalerType) {
+		return v.Interface().(Marshaler), true
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(marshalerType) {
+		return v.Addr().Interface().(Marshaler), true
+	}
+
+	return nil, false
+}
+
+// replaceNodeValue copies the payload of src into node, keeping the node key and identity.
+func replaceNodeValue(node, src *Node) {
+	node.Kind = src.Kind
+	node.StringValue = src.StringValue
+	node.Uint32Value = src.Uint32Value
+	node.Float32Value = src.Float32Value
+	node.ColorValue = src.ColorValue
+	node.Uint64Value = src.Uint64Value
+	node.Int64Value = src.Int64Value
+	node.Children = src.Children
+	node.RawType = src.RawType
+	node.RawValue = src.RawValue
+}
+
+// encodeRepeatedValue stores slice or array elements as repeated children with key.
+// Existing occurrences are updated in place, missing ones are appended,
+// and surplus occurrences are removed.
+func encodeRepeatedValue(parent *Node, key string, v reflect.Value) error {
+	existing := parent.All(key)
+	for i := 0; i < v.Len(); i++ {
+		var child *Node
+		if i < len(existing) {
+			child = existing[i]
+		}
+
+		if err := encodeChildValue(parent, child, key, v.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	if len(existing) > v.Len() {
+		surplus := existing[v.Len():]
+		parent.Children = slices.DeleteFunc(parent.Children, func(child *Node) bool {
+			return slices.Contains(surplus, child)
+		})
+	}
+
+	return nil
+}
+
+// encodeLeafValue stores a scalar Go value as a leaf node.
+// Unsigned values that fit uint32 become NodeUint32 for new nodes and
+// for nodes that already hold uint32; larger ones become NodeUint64, and
+// NodeUint64 and NodeInt64 leaves keep their kind. Float32 values likewise become NodeFloat32,
+// and floats written into NodeFloat32 leaves keep that kind.
+// Everything else is stored as text; NodeWideString leaves stay wide.
+func encodeLeafValue(node *Node, v reflect.Value) error {
+	var text string
+	switch v.Kind() {
+	case reflect.String:
+		text = v.String()
+
+	case reflect.Bool:
+		text = "0"
+		if v.Bool() {
+			text = "1"
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if node.Kind == NodeInt64 {
+			replaceNodeValue(node, NewInt64Node(node.Key, v.Int()))
+			return nil
+		}
+
+		text = strconv.FormatInt(v.Int(), 10)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value := v.Uint()
+		if node.Kind == NodeUint64 || node.Kind == 0 && value > math.MaxUint32 {
+			replaceNodeValue(node, NewUint64Node(node.Key, value))
+			return nil
+		}
+
+		if value <= math.MaxUint32 && (node.Kind == 0 || node.Kind == NodeUint32 || node.Kind == NodePointer) {
+			kind := NodeUint32
+			if node.Kind == NodePointer {
+				kind = NodePointer
+			}
+
+			number := uint32(value)
+			setLeaf(node, kind, nil, &number)
+			return nil
+		}
+
+		text = strconv.FormatUint(value, 10)
+
+	case reflect.Float32, reflect.Float64:
+		if v.Kind() == reflect.Float32 && node.Kind == 0 || node.Kind == NodeFloat32 {
+			replaceNodeValue(node, NewFloat32Node(node.Key, float32(v.Float())))
+			return nil
+		}
+
+		text = strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("%w: %s", ErrUnsupportedGoType, v.Type())
+		}
+
+		if v.Kind() == reflect.Array {
+			// Bytes requires an addressable array, so copy it out instead.
+			buf := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(buf), v)
+			text = string(buf)
+			break
+		}
+
+		text = string(v.Bytes())
+
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedGoType, v.Type())
+	}
+
+	kind := NodeString
+	if node.Kind == NodeWideString {
+		kind = NodeWideString
+	}
+
+	setLeaf(node, kind, &text, nil)
+	return nil
+}
+
+// setLeaf replaces node payload with a scalar value.
+func setLeaf(node *Node, kind NodeKind, str *string, number *uint32) {
+	replaceNodeValue(node, &Node{Kind: kind, StringValue: str, Uint32Value: number})
+}
+
+// makeObjectNode turns node into an object, keeping children when it already is one.
+func makeObjectNode(node *Node) {
+	if node.Kind == NodeObject {
+		return
+	}
+
+	replaceNodeValue(node, &Node{Kind: NodeObject, Children: make([]*Node, 0, 4)})
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import "fmt"
+
+// CheckRoundTrip decodes data, re-encodes it in the detected format, decodes the
+// result again and compares both documents including order, kinds and values.
+// A divergence is reported as ErrRoundTripMismatch with the first differing path.
+func CheckRoundTrip(data []byte, opts DecodeOptions) error {
+	first, err := ParseBytes(data, opts)
+	if err != nil {
+		return fmt.Errorf("decode input: %w", err)
+	}
+
+	var encoded []byte
+	if first.Format == FormatBinary {
+		encoded, err = AppendBinary(nil, first, EncodeOptions{Format: FormatBinary, Checksum: opts.VerifyChecksum})
+	} else {
+		encoded, err = AppendText(nil, first, EncodeOptions{Format: FormatText})
+	}
+
+	if err != nil {
+		return fmt.Errorf("re-encode: %w", err)
+	}
+
+	opts.Format = first.Format
+	second, err := ParseBytes(encoded, opts)
+	if err != nil {
+		return fmt.Errorf("decode re-encoded output: %w", err)
+	}
+
+	if path, diverged := firstDivergence(first.Roots, second.Roots, ""); diverged {
+		return fmt.Errorf("%w at %q", ErrRoundTripMismatch, path)
+	}
+
+	return nil
+}
+
+// firstDivergence compares two sibling lists in order and returns the first differing path.
+func firstDivergence(left, right []*Node, prefix string) (string, bool) {
+	seen := make(map[string]int, len(left))
+
+	for i := 0; i < max(len(left), len(right)); i++ {
+		if i >= len(left) || i >= len(right) {
+			extra := right
+			if i < len(left) {
+				extra = left
+			}
+
+			return joinOccurrencePath(prefix, nodeKey(extra[i]), seen[nodeKey(extra[i])]), true
+		}
+
+		a, b := left[i], right[i]
+		if a == nil || b == nil {
+			if a != b {
+				return joinOccurrencePath(prefix, nodeKey(a)+nodeKey(b), 0), true
+			}
+
+			continue
+		}
+
+		path := joinOccurrencePath(prefix, a.Key, seen[a.Key])
+		seen[a.Key]++
+
+		if a.Key != b.Key || a.Kind != b.Kind {
+			return path, true
+		}
+
+		if a.Kind == NodeObject {
+			if childPath, diverged := firstDivergence(a.Children, b.Children, path); diverged {
+				return childPath, true
+			}
+
+			continue
+		}
+
+		if !leafEqual(a, b) {
+			return path, true
+		}
+	}
+
+	return "", false
+}
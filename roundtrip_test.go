@@ -0,0 +1,47 @@
+package vdf
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, fixture := range []string{"valid.vdf", "consolesample.vdf", "duplicates.vdf", "empty.vdf"} {
+		if err := CheckRoundTrip(readFixtureBytes(t, fixture), DecodeOptions{}); err != nil {
+			t.Fatalf("CheckRoundTrip(%q) returned error: %v", fixture, err)
+		}
+	}
+
+	bin, err := AppendBinary(nil, mustParseString(t, readFixtureString(t, "consolesample.vdf")), EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	if err := CheckRoundTrip(bin, DecodeOptions{}); err != nil {
+		t.Fatalf("CheckRoundTrip(binary) returned error: %v", err)
+	}
+
+	if err := CheckRoundTrip(readFixtureBytes(t, "corrupted.vdf"), DecodeOptions{}); !errors.Is(err, ErrUnexpectedEOFInQuotedString) {
+		t.Fatalf("CheckRoundTrip(corrupted) error = %v, want ErrUnexpectedEOFInQuotedString", err)
+	}
+}
+
+func TestFirstDivergence(t *testing.T) {
+	t.Parallel()
+
+	a := mustParseString(t, `"r" { "k" "1" "k" { "x" "1" } }`)
+	b := mustParseString(t, `"r" { "k" "1" "k" { "x" "2" } }`)
+
+	path, diverged := firstDivergence(a.Roots, b.Roots, "")
+	if !diverged || path != "r/k[1]/x" {
+		t.Fatalf("firstDivergence() = %q, %v, want r/k[1]/x", path, diverged)
+	}
+
+	c := mustParseString(t, `"r" { "k" "1" }`)
+	path, diverged = firstDivergence(a.Roots, c.Roots, "")
+	if !diverged || path != "r/k[1]" {
+		t.Fatalf("firstDivergence(shorter) = %q, %v, want r/k[1]", path, diverged)
+	}
+}
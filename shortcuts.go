@@ -0,0 +1,261 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// shortcutsRootKey is the root key of Steam shortcuts.vdf.
+const shortcutsRootKey = "shortcuts"
+
+// Shortcut is one non-Steam game entry of Steam shortcuts.vdf.
+type Shortcut struct {
+	// Extra holds entry keys without a dedicated field, kept in source order for write-back.
+	Extra []*Node `json:"extra,omitempty" yaml:"extra,omitempty"`
+	// Tags lists the collection tags of the entry in order.
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// AppName is the display name.
+	AppName string `json:"app_name" yaml:"app_name"`
+	// Exe is the quoted executable path as Steam stores it.
+	Exe string `json:"exe" yaml:"exe"`
+	// StartDir is the quoted working directory.
+	StartDir string `json:"start_dir" yaml:"start_dir"`
+	// Icon is the icon path, stored under the "icon" key.
+	Icon string `json:"icon,omitempty" yaml:"icon,omitempty"`
+	// ShortcutPath is the desktop shortcut the entry was created from.
+	ShortcutPath string `json:"shortcut_path,omitempty" yaml:"shortcut_path,omitempty"`
+	// LaunchOptions are the extra command line arguments.
+	LaunchOptions string `json:"launch_options,omitempty" yaml:"launch_options,omitempty"`
+	// DevkitGameID is the devkit game id.
+	DevkitGameID string `json:"devkit_game_id,omitempty" yaml:"devkit_game_id,omitempty"`
+	// FlatpakAppID is the Flatpak application id on Linux.
+	FlatpakAppID string `json:"flatpak_app_id,omitempty" yaml:"flatpak_app_id,omitempty"`
+	// AppID is the shortcut app id; zero means derive it from Exe and AppName on write.
+	AppID uint32 `json:"app_id" yaml:"app_id"`
+	// LastPlayTime is the Unix time the shortcut was last launched.
+	LastPlayTime uint32 `json:"last_play_time,omitempty" yaml:"last_play_time,omitempty"`
+	// DevkitOverrideAppID is the devkit override app id.
+	DevkitOverrideAppID uint32 `json:"devkit_override_app_id,omitempty" yaml:"devkit_override_app_id,omitempty"`
+	// IsHidden hides the entry from the library.
+	IsHidden bool `json:"is_hidden,omitempty" yaml:"is_hidden,omitempty"`
+	// AllowDesktopConfig enables the desktop controller configuration.
+	AllowDesktopConfig bool `json:"allow_desktop_config,omitempty" yaml:"allow_desktop_config,omitempty"`
+	// AllowOverlay enables the Steam overlay.
+	AllowOverlay bool `json:"allow_overlay,omitempty" yaml:"allow_overlay,omitempty"`
+	// OpenVR marks the entry as a VR application.
+	OpenVR bool `json:"open_vr,omitempty" yaml:"open_vr,omitempty"`
+	// Devkit marks the entry as a devkit game.
+	Devkit bool `json:"devkit,omitempty" yaml:"devkit,omitempty"`
+}
+
+// ShortcutAppID returns the app id Steam derives for a non-Steam shortcut:
+// the CRC32 of exe followed by appName with the high bit set.
+// It names grid artwork files and fills the "appid" key of new entries.
+func ShortcutAppID(exe, appName string) uint32 {
+	return crc32.ChecksumIEEE([]byte(exe+appName)) | 0x80000000
+}
+
+// ShortcutGameID returns the 64-bit game id of a non-Steam shortcut, used by
+// steam://rungameid/ links and legacy grid artwork names.
+func ShortcutGameID(exe, appName string) uint64 {
+	return shortcutGameID(ShortcutAppID(exe, appName))
+}
+
+// GameID returns the 64-bit game id of the shortcut, deriving the app id when AppID is zero.
+func (s *Shortcut) GameID() uint64 {
+	return shortcutGameID(s.effectiveAppID())
+}
+
+// ReadShortcuts decodes a shortcuts.vdf stream into its entries.
+func ReadShortcuts(r io.Reader) ([]Shortcut, error) {
+	doc, err := NewDecoder(r, DecodeOptions{Format: FormatBinary}).DecodeDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	return ShortcutsFromDocument(doc)
+}
+
+// WriteShortcuts encodes entries as a binary shortcuts.vdf stream.
+func WriteShortcuts(w io.Writer, shortcuts []Shortcut) error {
+	return NewEncoder(w, EncodeOptions{Format: FormatBinary}).EncodeDocument(ShortcutsDocument(shortcuts))
+}
+
+// ShortcutsFromDocument reads the entries of a decoded shortcuts.vdf document in order.
+// Keys are matched case-insensitively, as older Steam clients wrote "appname" and "exe".
+func ShortcutsFromDocument(doc *Document) ([]Shortcut, error) {
+	var root *Node
+	if doc != nil {
+		root = fieldChild(documentObject(doc), shortcutsRootKey)
+	}
+
+	if root == nil || root.Kind != NodeObject {
+		return nil, fmt.Errorf("%w: missing %q object", ErrValueConversion, shortcutsRootKey)
+	}
+
+	shortcuts := make([]Shortcut, 0, len(root.Children))
+	for _, entry := range root.Children {
+		if entry == nil {
+			continue
+		}
+
+		if entry.Kind != NodeObject {
+			return nil, fmt.Errorf("%w: shortcut %q is not an object", ErrValueConversion, entry.Key)
+		}
+
+		shortcut, err := shortcutFromNode(entry)
+		if err != nil {
+			return nil, fmt.Errorf("shortcut %q: %w", entry.Key, err)
+		}
+
+		shortcuts = append(shortcuts, shortcut)
+	}
+
+	return shortcuts, nil
+}
+
+// ShortcutsDocument builds a shortcuts.vdf document from entries, keyed by index.
+// Keys are written in the order the Steam client uses, followed by Extra.
+func ShortcutsDocument(shortcuts []Shortcut) *Document {
+	root := NewObjectNode(shortcutsRootKey)
+	for i := range shortcuts {
+		root.Add(shortcuts[i].node(strconv.Itoa(i)))
+	}
+
+	doc := NewDocumentWithFormat(FormatBinary)
+	doc.AddRoot(root)
+	return doc
+}
+
+// node builds the entry object stored under key.
+func (s *Shortcut) node(key string) *Node {
+	entry := NewObjectNode(key)
+	entry.Add(NewUint32Node("appid", s.effectiveAppID()))
+	entry.Add(NewStringNode("AppName", s.AppName))
+	entry.Add(NewStringNode("Exe", s.Exe))
+	entry.Add(NewStringNode("StartDir", s.StartDir))
+	entry.Add(NewStringNode("icon", s.Icon))
+	entry.Add(NewStringNode("ShortcutPath", s.ShortcutPath))
+	entry.Add(NewStringNode("LaunchOptions", s.LaunchOptions))
+	entry.Add(NewUint32Node("IsHidden", steamFlag(s.IsHidden)))
+	entry.Add(NewUint32Node("AllowDesktopConfig", steamFlag(s.AllowDesktopConfig)))
+	entry.Add(NewUint32Node("AllowOverlay", steamFlag(s.AllowOverlay)))
+	entry.Add(NewUint32Node("OpenVR", steamFlag(s.OpenVR)))
+	entry.Add(NewUint32Node("Devkit", steamFlag(s.Devkit)))
+	entry.Add(NewStringNode("DevkitGameID", s.DevkitGameID))
+	entry.Add(NewUint32Node("DevkitOverrideAppID", s.DevkitOverrideAppID))
+	entry.Add(NewUint32Node("LastPlayTime", s.LastPlayTime))
+	entry.Add(NewStringNode("FlatpakAppID", s.FlatpakAppID))
+
+	tags := NewObjectNode("tags")
+	for i, tag := range s.Tags {
+		tags.Add(NewStringNode(strconv.Itoa(i), tag))
+	}
+
+	entry.Add(tags)
+	for _, extra := range s.Extra {
+		entry.Add(cloneNode(extra))
+	}
+
+	return entry
+}
+
+// effectiveAppID returns AppID, or the derived app id when it is zero.
+func (s *Shortcut) effectiveAppID() uint32 {
+	if s.AppID != 0 {
+		return s.AppID
+	}
+
+	return ShortcutAppID(s.Exe, s.AppName)
+}
+
+// shortcutFromNode maps one entry object to a Shortcut.
+func shortcutFromNode(entry *Node) (Shortcut, error) {
+	var shortcut Shortcut
+	for _, child := range entry.Children {
+		if child == nil {
+			continue
+		}
+
+		var err error
+		switch strings.ToLower(child.Key) {
+		case "appid":
+			shortcut.AppID, err = steamUint32(child)
+		case "appname":
+			shortcut.AppName, err = steamText(child)
+		case "exe":
+			shortcut.Exe, err = steamText(child)
+		case "startdir":
+			shortcut.StartDir, err = steamText(child)
+		case "icon":
+			shortcut.Icon, err = steamText(child)
+		case "shortcutpath":
+			shortcut.ShortcutPath, err = steamText(child)
+		case "launchoptions":
+			shortcut.LaunchOptions, err = steamText(child)
+		case "devkitgameid":
+			shortcut.DevkitGameID, err = steamText(child)
+		case "flatpakappid":
+			shortcut.FlatpakAppID, err = steamText(child)
+		case "lastplaytime":
+			shortcut.LastPlayTime, err = steamUint32(child)
+		case "devkitoverrideappid":
+			shortcut.DevkitOverrideAppID, err = steamUint32(child)
+		case "ishidden":
+			shortcut.IsHidden, err = steamBool(child)
+		case "allowdesktopconfig":
+			shortcut.AllowDesktopConfig, err = steamBool(child)
+		case "allowoverlay":
+			shortcut.AllowOverlay, err = steamBool(child)
+		case "openvr":
+			shortcut.OpenVR, err = steamBool(child)
+		case "devkit":
+			shortcut.Devkit, err = steamBool(child)
+		case "tags":
+			shortcut.Tags, err = shortcutTags(child)
+		default:
+			shortcut.Extra = append(shortcut.Extra, cloneNode(child))
+		}
+
+		if err != nil {
+			return Shortcut{}, err
+		}
+	}
+
+	return shortcut, nil
+}
+
+// shortcutTags reads the values of the tags object in order.
+func shortcutTags(node *Node) ([]string, error) {
+	if node.Kind != NodeObject {
+		return nil, fmt.Errorf("%w: key %q is not an object", ErrValueConversion, node.Key)
+	}
+
+	tags := make([]string, 0, len(node.Children))
+	for _, child := range node.Children {
+		if child == nil {
+			continue
+		}
+
+		tag, err := steamText(child)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// shortcutGameID combines a shortcut app id with the non-Steam game id type bits.
+func shortcutGameID(appID uint32) uint64 {
+	return uint64(appID)<<32 | 0x02000000
+}
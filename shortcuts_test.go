@@ -0,0 +1,74 @@
+package vdf
+
+import (
+	"bytes"
+	"hash/crc32"
+	"reflect"
+	"testing"
+)
+
+func TestShortcutAppID(t *testing.T) {
+	t.Parallel()
+
+	exe, name := `"C:\Games\game.exe"`, "Game"
+	want := crc32.ChecksumIEEE([]byte(exe+name)) | 0x80000000
+	if got := ShortcutAppID(exe, name); got != want {
+		t.Fatalf("ShortcutAppID() = %#x, want %#x", got, want)
+	}
+
+	if got := ShortcutGameID(exe, name); got != uint64(want)<<32|0x02000000 {
+		t.Fatalf("ShortcutGameID() = %#x", got)
+	}
+}
+
+func TestShortcutsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	shortcuts := []Shortcut{
+		{
+			AppName:      "Game",
+			Exe:          `"/opt/game/run"`,
+			StartDir:     `"/opt/game/"`,
+			Icon:         "/opt/game/icon.png",
+			Tags:         []string{"favorite", "Indie"},
+			LastPlayTime: 1700000000,
+			AllowOverlay: true,
+			Extra:        []*Node{NewStringNode("sortas", "game")},
+		},
+		{AppID: 0x9234ABCD, AppName: "Other", Exe: "other", IsHidden: true},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteShortcuts(&buf, shortcuts); err != nil {
+		t.Fatalf("WriteShortcuts() returned error: %v", err)
+	}
+
+	got, err := ReadShortcuts(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadShortcuts() returned error: %v", err)
+	}
+
+	shortcuts[0].AppID = ShortcutAppID(shortcuts[0].Exe, shortcuts[0].AppName)
+	shortcuts[1].Tags = []string{}
+	if !reflect.DeepEqual(got, shortcuts) {
+		t.Fatalf("ReadShortcuts() = %+v, want %+v", got, shortcuts)
+	}
+}
+
+func TestShortcutsFromDocumentLegacyKeys(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"shortcuts" { "0" { "appname" "Old" "exe" "old.exe" "appid" "-1" "IsHidden" "1" } }`)
+	got, err := ShortcutsFromDocument(doc)
+	if err != nil {
+		t.Fatalf("ShortcutsFromDocument() returned error: %v", err)
+	}
+
+	if len(got) != 1 || got[0].AppName != "Old" || got[0].Exe != "old.exe" || got[0].AppID != 0xFFFFFFFF || !got[0].IsHidden {
+		t.Fatalf("ShortcutsFromDocument() = %+v", got)
+	}
+
+	if _, err := ShortcutsFromDocument(NewDocument()); err == nil {
+		t.Fatal("ShortcutsFromDocument() on empty document returned nil error")
+	}
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// steamText returns the text of a leaf in a Steam file model, rejecting objects.
+func steamText(node *Node) (string, error) {
+	if node.Kind == NodeObject {
+		return "", fmt.Errorf("%w: key %q is an object", ErrValueConversion, node.Key)
+	}
+
+	return textValueForNode(node)
+}
+
+// steamUint32 reads a uint32 leaf or its text form. Steam writes some ids
+// as signed 32-bit values, so negative text keeps the same bit pattern.
+func steamUint32(node *Node) (uint32, error) {
+	switch node.Kind {
+	case NodeUint32:
+		if node.Uint32Value != nil {
+			return *node.Uint32Value, nil
+		}
+	case NodeUint64:
+		if node.Uint64Value != nil && *node.Uint64Value <= math.MaxUint32 {
+			return uint32(*node.Uint64Value), nil
+		}
+	}
+
+	text, err := steamText(node)
+	if err != nil {
+		return 0, err
+	}
+
+	if value, err := strconv.ParseUint(text, 10, 32); err == nil {
+		return uint32(value), nil
+	}
+
+	value, err := strconv.ParseInt(text, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: key %q value %q is not a uint32", ErrValueConversion, node.Key, text)
+	}
+
+	return uint32(int32(value)), nil
+}
+
+// steamUint64 reads an unsigned leaf of any width or its decimal text form.
+func steamUint64(node *Node) (uint64, error) {
+	switch node.Kind {
+	case NodeUint32:
+		if node.Uint32Value != nil {
+			return uint64(*node.Uint32Value), nil
+		}
+	case NodeUint64:
+		if node.Uint64Value != nil {
+			return *node.Uint64Value, nil
+		}
+	}
+
+	text, err := steamText(node)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseUint(text, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: key %q value %q is not a uint64", ErrValueConversion, node.Key, text)
+	}
+
+	return value, nil
+}
+
+// steamInt64 reads a signed leaf or its decimal text form.
+func steamInt64(node *Node) (int64, error) {
+	if node.Kind == NodeInt64 && node.Int64Value != nil {
+		return *node.Int64Value, nil
+	}
+
+	text, err := steamText(node)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: key %q value %q is not an int64", ErrValueConversion, node.Key, text)
+	}
+
+	return value, nil
+}
+
+// steamBool reads a 0/1 flag.
+func steamBool(node *Node) (bool, error) {
+	value, err := steamUint32(node)
+	return value != 0, err
+}
+
+// steamFlag converts a flag to the 0/1 value Steam stores.
+func steamFlag(value bool) uint32 {
+	if value {
+		return 1
+	}
+
+	return 0
+}
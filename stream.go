@@ -0,0 +1,510 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"unicode/utf8"
+)
+
+// streamReader reads entries from text or binary input as events without building an AST.
+// Memory use is bounded by nesting depth, not by input size.
+// MaxDepth, MaxNodes, FoldKeys and KeyMap are honored; Strict, DuplicatePolicy, NodeFilter and Conditions apply to document decode only.
+// Text conditionals are kept unevaluated in Event.Condition.
+type streamReader struct {
+	text      *textParser    // Text token source when format is FormatText.
+	binary    *binaryDecoder // Binary byte source when format is FormatBinary.
+	buffer    *bufio.Reader  // Read buffer below the token source, when there is one.
+	typeErr   error          // Error of a binary type byte read ahead by More.
+	path      []string       // Keys of currently open objects.
+	format    Format         // Effective input format.
+	rootCount int            // Number of root entries read.
+	typeByte  byte           // Binary type byte read ahead by More.
+	hasType   bool           // Whether typeByte or typeErr is set.
+	finished  bool           // Whether the document end was reached.
+	started   bool           // Whether Token returned EventDocumentStart.
+	ended     bool           // Whether Token returned EventDocumentEnd.
+}
+
+// openStreamReader resolves the input format and creates an event reader over src.
+func openStreamReader(src io.Reader, opts DecodeOptions) (*streamReader, error) {
+	return NewDecoder(src, opts).openStream()
+}
+
+// openStream resolves the decoder input format and creates an event reader over it.
+func (d *Decoder) openStream() (*streamReader, error) {
+	if err := validateDecodeFormat(d.opts.Format); err != nil {
+		return nil, err
+	}
+
+	format, source, err := d.resolveSource()
+	if err != nil {
+		return nil, err
+	}
+
+	// Streams unwrap a VBKV header without verifying its checksum.
+	wrapped := false
+	if format == FormatBinary {
+		if source, _, wrapped, err = stripVBKVHeader(ensureBinaryReader(source)); err != nil {
+			return nil, err
+		}
+	}
+
+	stream := newStreamReader(source, format, d.opts)
+	if wrapped {
+		stream.binary.counter.n = vbkvHeaderSize
+	}
+
+	return stream, nil
+}
+
+// Token returns the next event read directly from the input stream, and io.EOF
+// after EventDocumentEnd. Events match NextEvent, but Token does not decode a
+// Document first, so memory use is bounded by nesting depth, not input size.
+// Stream rules apply: MaxDepth, MaxNodes, FoldKeys and KeyMap are honored, while
+// duplicate policies, NodeFilter and Conditions are not; text conditionals are
+// reported unevaluated in Event.Condition.
+// A decoder should be read either with Token or with DecodeDocument and NextEvent.
+func (d *Decoder) Token() (Event, error) {
+	if d.decodeErr != nil {
+		return Event{}, d.decodeErr
+	}
+
+	if err := d.ensureStream(); err != nil {
+		return Event{}, err
+	}
+
+	if !d.stream.started {
+		d.stream.started = true
+		return Event{Type: EventDocumentStart}, nil
+	}
+
+	event, err := d.stream.next()
+	switch {
+	case errors.Is(err, io.EOF) && !d.stream.ended:
+		d.stream.ended = true
+		return Event{Type: EventDocumentEnd}, nil
+	case err != nil && !errors.Is(err, io.EOF):
+		d.decodeErr = err
+	}
+
+	return event, err
+}
+
+// ensureStream opens the incremental event reader used by Token on first use.
+func (d *Decoder) ensureStream() error {
+	if d.decodeErr != nil {
+		return d.decodeErr
+	}
+
+	if d.stream != nil {
+		return nil
+	}
+
+	stream, err := d.openStream()
+	if err != nil {
+		d.decodeErr = err
+		return err
+	}
+
+	d.stream = stream
+	return nil
+}
+
+// More reports whether another entry follows in the object last opened by Token,
+// or another root follows at the top level, like json.Decoder.More.
+// It returns false on read errors, which the next Token call reports.
+func (d *Decoder) More() bool {
+	if d.ensureStream() != nil || d.stream.finished {
+		return false
+	}
+
+	s := d.stream
+	if s.format == FormatBinary {
+		typeByte, err := s.peekType()
+		return err == nil && typeByte != binaryTypeMapEnd
+	}
+
+	tok, err := s.text.peekToken()
+	return err == nil && tok.kind != textTokenRBrace && tok.kind != textTokenEOF
+}
+
+// InputOffset returns the input byte offset just past the last event read by Token,
+// counted from the start of the input or, for text input with a byte order mark,
+// from the first byte after it. UTF-16LE text offsets count transcoded UTF-8 bytes.
+func (d *Decoder) InputOffset() int64 {
+	switch {
+	case d.stream == nil:
+		return 0
+	case d.stream.format == FormatBinary:
+		offset := d.stream.binary.counter.n
+		if d.stream.hasType && d.stream.typeErr == nil {
+			offset--
+		}
+
+		return offset
+	default:
+		return d.stream.text.lastEnd
+	}
+}
+
+// Buffered returns the input the decoder has read past the current position,
+// so data following an embedded VDF document can be recovered.
+// The reader is valid until the next Token, Skip or More call.
+// Text input is read ahead by up to one token, which is not included.
+func (d *Decoder) Buffered() io.Reader {
+	s := d.stream
+	if s == nil {
+		return bytes.NewReader(nil)
+	}
+
+	var ahead []byte
+	switch {
+	case s.format == FormatBinary && s.hasType && s.typeErr == nil:
+		ahead = append(ahead, s.typeByte)
+	case s.format == FormatText && s.text.lexer.hasPeeked:
+		if r := s.text.lexer.peeked; r >= 0 {
+			ahead = utf8.AppendRune(ahead, r)
+		} else {
+			ahead = append(ahead, byte(-1-r))
+		}
+	}
+
+	var rest []byte
+	if s.buffer != nil {
+		rest, _ = s.buffer.Peek(s.buffer.Buffered())
+	}
+
+	if len(ahead) == 0 {
+		return bytes.NewReader(rest)
+	}
+
+	return bytes.NewReader(append(ahead, rest...))
+}
+
+// Skip discards the rest of the innermost object opened by Token, through its
+// EventObjectEnd, without building nodes, so unwanted subtrees can be passed over
+// during selective extraction. It fails with ErrInvalidNodeState when no object is open.
+func (d *Decoder) Skip() error {
+	if d.decodeErr != nil {
+		return d.decodeErr
+	}
+
+	if d.stream == nil || len(d.stream.path) == 0 {
+		return fmt.Errorf("%w: no open object to skip", ErrInvalidNodeState)
+	}
+
+	depth := len(d.stream.path)
+	for len(d.stream.path) >= depth {
+		if _, err := d.Token(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeRoots reads the input one root at a time and passes each root node to fn,
+// built from Token events, so only one root subtree is held in memory.
+// Roots are not retained after fn returns; an error from fn stops decoding and is returned.
+// Stream rules of Token apply.
+func (d *Decoder) DecodeRoots(fn func(root *Node) error) error {
+	stack := make([]*Node, 0, 8)
+	for {
+		event, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		var node *Node
+		switch event.Type {
+		case EventDocumentStart, EventDocumentEnd:
+			continue
+		case EventObjectStart:
+			obj := NewObjectNode(event.Key)
+			obj.Condition = event.Condition
+			stack = append(stack, obj)
+			continue
+		case EventObjectEnd:
+			node = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+		default:
+			leaf, ok := eventLeafNode(event)
+			if !ok {
+				return fmt.Errorf("%w: event type %d is not a leaf", ErrInvalidNodeState, event.Type)
+			}
+
+			node = leaf
+		}
+
+		if len(stack) > 0 {
+			stack[len(stack)-1].Add(node)
+			continue
+		}
+
+		if err := fn(node); err != nil {
+			return err
+		}
+	}
+}
+
+// newStreamReader creates an event reader over an input with a resolved format.
+func newStreamReader(r io.Reader, format Format, opts DecodeOptions) *streamReader {
+	s := &streamReader{
+		format: format,
+		path:   make([]string, 0, 8),
+	}
+
+	switch format {
+	case FormatBinary:
+		reader := ensureBinaryReader(r)
+		s.buffer, _ = reader.(*bufio.Reader)
+		counter := &countingReader{r: reader}
+		s.binary = &binaryDecoder{reader: counter, counter: counter, opts: opts}
+	default:
+		s.text = &textParser{lexer: newTextLexer(r), opts: opts}
+		s.text.lexer.applyOptions(opts)
+		s.buffer, _ = s.text.lexer.reader.(*bufio.Reader)
+	}
+
+	return s
+}
+
+// peekType reads the next binary type byte ahead without consuming it.
+func (s *streamReader) peekType() (byte, error) {
+	if !s.hasType {
+		s.typeByte, s.typeErr = s.binary.readTypeByte()
+		s.hasType = true
+	}
+
+	return s.typeByte, s.typeErr
+}
+
+// readType consumes the next binary type byte.
+func (s *streamReader) readType() (byte, error) {
+	typeByte, err := s.peekType()
+	s.hasType = false
+	return typeByte, err
+}
+
+// next returns the next object or leaf event and io.EOF once the document ends.
+func (s *streamReader) next() (Event, error) {
+	if s.finished {
+		return Event{}, io.EOF
+	}
+
+	if s.format == FormatBinary {
+		event, err := s.nextBinary()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return Event{}, &BinaryError{Err: err, Offset: s.binary.offset()}
+		}
+
+		return event, err
+	}
+
+	return s.nextText()
+}
+
+// nextText reads one event from text input.
+func (s *streamReader) nextText() (Event, error) {
+	p := s.text
+
+	tok, err := p.peekToken()
+	if err != nil {
+		return Event{}, err
+	}
+
+	if len(s.path) > 0 {
+		// Closing brace completes the current object scope.
+		if tok.kind == textTokenRBrace {
+			if _, err := p.nextToken(); err != nil {
+				return Event{}, err
+			}
+
+			return s.closeObject(), nil
+		}
+
+		if tok.kind == textTokenEOF {
+			return Event{}, tok.errorAt(fmt.Errorf("%w for object %q", ErrUnexpectedEOFInObject, s.path[len(s.path)-1]))
+		}
+	} else if tok.kind == textTokenEOF {
+		s.finished = true
+		return Event{}, io.EOF
+	}
+
+	entry, err := p.parseEntry(len(s.path) + 1)
+	if err != nil {
+		return Event{}, err
+	}
+
+	if entry.kind == NodeDirective {
+		value := entry.value
+		return Event{Type: EventDirective, Key: entry.key, Condition: entry.condition, Depth: 1, StringValue: &value}, nil
+	}
+
+	key := s.mapKey(entry.key, p.opts)
+	if entry.kind == NodeObject {
+		event := s.openObject(key)
+		event.Condition = entry.condition
+		return event, nil
+	}
+
+	value := entry.value
+	return Event{Type: EventString, Key: key, Condition: entry.condition, Depth: len(s.path) + 1, StringValue: &value}, nil
+}
+
+// nextBinary reads one event from binary input.
+func (s *streamReader) nextBinary() (Event, error) {
+	d := s.binary
+
+	typeByte, err := s.readType()
+	if errors.Is(err, io.EOF) {
+		if len(s.path) == 0 && s.rootCount == 0 {
+			s.finished = true
+			return Event{}, io.EOF
+		}
+
+		return Event{}, ErrBufferOverflow
+	}
+
+	if err != nil {
+		return Event{}, err
+	}
+
+	if typeByte == binaryTypeMapEnd {
+		if len(s.path) == 0 {
+			s.finished = true
+			return Event{}, io.EOF
+		}
+
+		return s.closeObject(), nil
+	}
+
+	depth := len(s.path) + 1
+	if err := d.checkDepth(depth); err != nil {
+		return Event{}, err
+	}
+
+	key, err := d.readKey()
+	if err != nil {
+		return Event{}, err
+	}
+
+	key = s.mapKey(key, d.opts)
+
+	var event Event
+	switch typeByte {
+	case binaryTypeMapStart:
+		event = s.openObject(key)
+	case binaryTypeString:
+		value, err := d.readNullTerminatedString()
+		if err != nil {
+			return Event{}, err
+		}
+
+		event = Event{Type: EventString, Key: key, Depth: depth, StringValue: &value}
+	case binaryTypeWideString:
+		value, err := d.readWideString()
+		if err != nil {
+			return Event{}, err
+		}
+
+		event = Event{Type: EventWideString, Key: key, Depth: depth, StringValue: &value}
+	case binaryTypeNumber:
+		value, err := d.readUint32()
+		if err != nil {
+			return Event{}, err
+		}
+
+		event = Event{Type: EventUint32, Key: key, Depth: depth, Uint32Value: &value}
+	case binaryTypePointer:
+		value, err := d.readUint32()
+		if err != nil {
+			return Event{}, err
+		}
+
+		event = Event{Type: EventPointer, Key: key, Depth: depth, Uint32Value: &value}
+	case binaryTypeFloat32:
+		bits, err := d.readUint32()
+		if err != nil {
+			return Event{}, err
+		}
+
+		value := math.Float32frombits(bits)
+		event = Event{Type: EventFloat32, Key: key, Depth: depth, Float32Value: &value}
+	case binaryTypeUint64:
+		value, err := d.readUint64()
+		if err != nil {
+			return Event{}, err
+		}
+
+		event = Event{Type: EventUint64, Key: key, Depth: depth, Uint64Value: &value}
+	case binaryTypeInt64:
+		bits, err := d.readUint64()
+		if err != nil {
+			return Event{}, err
+		}
+
+		value := int64(bits)
+		event = Event{Type: EventInt64, Key: key, Depth: depth, Int64Value: &value}
+	case binaryTypeColor:
+		value, err := d.readColor()
+		if err != nil {
+			return Event{}, err
+		}
+
+		event = Event{Type: EventColor, Key: key, Depth: depth, ColorValue: &value}
+	default:
+		if d.binaryNodeKind(typeByte) != NodeRaw {
+			return Event{}, fmt.Errorf("%w: 0x%02x", ErrUnrecognizedType, typeByte)
+		}
+
+		payload, err := d.readRawPayload(typeByte)
+		if err != nil {
+			return Event{}, err
+		}
+
+		event = Event{Type: EventRaw, Key: key, Depth: depth, RawType: typeByte, RawValue: payload}
+	}
+
+	if err := d.incrementNodeCount(); err != nil {
+		return Event{}, err
+	}
+
+	return event, nil
+}
+
+// mapKey applies the FoldKeys and KeyMap options and counts root entries.
+func (s *streamReader) mapKey(key string, opts DecodeOptions) string {
+	if len(s.path) == 0 {
+		s.rootCount++
+	}
+
+	return mapDecodedKey(opts, s.path, key)
+}
+
+// openObject pushes an object key and returns its start event.
+func (s *streamReader) openObject(key string) Event {
+	s.path = append(s.path, key)
+	return Event{Type: EventObjectStart, Key: key, Depth: len(s.path)}
+}
+
+// closeObject pops the innermost object key and returns its end event.
+func (s *streamReader) closeObject() Event {
+	depth := len(s.path)
+	key := s.path[depth-1]
+	s.path = s.path[:depth-1]
+
+	return Event{Type: EventObjectEnd, Key: key, Depth: depth}
+}
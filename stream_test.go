@@ -0,0 +1,269 @@
+package vdf
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecoderTokenMatchesNextEvent(t *testing.T) {
+	t.Parallel()
+
+	input := `"root" { "name" "srv" "nested" { "port" "2302" } } "other" "1"`
+	doc := mustParseString(t, input)
+	bin, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	for _, data := range [][]byte{[]byte(input), bin} {
+		want := collectEvents(t, NewDecoder(bytes.NewReader(data), DecodeOptions{}).NextEvent)
+		got := collectEvents(t, NewDecoder(bytes.NewReader(data), DecodeOptions{}).Token)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Token() = %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestDecoderTokenError(t *testing.T) {
+	t.Parallel()
+
+	dec := NewDecoder(strings.NewReader(`"root" { "a" "1"`), DecodeOptions{Format: FormatText})
+	var err error
+	for err == nil {
+		_, err = dec.Token()
+	}
+
+	if !errors.Is(err, ErrUnexpectedEOFInObject) {
+		t.Fatalf("Token() error = %v, want ErrUnexpectedEOFInObject", err)
+	}
+
+	if _, again := dec.Token(); !errors.Is(again, ErrUnexpectedEOFInObject) {
+		t.Fatalf("Token() after error = %v, want the same error", again)
+	}
+}
+
+func collectEvents(t *testing.T, next func() (Event, error)) []Event {
+	t.Helper()
+
+	var events []Event
+	for {
+		event, err := next()
+		if errors.Is(err, io.EOF) {
+			return events
+		}
+
+		if err != nil {
+			t.Fatalf("reading events returned error: %v", err)
+		}
+
+		events = append(events, event)
+	}
+}
+
+func TestDecoderDecodeRoots(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"a" { "x" "1" "inner" { "y" "2" } } "b" "3" "c" { }`)
+	doc.Roots[0].Add(NewUint64Node("big", 1<<40))
+	bin, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	decoded := NewDocumentWithFormat(FormatBinary)
+	err = NewDecoder(bytes.NewReader(bin), DecodeOptions{}).DecodeRoots(func(root *Node) error {
+		decoded.AddRoot(root)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DecodeRoots() returned error: %v", err)
+	}
+
+	if paths := ChangedPaths(doc, decoded); len(paths) != 0 {
+		t.Fatalf("DecodeRoots() changed paths %v", paths)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = NewDecoder(bytes.NewReader(bin), DecodeOptions{}).DecodeRoots(func(*Node) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("DecodeRoots() = %v after %d calls, want stop after 1", err, calls)
+	}
+}
+
+func TestDecoderSkip(t *testing.T) {
+	t.Parallel()
+
+	input := `"root" { "skip" { "a" "1" "deep" { "b" "2" } } "keep" "3" }`
+	dec := NewDecoder(strings.NewReader(input), DecodeOptions{Format: FormatText})
+	if err := dec.Skip(); !errors.Is(err, ErrInvalidNodeState) {
+		t.Fatalf("Skip() before Token = %v, want ErrInvalidNodeState", err)
+	}
+
+	var keys []string
+	for {
+		event, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Token() returned error: %v", err)
+		}
+
+		keys = append(keys, event.Key)
+		if event.Type == EventObjectStart && event.Key == "skip" {
+			if err := dec.Skip(); err != nil {
+				t.Fatalf("Skip() returned error: %v", err)
+			}
+		}
+	}
+
+	want := []string{"", "root", "skip", "keep", "root", ""}
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("keys = %q, want %q", keys, want)
+	}
+}
+
+func TestDecoderMoreInputOffsetBuffered(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"a" { "x" "1" } "b" "2"`)
+	bin, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	trailer := []byte("TRAILER")
+	// Hide io.ByteReader so the decoder buffers the input itself.
+	input := struct{ io.Reader }{bytes.NewReader(append(bin, trailer...))}
+	dec := NewDecoder(input, DecodeOptions{Format: FormatBinary})
+
+	var roots []string
+	for dec.More() {
+		if len(roots) == 0 {
+			if _, err := dec.Token(); err != nil {
+				t.Fatalf("Token() returned error: %v", err)
+			}
+		}
+
+		event, err := dec.Token()
+		if err != nil {
+			t.Fatalf("Token() returned error: %v", err)
+		}
+
+		roots = append(roots, event.Key)
+		if event.Type == EventObjectStart {
+			if err := dec.Skip(); err != nil {
+				t.Fatalf("Skip() returned error: %v", err)
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(roots, []string{"a", "b"}) {
+		t.Fatalf("roots = %q, want [a b]", roots)
+	}
+
+	if event, err := dec.Token(); err != nil || event.Type != EventDocumentEnd {
+		t.Fatalf("Token() = %+v, %v, want EventDocumentEnd", event, err)
+	}
+
+	if got := dec.InputOffset(); got != int64(len(bin)) {
+		t.Fatalf("InputOffset() = %d, want %d", got, len(bin))
+	}
+
+	rest, err := io.ReadAll(dec.Buffered())
+	if err != nil || !bytes.Equal(rest, trailer) {
+		t.Fatalf("Buffered() = %q, %v, want %q", rest, err, trailer)
+	}
+}
+
+func TestDecoderTextInputOffset(t *testing.T) {
+	t.Parallel()
+
+	input := `"a" "1"  "b" { }`
+	dec := NewDecoder(strings.NewReader(input), DecodeOptions{Format: FormatText})
+	offsets := []int64{}
+	for dec.More() {
+		if _, err := dec.Token(); err != nil {
+			t.Fatalf("Token() returned error: %v", err)
+		}
+
+		offsets = append(offsets, dec.InputOffset())
+	}
+
+	want := []int64{0, 7, 14}
+	if !reflect.DeepEqual(offsets, want) {
+		t.Fatalf("offsets = %v, want %v", offsets, want)
+	}
+}
+
+func TestEncoderWriteEventTransform(t *testing.T) {
+	t.Parallel()
+
+	input := `"root" { "name" "srv" "secret" "x" "nested" { "port" "2302" } }`
+	dec := NewDecoder(strings.NewReader(input), DecodeOptions{Format: FormatText})
+
+	var out bytes.Buffer
+	enc := NewEncoder(&out, EncodeOptions{Format: FormatBinary})
+	for {
+		event, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Token() returned error: %v", err)
+		}
+
+		if event.Key == "secret" {
+			continue
+		}
+
+		if event.Key == "name" {
+			event.Key = "hostname"
+		}
+
+		if err := enc.WriteEvent(event); err != nil {
+			t.Fatalf("WriteEvent() returned error: %v", err)
+		}
+	}
+
+	got, err := ParseBytes(out.Bytes(), DecodeOptions{Format: FormatBinary})
+	if err != nil {
+		t.Fatalf("ParseBytes() returned error: %v", err)
+	}
+
+	want := mustParseString(t, `"root" { "hostname" "srv" "nested" { "port" "2302" } }`)
+	if paths := ChangedPaths(want, got); len(paths) != 0 {
+		t.Fatalf("transformed document changed paths %v", paths)
+	}
+}
+
+func TestEncoderWriteEventMissingValue(t *testing.T) {
+	t.Parallel()
+
+	types := []EventType{
+		EventString, EventUint32, EventFloat32, EventPointer, EventWideString,
+		EventColor, EventUint64, EventInt64, EventDirective,
+	}
+
+	for _, format := range []Format{FormatText, FormatBinary} {
+		for _, eventType := range types {
+			for _, condition := range []string{"", "$WIN32"} {
+				var buf bytes.Buffer
+				err := NewEncoder(&buf, EncodeOptions{Format: format}).WriteEvent(Event{Type: eventType, Key: "k", Condition: condition})
+				if !errors.Is(err, ErrInvalidNodeState) {
+					t.Fatalf("WriteEvent(type=%d, format=%d) error = %v, want ErrInvalidNodeState", eventType, format, err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldDiff describes one mapped struct field that differs from a document subtree.
+type FieldDiff struct {
+	// Path is the slash-delimited key path relative to the compared node.
+	Path string `json:"path" yaml:"path"`
+	// Expected is the textual value from the struct (empty for objects).
+	Expected string `json:"expected,omitempty" yaml:"expected,omitempty"`
+	// Actual is the textual value found in the document (empty for objects or missing keys).
+	Actual string `json:"actual,omitempty" yaml:"actual,omitempty"`
+	// Missing is true when the document has no entry for the field.
+	Missing bool `json:"missing,omitempty" yaml:"missing,omitempty"`
+}
+
+// DiffStruct compares the mapped fields of struct v against an object node
+// and reports fields whose values differ or are missing from the node.
+// Leaves are compared by their text form, so uint32 and numeric string leaves
+// holding the same number are equal. Children not mapped by v are ignored,
+// and nil pointer fields are not compared.
+func DiffStruct(v any, node *Node) ([]FieldDiff, error) {
+	if node == nil || node.Kind != NodeObject {
+		return nil, fmt.Errorf("%w: %q is not an object", ErrInvalidNodeState, nodeKey(node))
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: diff requires a struct, got %T", ErrUnsupportedGoType, v)
+	}
+
+	desired := &Node{Key: node.Key}
+	if err := encodeNodeValue(desired, rv); err != nil {
+		return nil, err
+	}
+
+	diffs := make([]FieldDiff, 0)
+	collectFieldDiffs(desired, node, "", &diffs)
+	return diffs, nil
+}
+
+// collectFieldDiffs compares desired object children with actual ones and appends differences.
+func collectFieldDiffs(desired, actual *Node, prefix string, diffs *[]FieldDiff) {
+	seen := make(map[string]int, len(desired.Children))
+	for _, want := range desired.Children {
+		occurrence := seen[want.Key]
+		seen[want.Key]++
+
+		path := joinOccurrencePath(prefix, want.Key, occurrence)
+		var got *Node
+		if matches := fieldChildren(actual, want.Key); occurrence < len(matches) {
+			got = matches[occurrence]
+		}
+
+		if got == nil {
+			expected, _ := textValueForNode(want)
+			*diffs = append(*diffs, FieldDiff{Path: path, Expected: expected, Missing: true})
+			continue
+		}
+
+		if want.Kind == NodeObject && got.Kind == NodeObject {
+			collectFieldDiffs(want, got, path, diffs)
+			continue
+		}
+
+		expected, expectedErr := textValueForNode(want)
+		current, actualErr := textValueForNode(got)
+		if expectedErr != nil || actualErr != nil || expected != current {
+			*diffs = append(*diffs, FieldDiff{Path: path, Expected: expected, Actual: current})
+		}
+	}
+}
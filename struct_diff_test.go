@@ -0,0 +1,42 @@
+package vdf
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiffStruct(t *testing.T) {
+	t.Parallel()
+
+	type desired struct {
+		Launch string `vdf:"LaunchOptions"`
+		Video  struct {
+			Width  uint32 `vdf:"width"`
+			Height uint32 `vdf:"height"`
+		} `vdf:"video"`
+		AppID   uint32 `vdf:"appid"`
+		Beta    string `vdf:"beta"`
+		Overlay bool   `vdf:"overlay"`
+	}
+
+	doc := mustParseString(t, `"cfg" { "appid" "440" "LaunchOptions" "-novid" "video" { "width" "1920" "height" "1080" } "overlay" "1" "unknown" "x" }`)
+
+	want := desired{Launch: "-novid -high", AppID: 440, Beta: "public", Overlay: true}
+	want.Video.Width = 1920
+	want.Video.Height = 720
+
+	diffs, err := DiffStruct(&want, doc.Roots[0])
+	if err != nil {
+		t.Fatalf("DiffStruct() returned error: %v", err)
+	}
+
+	expected := []FieldDiff{
+		{Path: "LaunchOptions", Expected: "-novid -high", Actual: "-novid"},
+		{Path: "video/height", Expected: "720", Actual: "1080"},
+		{Path: "beta", Expected: "public", Missing: true},
+	}
+
+	if !slices.Equal(diffs, expected) {
+		t.Fatalf("DiffStruct() = %+v, want %+v", diffs, expected)
+	}
+}
@@ -4,22 +4,51 @@
 
 package vdf
 
+import "io/fs"
+
 // Map represents a generic key-value mapping used by explicit adapters.
 // It is inherently lossy for duplicate keys and ordering.
 type Map map[string]any
 
+// OrderedMap is a lossless alternative to Map: key-value pairs in source
+// order, with repeated keys kept as separate pairs.
+// Values are leaf values as returned by Node.Value, or OrderedMap for objects.
+type OrderedMap []KeyValue
+
+// KeyValue is one pair of an OrderedMap.
+type KeyValue struct {
+	// Value is a leaf value or a nested OrderedMap.
+	Value any `json:"value" yaml:"value"`
+	// Key is the node key.
+	Key string `json:"key" yaml:"key"`
+}
+
 // Event is a streaming traversal event.
 type Event struct {
-	// StringValue is set for EventString.
+	// StringValue is set for EventString, EventWideString and EventDirective.
 	StringValue *string `json:"string_value,omitempty" yaml:"string_value,omitempty"`
-	// Uint32Value is set for EventUint32.
+	// Uint32Value is set for EventUint32 and EventPointer.
 	Uint32Value *uint32 `json:"uint32_value,omitempty" yaml:"uint32_value,omitempty"`
+	// Float32Value is set for EventFloat32.
+	Float32Value *float32 `json:"float32_value,omitempty" yaml:"float32_value,omitempty"`
+	// ColorValue is set for EventColor.
+	ColorValue *Color `json:"color_value,omitempty" yaml:"color_value,omitempty"`
+	// Uint64Value is set for EventUint64.
+	Uint64Value *uint64 `json:"uint64_value,omitempty" yaml:"uint64_value,omitempty"`
+	// Int64Value is set for EventInt64.
+	Int64Value *int64 `json:"int64_value,omitempty" yaml:"int64_value,omitempty"`
 	// Key is the node key associated with this event.
 	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+	// Condition is the platform conditional of a text entry without brackets, or empty.
+	Condition string `json:"condition,omitempty" yaml:"condition,omitempty"`
+	// RawValue is the verbatim binary payload for EventRaw.
+	RawValue []byte `json:"raw_value,omitempty" yaml:"raw_value,omitempty"`
 	// Depth is the traversal depth for this event.
 	Depth int `json:"depth" yaml:"depth"`
 	// Type is the event kind.
 	Type EventType `json:"type" yaml:"type"`
+	// RawType is the binary type byte for EventRaw.
+	RawType byte `json:"raw_type,omitempty" yaml:"raw_type,omitempty"`
 }
 
 // EventType represents a decoded event type from streaming traversal.
@@ -38,30 +67,149 @@ const (
 	EventString
 	// EventUint32 marks a uint32 leaf node.
 	EventUint32
+	// EventFloat32 marks a float32 leaf node.
+	EventFloat32
+	// EventPointer marks a pointer leaf node.
+	EventPointer
+	// EventWideString marks a wide string leaf node.
+	EventWideString
+	// EventColor marks a color leaf node.
+	EventColor
+	// EventUint64 marks a uint64 leaf node.
+	EventUint64
+	// EventInt64 marks an int64 leaf node.
+	EventInt64
+	// EventDirective marks an "#include" or "#base" directive.
+	EventDirective
+	// EventRaw marks a binary entry of an unrecognized type preserved verbatim.
+	EventRaw
 )
 
 // Document represents a complete VDF document.
 type Document struct {
 	// Roots contains top-level nodes in source order.
 	Roots []*Node `json:"roots,omitempty" yaml:"roots,omitempty"`
+	// Layout holds the source text after the last root when decoded with DecodeOptions.Fidelity.
+	Layout *TextLayout `json:"-" yaml:"-"`
 	// Format is the source or intended encode format.
 	Format Format `json:"format,omitempty" yaml:"format,omitempty"`
 }
 
 // Node represents a VDF AST node.
 type Node struct {
-	// StringValue is set for NodeString.
+	// StringValue is set for NodeString, NodeWideString and NodeDirective.
 	StringValue *string `json:"string_value,omitempty" yaml:"string_value,omitempty"`
-	// Uint32Value is set for NodeUint32.
+	// Uint32Value is set for NodeUint32 and NodePointer.
 	Uint32Value *uint32 `json:"uint32_value,omitempty" yaml:"uint32_value,omitempty"`
+	// Float32Value is set for NodeFloat32.
+	Float32Value *float32 `json:"float32_value,omitempty" yaml:"float32_value,omitempty"`
+	// ColorValue is set for NodeColor.
+	ColorValue *Color `json:"color_value,omitempty" yaml:"color_value,omitempty"`
+	// Uint64Value is set for NodeUint64.
+	Uint64Value *uint64 `json:"uint64_value,omitempty" yaml:"uint64_value,omitempty"`
+	// Int64Value is set for NodeInt64.
+	Int64Value *int64 `json:"int64_value,omitempty" yaml:"int64_value,omitempty"`
 	// Key is the node key.
 	Key string `json:"key" yaml:"key"`
+	// Condition is the platform conditional of a text entry without brackets,
+	// such as "$WIN32" or "!$OSX" for "[$WIN32]" and "[!$OSX]"; empty when absent.
+	// Binary encoding drops it.
+	Condition string `json:"condition,omitempty" yaml:"condition,omitempty"`
 	// Children are set for NodeObject and preserve source order.
 	Children []*Node `json:"children,omitempty" yaml:"children,omitempty"`
+	// RawValue is the verbatim binary payload for NodeRaw.
+	RawValue []byte `json:"raw_value,omitempty" yaml:"raw_value,omitempty"`
 	// Kind defines the node payload shape.
 	Kind NodeKind `json:"kind" yaml:"kind"`
+	// Span holds source byte ranges when decoded with DecodeOptions.RecordSpans.
+	Span *Span `json:"-" yaml:"-"`
+	// Pos holds the key source position when decoded with DecodeOptions.TrackPositions.
+	Pos *Pos `json:"-" yaml:"-"`
+	// Layout holds the source text around a text node when decoded with DecodeOptions.Fidelity.
+	Layout *TextLayout `json:"-" yaml:"-"`
+	// RawType is the binary type byte for NodeRaw.
+	RawType byte `json:"raw_type,omitempty" yaml:"raw_type,omitempty"`
+	// KeyUnquoted records a text key written without double quotes.
+	// It is honored by EncodeOptions.QuoteStyle QuotePreserve.
+	KeyUnquoted bool `json:"key_unquoted,omitempty" yaml:"key_unquoted,omitempty"`
+	// ValueUnquoted records a text scalar value written without double quotes.
+	// It is honored by EncodeOptions.QuoteStyle QuotePreserve.
+	ValueUnquoted bool `json:"value_unquoted,omitempty" yaml:"value_unquoted,omitempty"`
 }
 
+// Span records where a node was found in the decoded input.
+// Offsets are zero-based byte positions and every End is exclusive.
+//
+// In text input the key and scalar value ranges cover the raw tokens including
+// quotes, and an object value range spans from "{" through "}".
+// In binary input the key and string ranges exclude the null terminator,
+// and an object value range spans from its first child through the map end marker.
+type Span struct {
+	// KeyStart is the offset of the first key byte.
+	KeyStart int64 `json:"key_start" yaml:"key_start"`
+	// KeyEnd is the offset just past the key.
+	KeyEnd int64 `json:"key_end" yaml:"key_end"`
+	// ValueStart is the offset of the first value byte.
+	ValueStart int64 `json:"value_start" yaml:"value_start"`
+	// ValueEnd is the offset just past the value.
+	ValueEnd int64 `json:"value_end" yaml:"value_end"`
+}
+
+// Pos is the source position of a decoded node key.
+// Binary input has no lines, so only Offset is set.
+type Pos struct {
+	// Offset is the byte offset of the first key byte.
+	// For UTF-16LE text input it refers to the transcoded UTF-8 text.
+	Offset int64 `json:"offset" yaml:"offset"`
+	// Line is the 1-based line number of text input.
+	Line int `json:"line,omitempty" yaml:"line,omitempty"`
+	// Col is the 1-based column of text input, counted in runes.
+	Col int `json:"col,omitempty" yaml:"col,omitempty"`
+}
+
+// DuplicatePolicy defines how decoding handles repeated keys within one object.
+type DuplicatePolicy uint8
+
+const (
+	// DuplicateKeepAll keeps every occurrence in source order.
+	DuplicateKeepAll DuplicatePolicy = iota
+	// DuplicateFirstWins keeps the first occurrence and skips later subtrees without building them.
+	DuplicateFirstWins
+	// DuplicateLastWins keeps the last occurrence at the position of the first one.
+	DuplicateLastWins
+	// DuplicateError fails decoding with ErrDuplicateKeyInStrictMode.
+	DuplicateError
+)
+
+// QuoteStyle defines when text encoding wraps keys and scalar values in double quotes.
+type QuoteStyle uint8
+
+const (
+	// QuoteAlways quotes every key and scalar value.
+	QuoteAlways QuoteStyle = iota
+	// QuotePreserve leaves tokens unquoted that were unquoted in the decoded source,
+	// as recorded by Node.KeyUnquoted and Node.ValueUnquoted, while they stay safe to read back.
+	QuotePreserve
+	// QuoteWhenNeeded writes a token unquoted unless it is empty, starts with "[" or "//",
+	// or contains whitespace, braces, quotes or backslashes, in document, manual and
+	// Fidelity encoding alike, which matches hand-written Valve configs.
+	QuoteWhenNeeded
+)
+
+// InvalidUTF8Policy defines how text decoding handles bytes that are not valid UTF-8
+// inside keys, values and conditionals.
+type InvalidUTF8Policy uint8
+
+const (
+	// InvalidUTF8Replace decodes each invalid byte as U+FFFD.
+	InvalidUTF8Replace InvalidUTF8Policy = iota
+	// InvalidUTF8Reject fails decoding with ErrInvalidUTF8.
+	InvalidUTF8Reject
+	// InvalidUTF8PassThrough keeps invalid bytes, such as CP-1252 text, unchanged in
+	// decoded strings, so encoding writes them back byte for byte.
+	InvalidUTF8PassThrough
+)
+
 // NodeKind defines the value type represented by a node.
 type NodeKind uint8
 
@@ -72,32 +220,187 @@ const (
 	NodeString
 	// NodeUint32 is a leaf node containing an unsigned 32-bit value.
 	NodeUint32
+	// NodeRaw is a binary-only leaf keeping an unrecognized type byte and its raw payload.
+	NodeRaw
+	// NodeFloat32 is a leaf node containing a 32-bit float (binary type 0x03).
+	NodeFloat32
+	// NodePointer is a leaf node containing a 32-bit pointer value (binary type 0x04)
+	// stored in Uint32Value.
+	NodePointer
+	// NodeWideString is a leaf node containing a string stored as UTF-16LE in
+	// binary form (binary type 0x05); the transcoded value is kept in StringValue.
+	NodeWideString
+	// NodeColor is a leaf node containing an RGBA color (binary type 0x06).
+	NodeColor
+	// NodeUint64 is a leaf node containing an unsigned 64-bit integer (binary type 0x07).
+	NodeUint64
+	// NodeInt64 is a leaf node containing a signed 64-bit integer (binary type 0x0A).
+	NodeInt64
+	// NodeDirective is a text-only root node for an "#include" or "#base" directive;
+	// Key holds the directive name as written and StringValue the referenced file.
+	NodeDirective
 )
 
 // DecodeOptions controls decoder behavior.
 type DecodeOptions struct {
+	// NodeFilter, when set, decides whether an entry is built into the AST.
+	// It receives keys of enclosing objects, the entry key and its kind.
+	// Rejected objects are skipped with their whole subtree.
+	// The path slice is reused between calls and must be copied to be retained.
+	NodeFilter func(path []string, key string, kind NodeKind) bool
+	// RawTypeSizes declares fixed payload sizes for vendor binary type bytes
+	// preserved with PreserveUnknownTypes.
+	RawTypeSizes map[byte]int
+	// Conditions, when non-nil, evaluates text conditionals such as "[$WIN32]"
+	// and drops entries whose conditional is false, with their subtree.
+	// Keys are symbol names without "$" matched case-insensitively; missing
+	// symbols are false. Expressions may combine "!", "&&" and "||".
+	// Kept entries retain Node.Condition.
+	Conditions map[string]bool
+	// IncludeFS, when set, resolves "#include" and "#base" directives of text
+	// input during DecodeDocument by reading the referenced files from it.
+	// Included roots are appended after the document roots, and base roots
+	// fill keys missing from the document, as in ApplyDefaults.
+	// Resolved directives are removed; event streaming leaves them unresolved.
+	IncludeFS fs.FS
+	// StringPool, when non-nil, switches binary input to pooled keys:
+	// each key is a little-endian uint32 index into StringPool instead of a
+	// null-terminated string, as in appinfo.vdf version 29 payloads.
+	// Text input ignores it.
+	StringPool []string
+	// KeyMap, when set, rewrites each key as it is parsed.
+	// It runs before NodeFilter and strict duplicate checks, and path holds already mapped keys.
+	// The path slice is reused between calls and must be copied to be retained.
+	KeyMap func(path []string, key string) string
+	// IncludePath is the IncludeFS path of the decoded input.
+	// Directive paths resolve against the directory of the including file,
+	// starting from the directory of IncludePath or the FS root when empty.
+	IncludePath string
 	// Format selects expected input format.
 	Format Format
 	// Strict enables stricter validation paths where available.
+	// With the default DuplicatePolicy it rejects duplicate keys like DuplicateError.
 	Strict bool
-	// MaxDepth limits nested object depth (0 means unlimited).
+	// DuplicatePolicy controls repeated keys within one object at parse time.
+	DuplicatePolicy DuplicatePolicy
+	// InvalidUTF8 controls invalid UTF-8 bytes in text tokens (0 replaces them with U+FFFD).
+	// Binary strings are always kept as stored.
+	InvalidUTF8 InvalidUTF8Policy
+	// RecordSpans stores key and value byte ranges of every node in Node.Span.
+	// For UTF-16LE text input the ranges refer to the transcoded UTF-8 text.
+	RecordSpans bool
+	// TrackPositions stores the key line, column and byte offset of every node in Node.Pos,
+	// so linters and editors can point at the line defining a key.
+	TrackPositions bool
+	// FoldKeys lower-cases every key as it is parsed, before KeyMap,
+	// so lookups and duplicate checks see canonical keys. Directive names are not keys.
+	FoldKeys bool
+	// DisableEscapes reads backslashes in quoted text tokens literally, like
+	// Valve KeyValues without UsesEscapeSequences, so Windows paths such as
+	// "C:\Games" keep their backslashes. A quoted token ends at the next double quote.
+	DisableEscapes bool
+	// Recover keeps decoding text input after recoverable problems, such as an
+	// unterminated string or conditional at EOF, unclosed objects, stray braces
+	// and keys without values. DecodeDocument then returns the best-effort
+	// Document together with an ErrorList of the problems. Event streaming
+	// and binary input are not recovered.
+	Recover bool
+	// Fidelity records the whitespace, comments and token quoting of text input
+	// in Node.Layout and Document.Layout, so EncodeOptions.Fidelity can write
+	// unchanged parts back byte for byte. Text input is read fully into memory.
+	Fidelity bool
+	// PreserveUnknownTypes decodes binary entries with unrecognized type bytes
+	// into NodeRaw leaves instead of failing. Payload sizes come from the
+	// built-in table of Valve extension types and RawTypeSizes.
+	PreserveUnknownTypes bool
+	// SkipVBKVChecksum accepts binary input wrapped in a VBKV header without
+	// comparing the CRC32 stored in the header with the body.
+	SkipVBKVChecksum bool
+	// VerifyChecksum requires a little-endian CRC32 (IEEE) trailer after binary
+	// payloads and fails with ErrChecksumMismatch when it does not match.
+	VerifyChecksum bool
+	// MaxDepth limits nested object depth.
+	// Zero applies DefaultMaxDepth; use Unlimited to disable the limit.
 	MaxDepth int
 	// MaxNodes limits total parsed nodes (0 means unlimited).
 	MaxNodes int
+	// MaxInputBytes limits the bytes read from the decoder input (0 means unlimited);
+	// longer input fails with ErrInputLimitExceeded. Files read through IncludeFS
+	// are not counted.
+	MaxInputBytes int64
+	// MaxKeyBytes limits the decoded size of each key (0 means unlimited).
+	// Oversized keys fail with ErrStringLimitExceeded before they are fully read.
+	MaxKeyBytes int
+	// MaxStringBytes limits the decoded size of each string value, counting
+	// UTF-16 bytes for binary wide strings (0 means unlimited).
+	// Oversized values fail with ErrStringLimitExceeded before they are fully read.
+	MaxStringBytes int
+	// MaxIncludeDepth limits nested include levels resolved through IncludeFS.
+	// Zero applies DefaultMaxIncludeDepth; use Unlimited to disable the limit.
+	MaxIncludeDepth int
 }
 
+const (
+	// DefaultMaxDepth is the nesting limit applied when DecodeOptions.MaxDepth is zero.
+	DefaultMaxDepth = 128
+	// DefaultMaxIncludeDepth is the include nesting limit applied when DecodeOptions.MaxIncludeDepth is zero.
+	DefaultMaxIncludeDepth = 16
+	// Unlimited disables a decode limit when assigned to DecodeOptions.MaxDepth or MaxIncludeDepth.
+	Unlimited = -1
+)
+
 // EncodeOptions controls encoder behavior.
 type EncodeOptions struct {
 	// Indent sets one indentation level for text format.
 	Indent string
+	// RootSeparator replaces the blank line written between roots in pretty text output.
+	// It is written verbatim and should hold only whitespace or "//" comment lines.
+	RootSeparator string
+	// RootBanner returns comment text written before each root in text output,
+	// for example provenance headers of generated files.
+	// Every line becomes a "//" line comment; an empty result writes nothing.
+	// It has no effect on binary output.
+	RootBanner func(index int, root *Node) string
 	// Format selects output format.
 	Format Format
 	// Compact enables compact text encoding.
 	Compact bool
+	// DeterministicDepth limits Deterministic ordering to the first N levels,
+	// where roots are level 1 and their children level 2 (0 sorts every level).
+	DeterministicDepth int
+	// Collation selects key comparison for Deterministic ordering (0 compares raw bytes).
+	Collation Collation
 	// Deterministic enables stable key ordering during encode.
 	Deterministic bool
 	// Validate enables full document validation before encoding.
 	Validate bool
+	// QuoteStyle selects when text keys and scalar values are written in double quotes
+	// (0 quotes every token). Directives keep an unquoted name and a quoted path.
+	QuoteStyle QuoteStyle
+	// Encoding selects the character encoding of text output (0 writes UTF-8).
+	// It has no effect on binary output.
+	Encoding TextEncoding
+	// WriteBOM starts UTF-8 text output with the EF BB BF byte order mark.
+	// Decoding strips it, so it is not kept by a decode and encode round trip.
+	// UTF-16LE output always starts with its byte order mark.
+	WriteBOM bool
+	// DisableEscapes writes backslashes, tabs and line breaks of text tokens
+	// literally, like Valve KeyValues without UsesEscapeSequences.
+	// Tokens holding a double quote then fail with ErrValueConversion.
+	DisableEscapes bool
+	// Fidelity writes text output from the layouts recorded by DecodeOptions.Fidelity:
+	// unchanged keys, values, whitespace and comments are copied from the source,
+	// edited tokens keep their quoting where possible, and nodes without a layout
+	// follow the indentation of their siblings.
+	// It overrides Compact, Deterministic and root separators, and has no effect on binary output.
+	Fidelity bool
+	// Checksum appends a little-endian CRC32 (IEEE) of the binary payload,
+	// matching the VBKV checksum scheme. It has no effect on text output.
+	Checksum bool
+	// VBKV wraps binary output of EncodeDocument in a "VBKV" header carrying
+	// the CRC32 (IEEE) of the body. It has no effect on text output and on
+	// manual streaming writes.
+	VBKV bool
 }
 
 // Format defines how encoded/decoded VDF data should be interpreted.
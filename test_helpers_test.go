@@ -35,3 +35,15 @@ func mustReadFixtureBytes(name string) []byte {
 
 	return data
 }
+
+// mustParseString parses text VDF input or fails the test.
+func mustParseString(tb testing.TB, input string) *Document {
+	tb.Helper()
+
+	doc, err := ParseString(input)
+	if err != nil {
+		tb.Fatalf("ParseString(%q) returned error: %v", input, err)
+	}
+
+	return doc
+}
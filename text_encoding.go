@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// TextEncoding defines the character encoding of text VDF output.
+type TextEncoding uint8
+
+const (
+	// EncodingUTF8 writes UTF-8 text.
+	EncodingUTF8 TextEncoding = iota
+	// EncodingUTF16LE writes UTF-16 little-endian text with a byte order mark,
+	// as used by Source engine localization files such as tf_english.txt.
+	EncodingUTF16LE
+)
+
+var (
+	// utf8BOM is the byte order mark Windows editors write at the start of UTF-8 text.
+	utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+	// utf16LEBOM is the byte order mark of UTF-16 little-endian text.
+	utf16LEBOM = []byte{0xFF, 0xFE}
+)
+
+// decodeTextInput returns a UTF-8 view of text input without a byte order mark.
+// Input starting with a UTF-16LE byte order mark is transcoded as it is read.
+func decodeTextInput(r io.Reader) (io.Reader, error) {
+	br := ensureBufferedReader(r)
+	prefix, err := br.Peek(len(utf8BOM))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(prefix, utf8BOM):
+		if _, err := br.Discard(len(utf8BOM)); err != nil {
+			return nil, err
+		}
+
+		return br, nil
+	case bytes.HasPrefix(prefix, utf16LEBOM):
+		if _, err := br.Discard(len(utf16LEBOM)); err != nil {
+			return nil, err
+		}
+
+		return &utf16Reader{r: br}, nil
+	default:
+		return br, nil
+	}
+}
+
+// bomWriter writes a byte order mark before the first output.
+type bomWriter struct {
+	w       io.Writer // Destination.
+	bom     []byte    // Byte order mark to write first.
+	started bool      // Whether the byte order mark was written.
+}
+
+// Write implements io.Writer.
+func (b *bomWriter) Write(p []byte) (int, error) {
+	if !b.started {
+		b.started = true
+		if _, err := b.w.Write(b.bom); err != nil {
+			return 0, err
+		}
+	}
+
+	return b.w.Write(p)
+}
+
+// utf16Reader transcodes UTF-16LE input to UTF-8.
+// Unpaired surrogates decode as U+FFFD.
+type utf16Reader struct {
+	r       *bufio.Reader // UTF-16LE source after the byte order mark.
+	out     []byte        // Transcoded bytes not yet returned.
+	unit    uint16        // Code unit read ahead while pairing surrogates.
+	hasUnit bool          // Whether unit is set.
+}
+
+// Read implements io.Reader.
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	for len(u.out) == 0 {
+		if err := u.fill(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, u.out)
+	u.out = u.out[n:]
+	return n, nil
+}
+
+// fill transcodes up to a buffer of code units into out.
+func (u *utf16Reader) fill() error {
+	u.out = u.out[:0]
+	for len(u.out) < 4096 {
+		first, err := u.readUnit()
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(u.out) > 0 {
+				return nil
+			}
+
+			return err
+		}
+
+		r := rune(first)
+		if utf16.IsSurrogate(r) {
+			second, err := u.readUnit()
+			switch {
+			case err == nil:
+				if r = utf16.DecodeRune(r, rune(second)); r == utf8.RuneError {
+					// Not a pair: the second unit starts the next rune.
+					u.unit, u.hasUnit = second, true
+				}
+			case errors.Is(err, io.EOF):
+				r = utf8.RuneError
+			default:
+				return err
+			}
+		}
+
+		u.out = utf8.AppendRune(u.out, r)
+	}
+
+	return nil
+}
+
+// readUnit reads one little-endian code unit.
+func (u *utf16Reader) readUnit() (uint16, error) {
+	if u.hasUnit {
+		u.hasUnit = false
+		return u.unit, nil
+	}
+
+	var buf [2]byte
+	n, err := io.ReadFull(u.r, buf[:])
+	if n == 1 {
+		return 0, fmt.Errorf("%w: odd byte count in UTF-16 input", io.ErrUnexpectedEOF)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint16(buf[:]), nil
+}
+
+// utf16Writer transcodes UTF-8 output to UTF-16LE, writing the byte order mark first.
+// Invalid UTF-8 is written as U+FFFD; an incomplete sequence left at the end
+// is written as U+FFFD by flush.
+type utf16Writer struct {
+	w       io.Writer // UTF-16LE destination.
+	pending []byte    // Incomplete UTF-8 sequence from the previous write.
+	buf     []byte    // Reused transcoding buffer.
+	started bool      // Whether the byte order mark was written.
+}
+
+// Write implements io.Writer.
+func (u *utf16Writer) Write(p []byte) (int, error) {
+	u.buf = u.buf[:0]
+	if !u.started {
+		u.started = true
+		u.buf = append(u.buf, utf16LEBOM...)
+	}
+
+	data := p
+	if len(u.pending) > 0 {
+		data = append(u.pending, p...)
+		u.pending = nil
+	}
+
+	for len(data) > 0 {
+		if !utf8.FullRune(data) {
+			u.pending = append([]byte(nil), data...)
+			break
+		}
+
+		r, size := utf8.DecodeRune(data)
+		data = data[size:]
+		if high, low := utf16.EncodeRune(r); high != utf8.RuneError {
+			u.buf = binary.LittleEndian.AppendUint16(u.buf, uint16(high))
+			u.buf = binary.LittleEndian.AppendUint16(u.buf, uint16(low))
+			continue
+		}
+
+		u.buf = binary.LittleEndian.AppendUint16(u.buf, uint16(r))
+	}
+
+	if _, err := u.w.Write(u.buf); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// flush writes an incomplete trailing UTF-8 sequence as U+FFFD.
+func (u *utf16Writer) flush() error {
+	if len(u.pending) == 0 {
+		return nil
+	}
+
+	u.pending = nil
+	_, err := u.Write([]byte(string(utf8.RuneError)))
+	return err
+}
@@ -0,0 +1,92 @@
+package vdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestUTF16LERoundTrip(t *testing.T) {
+	t.Parallel()
+
+	text := "\"lang\"\n{\n\t\"Tokens\"\n\t{\n\t\t\"greeting\"\t\t\"Привет 🎮\"\n\t}\n}\n"
+	input := append([]byte{0xFF, 0xFE}, encodeUTF16LE(text)...)
+
+	for _, format := range []Format{FormatAuto, FormatText} {
+		doc, err := ParseBytes(input, DecodeOptions{Format: format})
+		if err != nil {
+			t.Fatalf("ParseBytes(format %d) returned error: %v", format, err)
+		}
+
+		node, err := doc.lookupPath("lang/Tokens/greeting")
+		if err != nil || *node.StringValue != "Привет 🎮" {
+			t.Fatalf("greeting = %v, %v", node, err)
+		}
+	}
+
+	doc := mustParseString(t, text)
+	out, err := AppendText(nil, doc, EncodeOptions{Encoding: EncodingUTF16LE})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	if !bytes.Equal(out, input) {
+		t.Fatalf("AppendText(UTF-16LE) = %x, want %x", out, input)
+	}
+}
+
+func TestUTF16WriterTruncatedTail(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	w := &utf16Writer{w: &out}
+	for _, chunk := range []string{"a\xD0", "\x9F", "\xF0\x9F\x8E"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Write(%q) returned error: %v", chunk, err)
+		}
+	}
+
+	if err := w.flush(); err != nil {
+		t.Fatalf("flush() returned error: %v", err)
+	}
+
+	want := append([]byte{0xFF, 0xFE}, encodeUTF16LE("aП\uFFFD")...)
+	if !bytes.Equal(out.Bytes(), want) {
+		t.Fatalf("utf16Writer output = %x, want %x", out.Bytes(), want)
+	}
+}
+
+func encodeUTF16LE(s string) []byte {
+	var out []byte
+	for _, unit := range utf16.Encode([]rune(s)) {
+		out = binary.LittleEndian.AppendUint16(out, unit)
+	}
+
+	return out
+}
+
+func TestUTF8BOM(t *testing.T) {
+	t.Parallel()
+
+	input := "\xEF\xBB\xBF\"root\"\n{\n\t\"key\"\t\t\"value\"\n}\n"
+	for _, format := range []Format{FormatAuto, FormatText} {
+		doc, err := ParseBytes([]byte(input), DecodeOptions{Format: format})
+		if err != nil {
+			t.Fatalf("ParseBytes(format %d) returned error: %v", format, err)
+		}
+
+		if got := doc.Roots[0].Key; got != "root" {
+			t.Fatalf("root key = %q, want %q", got, "root")
+		}
+
+		out, err := AppendText(nil, doc, EncodeOptions{WriteBOM: true})
+		if err != nil {
+			t.Fatalf("AppendText() returned error: %v", err)
+		}
+
+		if string(out) != input {
+			t.Fatalf("AppendText(WriteBOM) = %q, want %q", out, input)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"errors"
+	"io"
+)
+
+// TranscodeOptions controls Transcode input and output.
+type TranscodeOptions struct {
+	// Decode selects the input format and stream limits.
+	Decode DecodeOptions
+	// Encode selects the output format and text layout.
+	Encode EncodeOptions
+}
+
+// Transcode converts VDF from src to dst event by event, for example text to
+// binary or pretty to compact text, without building a Document, so memory use
+// is bounded by nesting depth, not input size.
+// Output follows manual Encoder streaming: Deterministic ordering, Validate,
+// root banners and separators, Fidelity and VBKV wrapping do not apply.
+func Transcode(dst io.Writer, src io.Reader, opts TranscodeOptions) error {
+	dec := NewDecoder(src, opts.Decode)
+	enc := NewEncoder(dst, opts.Encode)
+	for {
+		event, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := enc.WriteEvent(event); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package vdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTranscode(t *testing.T) {
+	t.Parallel()
+
+	input := `"root" { "name" "srv" "nested" { "port" "2302" } } "other" "1"`
+	doc := mustParseString(t, input)
+
+	var bin bytes.Buffer
+	err := Transcode(&bin, strings.NewReader(input), TranscodeOptions{
+		Decode: DecodeOptions{Format: FormatText},
+		Encode: EncodeOptions{Format: FormatBinary},
+	})
+	if err != nil {
+		t.Fatalf("Transcode(text to binary) returned error: %v", err)
+	}
+
+	want, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	if !bytes.Equal(bin.Bytes(), want) {
+		t.Fatalf("Transcode(text to binary) = %x, want %x", bin.Bytes(), want)
+	}
+
+	var text bytes.Buffer
+	err = Transcode(&text, bytes.NewReader(want), TranscodeOptions{
+		Encode: EncodeOptions{Format: FormatText, Compact: true},
+	})
+	if err != nil {
+		t.Fatalf("Transcode(binary to text) returned error: %v", err)
+	}
+
+	if got := text.String(); got != input+" " {
+		t.Fatalf("Transcode(binary to text) = %q, want %q", got, input+" ")
+	}
+}
+
+func TestTranscodeKeepsConditions(t *testing.T) {
+	t.Parallel()
+
+	input := `"root" { "a" "1" [$WIN32] "a" "2" [$OSX] "sub" [!$X360] { "b" "3" } }`
+	doc := mustParseString(t, input)
+
+	var text bytes.Buffer
+	err := Transcode(&text, strings.NewReader(input), TranscodeOptions{
+		Decode: DecodeOptions{Format: FormatText},
+		Encode: EncodeOptions{Format: FormatText, Compact: true},
+	})
+	if err != nil {
+		t.Fatalf("Transcode returned error: %v", err)
+	}
+
+	want := `"root" { "a" "1" [$WIN32] "a" "2" [$OSX] "sub" [!$X360] { "b" "3" } } `
+	if text.String() != want {
+		t.Fatalf("Transcode = %q, want %q", text.String(), want)
+	}
+
+	var roots []*Node
+	dec := NewDecoder(strings.NewReader(input), DecodeOptions{Format: FormatText})
+	if err := dec.DecodeRoots(func(root *Node) error {
+		roots = append(roots, root)
+		return nil
+	}); err != nil {
+		t.Fatalf("DecodeRoots returned error: %v", err)
+	}
+
+	streamed := NewDocument()
+	streamed.Roots = roots
+	if !streamed.Equal(doc) || streamed.Get("root/sub").Condition != "!$X360" || streamed.Get("root/a").Condition != "$WIN32" {
+		t.Fatalf("DecodeRoots lost conditionals: %+v", roots[0].Children)
+	}
+}
@@ -6,7 +6,9 @@ package vdf
 
 import (
 	"fmt"
+	"maps"
 	"math"
+	"slices"
 	"strconv"
 )
 
@@ -53,6 +55,79 @@ func NewUint32Node(key string, value uint32) *Node {
 	}
 }
 
+// NewUint64Node creates a uint64 leaf node.
+func NewUint64Node(key string, value uint64) *Node {
+	return &Node{
+		Key:         key,
+		Kind:        NodeUint64,
+		Uint64Value: &value,
+	}
+}
+
+// NewInt64Node creates an int64 leaf node.
+func NewInt64Node(key string, value int64) *Node {
+	return &Node{
+		Key:        key,
+		Kind:       NodeInt64,
+		Int64Value: &value,
+	}
+}
+
+// NewFloat32Node creates a float32 leaf node.
+func NewFloat32Node(key string, value float32) *Node {
+	return &Node{
+		Key:          key,
+		Kind:         NodeFloat32,
+		Float32Value: &value,
+	}
+}
+
+// NewPointerNode creates a pointer leaf node holding a 32-bit pointer value.
+func NewPointerNode(key string, value uint32) *Node {
+	return &Node{
+		Key:         key,
+		Kind:        NodePointer,
+		Uint32Value: &value,
+	}
+}
+
+// NewWideStringNode creates a string leaf node that is encoded as UTF-16LE in binary form.
+func NewWideStringNode(key, value string) *Node {
+	return &Node{
+		Key:         key,
+		Kind:        NodeWideString,
+		StringValue: &value,
+	}
+}
+
+// NewColorNode creates a color leaf node.
+func NewColorNode(key string, value Color) *Node {
+	return &Node{
+		Key:        key,
+		Kind:       NodeColor,
+		ColorValue: &value,
+	}
+}
+
+// NewDirectiveNode creates an "#include" or "#base" directive node; name includes the "#".
+func NewDirectiveNode(name, path string) *Node {
+	return &Node{
+		Key:         name,
+		Kind:        NodeDirective,
+		StringValue: &path,
+	}
+}
+
+// NewRawNode creates a binary-only raw leaf with the provided type byte and payload.
+func NewRawNode(key string, rawType byte, payload []byte) *Node {
+	return &Node{
+		Key:      key,
+		Kind:     NodeRaw,
+		RawType:  rawType,
+		RawValue: payload,
+	}
+}
+
 // Add appends a child node to an object node.
 func (n *Node) Add(child *Node) {
 	if n == nil || n.Kind != NodeObject || child == nil {
@@ -102,6 +177,31 @@ func (d *Document) AddRoot(node *Node) {
 	d.Roots = append(d.Roots, node)
 }
 
+// Clone returns a deep copy of n and its subtree with the same kinds, values,
+// conditionals and child order, sharing no mutable state with n.
+// Text layout recorded by DecodeOptions.Fidelity is kept for encoding;
+// spans and positions, which describe the decoded input, are not copied.
+func (n *Node) Clone() *Node {
+	return cloneNode(n)
+}
+
+// Clone returns a deep copy of d whose roots are cloned with Node.Clone.
+func (d *Document) Clone() *Document {
+	if d == nil {
+		return nil
+	}
+
+	out := &Document{Layout: d.Layout, Format: d.Format}
+	if d.Roots != nil {
+		out.Roots = make([]*Node, len(d.Roots))
+		for i, root := range d.Roots {
+			out.Roots[i] = cloneNode(root)
+		}
+	}
+
+	return out
+}
+
 // Validate ensures document and node invariants are satisfied.
 func (d *Document) Validate() error {
 	if d == nil {
@@ -193,34 +293,58 @@ func validateNode(node *Node, seen map[*Node]struct{}) error {
 
 	switch node.Kind {
 	case NodeObject:
-		if node.StringValue != nil || node.Uint32Value != nil {
+		if leafPayloadCount(node) != 0 {
 			return fmt.Errorf("%w: object %q has scalar payload", ErrInvalidNodeState, node.Key)
 		}
 
 		for i, child := range node.Children {
+			if child != nil && child.Kind == NodeDirective {
+				return fmt.Errorf("%w: directive %q inside object %q", ErrInvalidNodeState, child.Key, node.Key)
+			}
+
 			if err := validateNode(child, seen); err != nil {
 				return fmt.Errorf("child[%d]: %w", i, err)
 			}
 		}
 
 	case NodeString:
-		if node.StringValue == nil {
-			return fmt.Errorf("%w: string node %q missing value", ErrInvalidNodeState, node.Key)
-		}
-
-		if node.Uint32Value != nil || len(node.Children) != 0 {
-			return fmt.Errorf("%w: string node %q has invalid extra data", ErrInvalidNodeState, node.Key)
-		}
+		return validateLeaf(node, "string", node.StringValue != nil)
 
 	case NodeUint32:
-		if node.Uint32Value == nil {
-			return fmt.Errorf("%w: uint32 node %q missing value", ErrInvalidNodeState, node.Key)
+		return validateLeaf(node, "uint32", node.Uint32Value != nil)
+
+	case NodeFloat32:
+		return validateLeaf(node, "float32", node.Float32Value != nil)
+
+	case NodePointer:
+		return validateLeaf(node, "pointer", node.Uint32Value != nil)
+
+	case NodeWideString:
+		return validateLeaf(node, "wide string", node.StringValue != nil)
+
+	case NodeDirective:
+		return validateLeaf(node, "directive", node.StringValue != nil)
+
+	case NodeColor:
+		return validateLeaf(node, "color", node.ColorValue != nil)
+
+	case NodeUint64:
+		return validateLeaf(node, "uint64", node.Uint64Value != nil)
+
+	case NodeInt64:
+		return validateLeaf(node, "int64", node.Int64Value != nil)
+
+	case NodeRaw:
+		if node.RawValue == nil {
+			return fmt.Errorf("%w: raw node %q missing payload", ErrInvalidNodeState, node.Key)
 		}
 
-		if node.StringValue != nil || len(node.Children) != 0 {
-			return fmt.Errorf("%w: uint32 node %q has invalid extra data", ErrInvalidNodeState, node.Key)
+		if isBuiltinBinaryType(node.RawType) {
+			return fmt.Errorf("%w: raw node %q uses built-in type byte 0x%02x", ErrInvalidNodeState, node.Key, node.RawType)
 		}
 
+		return validateLeaf(node, "raw", true)
+
 	default:
 		return fmt.Errorf("%w: unknown node kind %d", ErrInvalidNodeState, node.Kind)
 	}
@@ -228,15 +352,42 @@ func validateNode(node *Node, seen map[*Node]struct{}) error {
 	return nil
 }
 
+// validateLeaf checks that a leaf carries its own payload and nothing else.
+func validateLeaf(node *Node, name string, hasValue bool) error {
+	if !hasValue {
+		return fmt.Errorf("%w: %s node %q missing value", ErrInvalidNodeState, name, node.Key)
+	}
+
+	if leafPayloadCount(node) != 1 || len(node.Children) != 0 {
+		return fmt.Errorf("%w: %s node %q has invalid extra data", ErrInvalidNodeState, name, node.Key)
+	}
+
+	return nil
+}
+
+// leafPayloadCount returns the number of scalar payload fields set on node.
+func leafPayloadCount(node *Node) int {
+	count := 0
+	for _, set := range [...]bool{
+		node.StringValue != nil,
+		node.Uint32Value != nil,
+		node.Float32Value != nil,
+		node.ColorValue != nil,
+		node.Uint64Value != nil,
+		node.Int64Value != nil,
+		node.RawValue != nil,
+	} {
+		if set {
+			count++
+		}
+	}
+
+	return count
+}
+
 // nodeToStrictValue converts a node to map-friendly value with duplicate detection.
 func nodeToStrictValue(node *Node) (any, error) {
 	switch node.Kind {
-	case NodeString:
-		return *node.StringValue, nil
-
-	case NodeUint32:
-		return *node.Uint32Value, nil
-
 	case NodeObject:
 		m := Map{}
 		for _, child := range node.Children {
@@ -254,19 +405,22 @@ func nodeToStrictValue(node *Node) (any, error) {
 		return m, nil
 
 	default:
-		return nil, fmt.Errorf("%w: unknown node kind %d", ErrInvalidNodeState, node.Kind)
+		if value := node.Value(); value != nil {
+			return value, nil
+		}
+
+		// Validation names the kind and key of a leaf without its value.
+		if err := validateNode(node, map[*Node]struct{}{}); err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w: node %q has no value", ErrInvalidNodeState, node.Key)
 	}
 }
 
 // nodeToLossyValue converts a node to map-friendly value with last-write-wins semantics.
 func nodeToLossyValue(node *Node) any {
 	switch node.Kind {
-	case NodeString:
-		return *node.StringValue
-
-	case NodeUint32:
-		return *node.Uint32Value
-
 	case NodeObject:
 		m := Map{}
 		for _, child := range node.Children {
@@ -275,15 +429,16 @@ func nodeToLossyValue(node *Node) any {
 		return m
 
 	default:
-		return nil
+		return node.Value()
 	}
 }
 
 // mapToNodeChildren converts map entries to ordered node children.
+// Keys are sorted so the produced child order is deterministic.
 func mapToNodeChildren(m Map) ([]*Node, error) {
 	children := make([]*Node, 0, len(m))
-	for key, value := range m {
-		node, err := mapValueToNode(key, value)
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		node, err := mapValueToNode(key, m[key])
 		if err != nil {
 			return nil, err
 		}
@@ -303,6 +458,15 @@ func mapValueToNode(key string, value any) (*Node, error) {
 	case uint32:
 		return NewUint32Node(key, val), nil
 
+	case uint64:
+		return NewUint64Node(key, val), nil
+
+	case float32:
+		return NewFloat32Node(key, val), nil
+
+	case Color:
+		return NewColorNode(key, val), nil
+
 	case int:
 		if val < 0 || val > math.MaxUint32 {
 			return nil, fmt.Errorf("%w: key %q int=%d", ErrIntOutOfRange, key, val)
@@ -338,21 +502,50 @@ func mapValueToNode(key string, value any) (*Node, error) {
 	}
 }
 
+// formatFloat32 formats a float32 with the shortest decimal text that parses back exactly.
+func formatFloat32(value float32) string {
+	return strconv.FormatFloat(float64(value), 'f', -1, 32)
+}
+
 // textValueForNode converts leaf nodes to textual value for text format writer.
 func textValueForNode(node *Node) (string, error) {
 	switch node.Kind {
-	case NodeString:
+	case NodeString, NodeWideString, NodeDirective:
 		if node.StringValue == nil {
 			return "", fmt.Errorf("%w: string node %q missing value", ErrInvalidNodeState, node.Key)
 		}
 		return *node.StringValue, nil
 
-	case NodeUint32:
+	case NodeUint32, NodePointer:
 		if node.Uint32Value == nil {
 			return "", fmt.Errorf("%w: uint32 node %q missing value", ErrInvalidNodeState, node.Key)
 		}
 		return strconv.FormatUint(uint64(*node.Uint32Value), 10), nil
 
+	case NodeUint64:
+		if node.Uint64Value == nil {
+			return "", fmt.Errorf("%w: uint64 node %q missing value", ErrInvalidNodeState, node.Key)
+		}
+		return strconv.FormatUint(*node.Uint64Value, 10), nil
+
+	case NodeInt64:
+		if node.Int64Value == nil {
+			return "", fmt.Errorf("%w: int64 node %q missing value", ErrInvalidNodeState, node.Key)
+		}
+		return strconv.FormatInt(*node.Int64Value, 10), nil
+
+	case NodeFloat32:
+		if node.Float32Value == nil {
+			return "", fmt.Errorf("%w: float32 node %q missing value", ErrInvalidNodeState, node.Key)
+		}
+		return formatFloat32(*node.Float32Value), nil
+
+	case NodeColor:
+		if node.ColorValue == nil {
+			return "", fmt.Errorf("%w: color node %q missing value", ErrInvalidNodeState, node.Key)
+		}
+		return node.ColorValue.String(), nil
+
 	default:
 		return "", fmt.Errorf("%w: node %q kind=%d cannot be formatted as text leaf", ErrInvalidNodeState, node.Key, node.Kind)
 	}
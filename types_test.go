@@ -2,6 +2,7 @@ package vdf
 
 import (
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -110,6 +111,12 @@ func TestToMapStrictAndLossy(t *testing.T) {
 	if got := rootVal["dup"]; got != "second" {
 		t.Fatalf("lossy duplicate value = %#v, want %#v", got, "second")
 	}
+
+	root.Children[1] = &Node{Key: "port", Kind: NodeUint32}
+	_, err := doc.ToMapStrict()
+	if want := `uint32 node "port" missing value`; !errors.Is(err, ErrInvalidNodeState) || !strings.Contains(err.Error(), want) {
+		t.Fatalf("ToMapStrict(missing value) error = %v, want %q", err, want)
+	}
 }
 
 func TestFromMap(t *testing.T) {
@@ -139,3 +146,36 @@ func TestFromMap(t *testing.T) {
 		t.Fatalf("FromMap(unsupported) error = %v, want ErrUnsupportedMapValueType", err)
 	}
 }
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"root" { "name" "a" "sub" { "x" "1" } "name" "b" }`)
+	doc.Roots[0].Add(NewRawNode("raw", 0x7F, []byte{1, 2}))
+	doc.Roots[0].Add(NewColorNode("color", Color{R: 1, G: 2, B: 3, A: 4}))
+
+	clone := doc.Clone()
+	if !clone.Equal(doc) || clone.Format != doc.Format {
+		t.Fatal("Clone() is not equal to the source")
+	}
+
+	*clone.Roots[0].First("name").StringValue = "changed"
+	clone.Roots[0].First("sub").Add(NewStringNode("y", "2"))
+	clone.Roots[0].First("raw").RawValue[0] = 9
+	clone.Roots[0].First("color").ColorValue.R = 9
+
+	source := doc.Roots[0]
+	if *source.First("name").StringValue != "a" || source.First("sub").Len() != 1 ||
+		source.First("raw").RawValue[0] != 1 || source.First("color").ColorValue.R != 1 {
+		t.Fatal("editing the clone changed the source document")
+	}
+
+	if node := source.First("sub").Clone(); node == source.First("sub") || !node.Equal(source.First("sub")) {
+		t.Fatal("Node.Clone() did not return an equal copy")
+	}
+
+	var missing *Document
+	if missing.Clone() != nil {
+		t.Fatal("Clone() of nil document is not nil")
+	}
+}
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+// leafEventTypes maps leaf node kinds to the events that carry them.
+var leafEventTypes = map[NodeKind]EventType{
+	NodeString:     EventString,
+	NodeWideString: EventWideString,
+	NodeUint32:     EventUint32,
+	NodePointer:    EventPointer,
+	NodeUint64:     EventUint64,
+	NodeInt64:      EventInt64,
+	NodeFloat32:    EventFloat32,
+	NodeColor:      EventColor,
+	NodeDirective:  EventDirective,
+	NodeRaw:        EventRaw,
+}
+
+// IsLeaf reports whether kind is a scalar or raw leaf kind.
+func (k NodeKind) IsLeaf() bool {
+	_, leaf := leafEventTypes[k]
+	return leaf
+}
+
+// Value returns the leaf payload as a Go value: string for NodeString,
+// NodeWideString and NodeDirective, uint32 for NodeUint32 and NodePointer, uint64, int64,
+// float32, Color, or []byte for NodeRaw.
+// It returns nil for objects and for leaves missing their payload.
+func (n *Node) Value() any {
+	if n == nil {
+		return nil
+	}
+
+	switch n.Kind {
+	case NodeString, NodeWideString, NodeDirective:
+		return derefValue(n.StringValue)
+	case NodeUint32, NodePointer:
+		return derefValue(n.Uint32Value)
+	case NodeUint64:
+		return derefValue(n.Uint64Value)
+	case NodeInt64:
+		return derefValue(n.Int64Value)
+	case NodeFloat32:
+		return derefValue(n.Float32Value)
+	case NodeColor:
+		return derefValue(n.ColorValue)
+	case NodeRaw:
+		if n.RawValue == nil {
+			return nil
+		}
+
+		return n.RawValue
+	default:
+		return nil
+	}
+}
+
+// Value returns the leaf payload of a scalar event as a Go value, using the
+// same types as Node.Value. It returns nil for document and object events.
+func (e Event) Value() any {
+	switch e.Type {
+	case EventString, EventWideString, EventDirective:
+		return derefValue(e.StringValue)
+	case EventUint32, EventPointer:
+		return derefValue(e.Uint32Value)
+	case EventUint64:
+		return derefValue(e.Uint64Value)
+	case EventInt64:
+		return derefValue(e.Int64Value)
+	case EventFloat32:
+		return derefValue(e.Float32Value)
+	case EventColor:
+		return derefValue(e.ColorValue)
+	case EventRaw:
+		if e.RawValue == nil {
+			return nil
+		}
+
+		return e.RawValue
+	default:
+		return nil
+	}
+}
+
+// leafEvent returns the scalar event for node, sharing its payload pointers.
+func leafEvent(node *Node, depth int) (Event, bool) {
+	eventType, ok := leafEventTypes[node.Kind]
+	if !ok {
+		return Event{}, false
+	}
+
+	return Event{
+		Type:         eventType,
+		Key:          node.Key,
+		Condition:    node.Condition,
+		Depth:        depth,
+		StringValue:  node.StringValue,
+		Uint32Value:  node.Uint32Value,
+		Uint64Value:  node.Uint64Value,
+		Int64Value:   node.Int64Value,
+		Float32Value: node.Float32Value,
+		ColorValue:   node.ColorValue,
+		RawValue:     node.RawValue,
+		RawType:      node.RawType,
+	}, true
+}
+
+// eventLeafNode returns the scalar node for a leaf event, sharing its payload pointers.
+func eventLeafNode(event Event) (*Node, bool) {
+	for kind, eventType := range leafEventTypes {
+		if eventType == event.Type {
+			return &Node{
+				Key:          event.Key,
+				Condition:    event.Condition,
+				Kind:         kind,
+				StringValue:  event.StringValue,
+				Uint32Value:  event.Uint32Value,
+				Uint64Value:  event.Uint64Value,
+				Int64Value:   event.Int64Value,
+				Float32Value: event.Float32Value,
+				ColorValue:   event.ColorValue,
+				RawValue:     event.RawValue,
+				RawType:      event.RawType,
+			}, true
+		}
+	}
+
+	return nil, false
+}
+
+// derefValue returns *p as any, or nil when p is nil.
+func derefValue[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+
+	return *p
+}
@@ -0,0 +1,77 @@
+package vdf
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestNodeValue(t *testing.T) {
+	t.Parallel()
+
+	leaves := []*Node{
+		NewStringNode("s", "text"),
+		NewWideStringNode("w", "wide"),
+		NewUint32Node("u32", 7),
+		NewPointerNode("ptr", 8),
+		NewUint64Node("u64", 1<<40),
+		NewInt64Node("i64", -9),
+		NewFloat32Node("f32", 0.5),
+		NewColorNode("c", Color{R: 1, G: 2, B: 3, A: 4}),
+		NewRawNode("raw", 0x42, []byte{1}),
+	}
+
+	want := []any{"text", "wide", uint32(7), uint32(8), uint64(1 << 40), int64(-9), float32(0.5), Color{R: 1, G: 2, B: 3, A: 4}, []byte{1}}
+
+	doc := NewDocumentWithFormat(FormatBinary)
+	root := NewObjectNode("root")
+	for i, leaf := range leaves {
+		if !leaf.Kind.IsLeaf() {
+			t.Fatalf("%s: IsLeaf() = false", leaf.Key)
+		}
+
+		if got := leaf.Value(); !reflect.DeepEqual(got, want[i]) {
+			t.Fatalf("%s: Value() = %#v, want %#v", leaf.Key, got, want[i])
+		}
+
+		root.Add(leaf)
+	}
+	doc.AddRoot(root)
+
+	if root.Value() != nil || NodeObject.IsLeaf() {
+		t.Fatal("object node reports a leaf value")
+	}
+
+	data, err := AppendBinary(nil, doc, EncodeOptions{})
+	if err != nil {
+		t.Fatalf("AppendBinary() returned error: %v", err)
+	}
+
+	dec := NewDecoder(bytes.NewReader(data), DecodeOptions{Format: FormatBinary, RawTypeSizes: map[byte]int{0x42: 1}, PreserveUnknownTypes: true})
+	var values []any
+	for {
+		event, err := dec.NextEvent()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("NextEvent() returned error: %v", err)
+		}
+
+		if value := event.Value(); value != nil {
+			values = append(values, value)
+		}
+	}
+
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("event values = %#v, want %#v", values, want)
+	}
+
+	m := doc.ToMapLossy()["root"].(Map)
+	if m["ptr"] != uint32(8) || m["i64"] != int64(-9) {
+		t.Fatalf("ToMapLossy() = %#v", m)
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// vbkvMagic starts binary payloads wrapped in a VBKV header.
+const vbkvMagic = "VBKV"
+
+// vbkvHeaderSize is the byte length of the magic and the CRC32 of the body.
+const vbkvHeaderSize = 8
+
+// stripVBKVHeader consumes a VBKV header when r starts with one and returns
+// the reader positioned at the body with the stored body CRC32.
+// The magic cannot be mistaken for binary VDF, which never starts with 'V'.
+func stripVBKVHeader(r binaryReadReader) (binaryReadReader, uint32, bool, error) {
+	scanner, ok := r.(io.ByteScanner)
+	if !ok {
+		br := bufio.NewReader(r)
+		scanner, r = br, br
+	}
+
+	first, err := scanner.ReadByte()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return r, 0, false, nil
+		}
+
+		return nil, 0, false, err
+	}
+
+	if first != vbkvMagic[0] {
+		if err := scanner.UnreadByte(); err != nil {
+			return nil, 0, false, err
+		}
+
+		return r, 0, false, nil
+	}
+
+	var rest [vbkvHeaderSize - 1]byte
+	if _, err := io.ReadFull(r, rest[:]); err != nil {
+		return nil, 0, false, ErrBufferOverflow
+	}
+
+	if string(rest[:3]) != vbkvMagic[1:] {
+		return nil, 0, false, fmt.Errorf("%w: 0x%02x", ErrUnrecognizedType, first)
+	}
+
+	return r, binary.LittleEndian.Uint32(rest[3:]), true, nil
+}
+
+// encodeVBKVDocument writes doc as binary VDF wrapped in a VBKV header.
+// The body is buffered because the header carries its checksum.
+func encodeVBKVDocument(w io.Writer, doc *Document, opts EncodeOptions) error {
+	var body bytes.Buffer
+	body.Grow(estimateBinaryDocumentSize(doc))
+
+	opts.VBKV = false
+	if err := encodeBinaryDocument(&body, doc, opts); err != nil {
+		return err
+	}
+
+	var header [vbkvHeaderSize]byte
+	copy(header[:], vbkvMagic)
+	binary.LittleEndian.PutUint32(header[4:], crc32.ChecksumIEEE(body.Bytes()))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+
+	_, err := w.Write(body.Bytes())
+	return err
+}
+
+// updateVBKVChecksum rewrites the header CRC32 of a VBKV-wrapped file of the
+// given size after its body was edited in place. Unwrapped files are left as is.
+func updateVBKVChecksum(f *os.File, size int64) error {
+	var magic [len(vbkvMagic)]byte
+	n, err := f.ReadAt(magic[:], 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if string(magic[:n]) != vbkvMagic {
+		return nil
+	}
+
+	sum := crc32.NewIEEE()
+	if _, err := io.Copy(sum, io.NewSectionReader(f, vbkvHeaderSize, size-vbkvHeaderSize)); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var crc [4]byte
+	binary.LittleEndian.PutUint32(crc[:], sum.Sum32())
+	if _, err := f.WriteAt(crc[:], int64(len(vbkvMagic))); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
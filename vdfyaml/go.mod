@@ -0,0 +1,10 @@
+module github.com/woozymasta/vdf/vdfyaml
+
+go 1.25.5
+
+require (
+	github.com/woozymasta/vdf v0.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+replace github.com/woozymasta/vdf => ../
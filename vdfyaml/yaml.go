@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+/*
+Package vdfyaml converts VDF documents to and from YAML in their natural
+nested shape, keeping key order through yaml.Node.
+
+It is a separate module, github.com/woozymasta/vdf/vdfyaml, so the core
+module stays free of dependencies.
+Conversion goes through the order-preserving JSON form of package vdf, so
+duplicate keys, numbers and booleans follow the same policies as
+Document.ToJSON and vdf.FromJSON.
+*/
+package vdfyaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/woozymasta/vdf"
+	"gopkg.in/yaml.v3"
+)
+
+// Options controls YAML conversion.
+type Options struct {
+	// Indent is the number of spaces per nesting level; zero uses 2.
+	Indent int
+	// Duplicates selects how repeated keys are represented.
+	Duplicates vdf.JSONDuplicates
+	// Numbers selects how FromYAML and FromNode map numbers.
+	Numbers vdf.JSONNumbers
+	// Bools selects how FromYAML and FromNode map booleans.
+	Bools vdf.JSONBools
+}
+
+// jsonOptions returns the matching JSON conversion options.
+func (o Options) jsonOptions() vdf.JSONOptions {
+	return vdf.JSONOptions{Duplicates: o.Duplicates, Numbers: o.Numbers, Bools: o.Bools}
+}
+
+// ToNode converts the document to a YAML mapping node in block style,
+// one member per root in source order.
+func ToNode(doc *vdf.Document, opts Options) (*yaml.Node, error) {
+	data, err := doc.ToJSON(opts.jsonOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is valid YAML, so decoding it keeps member order and scalar tags.
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("%w: %w", vdf.ErrValueConversion, err)
+	}
+
+	root := node.Content[0]
+	clearStyle(root)
+	return root, nil
+}
+
+// ToYAML encodes the document as a YAML document.
+func ToYAML(doc *vdf.Document, opts Options) ([]byte, error) {
+	node, err := ToNode(doc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	indent := opts.Indent
+	if indent <= 0 {
+		indent = 2
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indent)
+	if err := enc.Encode(node); err != nil {
+		return nil, fmt.Errorf("%w: %w", vdf.ErrValueConversion, err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("%w: %w", vdf.ErrValueConversion, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// FromNode builds a document from a YAML mapping node or a document node
+// holding one. Aliases are resolved; mapping keys must be scalars.
+func FromNode(node *yaml.Node, opts Options) (*vdf.Document, error) {
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+		node = node.Content[0]
+	}
+
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%w: top-level YAML value is not a mapping", vdf.ErrInvalidJSON)
+	}
+
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, node); err != nil {
+		return nil, err
+	}
+
+	return vdf.FromJSON(buf.Bytes(), opts.jsonOptions())
+}
+
+// FromYAML builds a document from a YAML document whose top level is a mapping.
+func FromYAML(data []byte, opts Options) (*vdf.Document, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("%w: %w", vdf.ErrInvalidJSON, err)
+	}
+
+	return FromNode(&node, opts)
+}
+
+// clearStyle switches a node tree decoded from JSON to block style with plain scalars,
+// leaving the encoder to quote strings that would otherwise resolve to other types.
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
+
+// writeJSON writes a YAML node tree as JSON, keeping mapping order.
+func writeJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return writeJSON(buf, node.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if key.Kind != yaml.ScalarNode {
+				return fmt.Errorf("%w: non-scalar mapping key at line %d", vdf.ErrInvalidJSON, key.Line)
+			}
+
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			data, err := json.Marshal(key.Value)
+			if err != nil {
+				return err
+			}
+
+			buf.Write(data)
+			buf.WriteByte(':')
+			if err := writeJSON(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte('}')
+		return nil
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := writeJSON(buf, item); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte(']')
+		return nil
+	case yaml.ScalarNode:
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return fmt.Errorf("%w: line %d: %w", vdf.ErrInvalidJSON, node.Line, err)
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("%w: line %d: %w", vdf.ErrValueConversion, node.Line, err)
+		}
+
+		buf.Write(data)
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported YAML node kind %d at line %d", vdf.ErrInvalidJSON, node.Kind, node.Line)
+	}
+}
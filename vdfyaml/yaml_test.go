@@ -0,0 +1,85 @@
+package vdfyaml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/woozymasta/vdf"
+	"gopkg.in/yaml.v3"
+)
+
+func TestYAMLRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	doc, err := vdf.ParseBytes([]byte(`"root" { "z" "1" "tag" "a" "sub" { "x" "y" } "tag" "b" }`), vdf.DecodeOptions{Format: vdf.FormatText})
+	if err != nil {
+		t.Fatalf("ParseBytes: %v", err)
+	}
+
+	doc.Roots[0].Add(vdf.NewUint32Node("n", 440))
+
+	out, err := ToYAML(doc, Options{})
+	if err != nil {
+		t.Fatalf("ToYAML: %v", err)
+	}
+
+	want := "root:\n  z: \"1\"\n  tag:\n    - a\n    - b\n  sub:\n    x: y\n  n: 440\n"
+	if string(out) != want {
+		t.Fatalf("ToYAML =\n%s\nwant\n%s", out, want)
+	}
+
+	back, err := FromYAML(out, Options{Numbers: vdf.JSONNumberTyped})
+	if err != nil {
+		t.Fatalf("FromYAML: %v", err)
+	}
+
+	// Arrays gather repeated keys at their first position.
+	if !back.Equal(doc, vdf.EqualOptions{IgnoreOrder: true}) {
+		t.Fatalf("round trip mismatch:\n%s", out)
+	}
+}
+
+func TestFromYAML(t *testing.T) {
+	t.Parallel()
+
+	doc, err := FromYAML([]byte("base: &b\n  k: v\nroot:\n  on: yes\n  copy: *b\n  hex: 0x10\n"), Options{})
+	if err != nil {
+		t.Fatalf("FromYAML: %v", err)
+	}
+
+	if v := doc.Get("root/copy/k"); v == nil || *v.StringValue != "v" {
+		t.Fatalf("alias = %+v", v)
+	}
+
+	if v := doc.Get("root/hex"); v == nil || *v.StringValue != "16" {
+		t.Fatalf("hex = %+v", v)
+	}
+
+	if _, err := FromYAML([]byte("- a\n- b\n"), Options{}); !errors.Is(err, vdf.ErrInvalidJSON) {
+		t.Fatalf("FromYAML(sequence) error = %v", err)
+	}
+
+	if _, err := FromYAML([]byte("? [a]\n: b\n"), Options{}); !errors.Is(err, vdf.ErrInvalidJSON) {
+		t.Fatalf("FromYAML(complex key) error = %v", err)
+	}
+}
+
+func TestDocumentASTYAML(t *testing.T) {
+	t.Parallel()
+
+	doc := vdf.NewDocument()
+	root := vdf.NewObjectNode("root")
+	root.Add(vdf.NewStringNode("name", "x"))
+	doc.AddRoot(root)
+
+	out, err := yaml.Marshal(doc)
+	if err != nil || !strings.HasPrefix(string(out), "roots:\n") {
+		t.Fatalf("Marshal = %s, %v", out, err)
+	}
+
+	var back vdf.Document
+	if err := yaml.Unmarshal(out, &back); err != nil || !back.Equal(doc) {
+		t.Fatalf("Unmarshal = %+v, %v", back, err)
+	}
+}
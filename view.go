@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// View binds a struct value to an object node.
+// Load copies mapped children into Value; Save writes Value back into the node,
+// updating mapped children in place and keeping unknown children and order intact.
+// Fields map to keys by their `vdf` tag or Go name.
+type View[T any] struct {
+	// Value is the bound struct.
+	Value T
+	node  *Node
+}
+
+// Bind creates a view over an object node for struct type T.
+// The view starts with a zero Value; call Load to populate it.
+func Bind[T any](node *Node) (*View[T], error) {
+	if node == nil || node.Kind != NodeObject {
+		return nil, fmt.Errorf("%w: %q is not an object", ErrInvalidNodeState, nodeKey(node))
+	}
+
+	if t := reflect.TypeFor[T](); t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: view requires a struct, got %s", ErrUnsupportedGoType, t)
+	}
+
+	return &View[T]{node: node}, nil
+}
+
+// Node returns the bound object node.
+func (v *View[T]) Node() *Node {
+	return v.node
+}
+
+// Load populates Value from the bound node.
+// Fields without a matching child keep their current values.
+func (v *View[T]) Load() error {
+	return decodeNodeValue(v.node, reflect.ValueOf(&v.Value).Elem())
+}
+
+// Save writes Value back into the bound node.
+// Nil pointer fields are skipped and leave existing children untouched.
+func (v *View[T]) Save() error {
+	return encodeNodeValue(v.node, reflect.ValueOf(&v.Value).Elem())
+}
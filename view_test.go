@@ -0,0 +1,69 @@
+package vdf
+
+import (
+	"errors"
+	"testing"
+)
+
+type viewAppState struct {
+	Name      string `vdf:"name"`
+	Installed bool   `vdf:"installed"`
+	Config    struct {
+		Language string `vdf:"language"`
+	} `vdf:"UserConfig"`
+	AppID   uint32 `vdf:"appid"`
+	Size    int64  `vdf:"SizeOnDisk"`
+	Ignored string `vdf:"-"`
+}
+
+func TestViewLoadSave(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"AppState" { "appid" "440" "name" "TF2" "custom" "keep" "SizeOnDisk" "123" "installed" "1" "UserConfig" { "language" "english" "extra" "x" } }`)
+
+	view, err := Bind[viewAppState](doc.Roots[0])
+	if err != nil {
+		t.Fatalf("Bind() returned error: %v", err)
+	}
+
+	if err := view.Load(); err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	if view.Value.AppID != 440 || view.Value.Name != "TF2" || !view.Value.Installed || view.Value.Size != 123 || view.Value.Config.Language != "english" {
+		t.Fatalf("Load() value = %+v", view.Value)
+	}
+
+	view.Value.Name = "Team Fortress 2"
+	view.Value.Installed = false
+	view.Value.Config.Language = "german"
+	view.Value.Ignored = "never"
+
+	if err := view.Save(); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	got, err := AppendText(nil, doc, EncodeOptions{Compact: true})
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	want := `"AppState" { "appid" "440" "name" "Team Fortress 2" "custom" "keep" "SizeOnDisk" "123" "installed" "0" "UserConfig" { "language" "german" "extra" "x" } } `
+	if string(got) != want {
+		t.Fatalf("Save() document = %q, want %q", got, want)
+	}
+
+	if _, err := Bind[int](doc.Roots[0]); !errors.Is(err, ErrUnsupportedGoType) {
+		t.Fatalf("Bind[int]() error = %v, want ErrUnsupportedGoType", err)
+	}
+
+	bad := mustParseString(t, `"AppState" { "appid" "x" }`)
+	badView, err := Bind[viewAppState](bad.Roots[0])
+	if err != nil {
+		t.Fatalf("Bind() returned error: %v", err)
+	}
+
+	if err := badView.Load(); !errors.Is(err, ErrValueConversion) {
+		t.Fatalf("Load(bad) error = %v, want ErrValueConversion", err)
+	}
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Maxim Levchenko (WoozyMasta)
+// Source: github.com/woozymasta/vdf
+
+package vdf
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// SkipChildren is returned by a WalkFunc to skip the children of the visited object.
+	SkipChildren = errors.New("skip children")
+	// SkipAll is returned by a WalkFunc to stop walking without an error.
+	SkipAll = errors.New("skip all")
+)
+
+// WalkFunc visits one node during Walk. The path holds keys from the walked
+// root down to the node itself; it is reused between calls and must be copied
+// to be retained. Returning SkipChildren skips the children of an object,
+// SkipAll stops the walk, and any other error stops it and is returned by Walk.
+type WalkFunc func(path []string, node *Node) error
+
+// Walk visits every node of the document in depth-first pre-order,
+// roots first in source order. Nil nodes are skipped.
+// Traversal uses an explicit stack, so nesting depth is not limited by the goroutine stack.
+func (d *Document) Walk(fn WalkFunc) error {
+	if d == nil {
+		return fmt.Errorf("%w: nil document", ErrInvalidNodeState)
+	}
+
+	return walkNodes(d.Roots, fn)
+}
+
+// Walk visits n and its descendants in depth-first pre-order.
+// Paths passed to fn start with the key of n.
+func (n *Node) Walk(fn WalkFunc) error {
+	if n == nil {
+		return nil
+	}
+
+	return walkNodes([]*Node{n}, fn)
+}
+
+// walkFrame is one sibling list being visited by walkNodes.
+type walkFrame struct {
+	nodes []*Node // Sibling nodes.
+	index int     // Next sibling to visit.
+}
+
+// walkNodes visits nodes and their descendants in pre-order.
+func walkNodes(nodes []*Node, fn WalkFunc) error {
+	path := make([]string, 0, 8)
+	stack := []walkFrame{{nodes: nodes}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.index >= len(top.nodes) {
+			stack = stack[:len(stack)-1]
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+
+			continue
+		}
+
+		node := top.nodes[top.index]
+		top.index++
+		if node == nil {
+			continue
+		}
+
+		path = append(path, node.Key)
+		err := fn(path, node)
+		switch {
+		case errors.Is(err, SkipAll):
+			return nil
+		case errors.Is(err, SkipChildren):
+		case err != nil:
+			return err
+		case node.Kind == NodeObject && len(node.Children) > 0:
+			// The path keeps the object key until its frame is popped.
+			stack = append(stack, walkFrame{nodes: node.Children})
+			continue
+		}
+
+		path = path[:len(path)-1]
+	}
+
+	return nil
+}
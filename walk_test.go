@@ -0,0 +1,59 @@
+package vdf
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDocumentWalk(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"a" { "b" { "c" "1" } "skip" { "hidden" "2" } "d" "3" } "e" "4"`)
+
+	var visited []string
+	err := doc.Walk(func(path []string, node *Node) error {
+		visited = append(visited, strings.Join(path, "/"))
+		if node.Key == "skip" {
+			return SkipChildren
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() returned error: %v", err)
+	}
+
+	want := []string{"a", "a/b", "a/b/c", "a/skip", "a/d", "e"}
+	if !slices.Equal(visited, want) {
+		t.Fatalf("Walk() visited %v, want %v", visited, want)
+	}
+
+	visited = visited[:0]
+	err = doc.Roots[0].First("b").Walk(func(path []string, _ *Node) error {
+		visited = append(visited, strings.Join(path, "/"))
+		return nil
+	})
+	if err != nil || !slices.Equal(visited, []string{"b", "b/c"}) {
+		t.Fatalf("Node.Walk() visited %v, %v", visited, err)
+	}
+
+	count := 0
+	err = doc.Walk(func([]string, *Node) error {
+		count++
+		if count == 2 {
+			return SkipAll
+		}
+
+		return nil
+	})
+	if err != nil || count != 2 {
+		t.Fatalf("Walk(SkipAll) = %d visits, %v, want 2, nil", count, err)
+	}
+
+	stop := errors.New("stop")
+	if err := doc.Walk(func([]string, *Node) error { return stop }); !errors.Is(err, stop) {
+		t.Fatalf("Walk(error) = %v, want stop", err)
+	}
+}
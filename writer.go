@@ -8,17 +8,20 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"strconv"
 )
 
 // Encoder encodes VDF documents to an output stream.
 type Encoder struct {
-	w                    io.Writer     // Writer for the output.
-	opts                 EncodeOptions // Encode options.
-	manualDepth          int           // Current depth for manual streaming.
-	manualBinaryUsed     bool          // Whether binary mode is used for manual streaming.
-	manualBinaryFinished bool          // Whether binary mode is finished for manual streaming.
+	w                    io.Writer       // Writer for the output.
+	checksum             *checksumWriter // Checksum writer for manual binary streaming.
+	text                 io.Writer       // Text output wrapped for EncodeOptions.Encoding and WriteBOM.
+	opts                 EncodeOptions   // Encode options.
+	manualDepth          int             // Current depth for manual streaming.
+	manualBinaryUsed     bool            // Whether binary mode is used for manual streaming.
+	manualBinaryFinished bool            // Whether binary mode is finished for manual streaming.
 }
 
 // NewEncoder creates a VDF encoder.
@@ -52,7 +55,11 @@ func (e *Encoder) EncodeDocument(doc *Document) error {
 
 	switch format {
 	case FormatText:
-		return encodeTextDocument(e.w, doc, e.opts)
+		if err := encodeTextDocument(e.textWriter(), doc, e.opts); err != nil {
+			return err
+		}
+
+		return e.flushText()
 	case FormatBinary:
 		return encodeBinaryDocument(e.w, doc, e.opts)
 	default:
@@ -62,17 +69,23 @@ func (e *Encoder) EncodeDocument(doc *Document) error {
 
 // StartObject begins an object in manual streaming mode.
 func (e *Encoder) StartObject(key string) error {
+	return e.startObject(key, "")
+}
+
+// startObject begins an object with an optional conditional, written only to text output.
+func (e *Encoder) startObject(key, condition string) error {
 	switch e.manualFormat() {
 	case FormatText:
-		return e.startTextObject(key)
+		return e.startTextObject(key, conditionSuffix(condition))
 
 	case FormatBinary:
 		e.manualBinaryUsed = true
 		e.manualDepth++
-		if err := writeBinaryByte(e.w, binaryTypeMapStart); err != nil {
+		bw := e.manualBinaryWriter()
+		if err := writeBinaryByte(bw, binaryTypeMapStart); err != nil {
 			return err
 		}
-		return writeNullTerminatedString(e.w, key)
+		return writeNullTerminatedString(bw, key)
 
 	default:
 		return fmt.Errorf("%w: %d", ErrInvalidFormat, e.opts.Format)
@@ -83,17 +96,18 @@ func (e *Encoder) StartObject(key string) error {
 func (e *Encoder) WriteString(key, value string) error {
 	switch e.manualFormat() {
 	case FormatText:
-		return e.writeTextLeaf(key, value)
+		return e.writeTextLeaf(key, value, "")
 
 	case FormatBinary:
 		e.manualBinaryUsed = true
-		if err := writeBinaryByte(e.w, binaryTypeString); err != nil {
+		bw := e.manualBinaryWriter()
+		if err := writeBinaryByte(bw, binaryTypeString); err != nil {
 			return err
 		}
-		if err := writeNullTerminatedString(e.w, key); err != nil {
+		if err := writeNullTerminatedString(bw, key); err != nil {
 			return err
 		}
-		return writeNullTerminatedString(e.w, value)
+		return writeNullTerminatedString(bw, value)
 
 	default:
 		return fmt.Errorf("%w: %d", ErrInvalidFormat, e.opts.Format)
@@ -104,20 +118,148 @@ func (e *Encoder) WriteString(key, value string) error {
 func (e *Encoder) WriteUint32(key string, value uint32) error {
 	switch e.manualFormat() {
 	case FormatText:
-		return e.writeTextLeaf(key, strconv.FormatUint(uint64(value), 10))
+		return e.writeTextLeaf(key, strconv.FormatUint(uint64(value), 10), "")
 
 	case FormatBinary:
 		e.manualBinaryUsed = true
-		if err := writeBinaryByte(e.w, binaryTypeNumber); err != nil {
+		bw := e.manualBinaryWriter()
+		if err := writeBinaryByte(bw, binaryTypeNumber); err != nil {
 			return err
 		}
-		if err := writeNullTerminatedString(e.w, key); err != nil {
+		if err := writeNullTerminatedString(bw, key); err != nil {
 			return err
 		}
 
 		var raw [4]byte
 		binary.LittleEndian.PutUint32(raw[:], value)
-		_, err := e.w.Write(raw[:])
+		_, err := bw.Write(raw[:])
+		return err
+
+	default:
+		return fmt.Errorf("%w: %d", ErrInvalidFormat, e.opts.Format)
+	}
+}
+
+// WriteWideString writes a string leaf in manual streaming mode.
+// Binary output stores the value as UTF-16LE (type 0x05); text output matches WriteString.
+func (e *Encoder) WriteWideString(key, value string) error {
+	switch e.manualFormat() {
+	case FormatText:
+		return e.writeTextLeaf(key, value, "")
+
+	case FormatBinary:
+		e.manualBinaryUsed = true
+		bw := e.manualBinaryWriter()
+		if err := writeBinaryByte(bw, binaryTypeWideString); err != nil {
+			return err
+		}
+		if err := writeNullTerminatedString(bw, key); err != nil {
+			return err
+		}
+		return writeWideString(bw, value)
+
+	default:
+		return fmt.Errorf("%w: %d", ErrInvalidFormat, e.opts.Format)
+	}
+}
+
+// WriteColor writes an RGBA color leaf in manual streaming mode.
+// Text output stores the four components as "R G B A".
+func (e *Encoder) WriteColor(key string, value Color) error {
+	switch e.manualFormat() {
+	case FormatText:
+		return e.writeTextLeaf(key, value.String(), "")
+
+	case FormatBinary:
+		e.manualBinaryUsed = true
+		bw := e.manualBinaryWriter()
+		if err := writeBinaryByte(bw, binaryTypeColor); err != nil {
+			return err
+		}
+		if err := writeNullTerminatedString(bw, key); err != nil {
+			return err
+		}
+
+		_, err := bw.Write([]byte{value.R, value.G, value.B, value.A})
+		return err
+
+	default:
+		return fmt.Errorf("%w: %d", ErrInvalidFormat, e.opts.Format)
+	}
+}
+
+// WritePointer writes a 32-bit pointer leaf in manual streaming mode.
+// Text output stores the decimal value.
+func (e *Encoder) WritePointer(key string, value uint32) error {
+	switch e.manualFormat() {
+	case FormatText:
+		return e.writeTextLeaf(key, strconv.FormatUint(uint64(value), 10), "")
+
+	case FormatBinary:
+		e.manualBinaryUsed = true
+		bw := e.manualBinaryWriter()
+		if err := writeBinaryByte(bw, binaryTypePointer); err != nil {
+			return err
+		}
+		if err := writeNullTerminatedString(bw, key); err != nil {
+			return err
+		}
+
+		var raw [4]byte
+		binary.LittleEndian.PutUint32(raw[:], value)
+		_, err := bw.Write(raw[:])
+		return err
+
+	default:
+		return fmt.Errorf("%w: %d", ErrInvalidFormat, e.opts.Format)
+	}
+}
+
+// WriteUint64 writes an unsigned 64-bit leaf in manual streaming mode.
+func (e *Encoder) WriteUint64(key string, value uint64) error {
+	switch e.manualFormat() {
+	case FormatText:
+		return e.writeTextLeaf(key, strconv.FormatUint(value, 10), "")
+
+	case FormatBinary:
+		e.manualBinaryUsed = true
+		bw := e.manualBinaryWriter()
+		if err := writeBinaryByte(bw, binaryTypeUint64); err != nil {
+			return err
+		}
+		if err := writeNullTerminatedString(bw, key); err != nil {
+			return err
+		}
+
+		var raw [8]byte
+		binary.LittleEndian.PutUint64(raw[:], value)
+		_, err := bw.Write(raw[:])
+		return err
+
+	default:
+		return fmt.Errorf("%w: %d", ErrInvalidFormat, e.opts.Format)
+	}
+}
+
+// WriteInt64 writes a signed 64-bit leaf in manual streaming mode.
+func (e *Encoder) WriteInt64(key string, value int64) error {
+	switch e.manualFormat() {
+	case FormatText:
+		return e.writeTextLeaf(key, strconv.FormatInt(value, 10), "")
+
+	case FormatBinary:
+		e.manualBinaryUsed = true
+		bw := e.manualBinaryWriter()
+		if err := writeBinaryByte(bw, binaryTypeInt64); err != nil {
+			return err
+		}
+		if err := writeNullTerminatedString(bw, key); err != nil {
+			return err
+		}
+
+		var raw [8]byte
+		binary.LittleEndian.PutUint64(raw[:], uint64(value))
+		_, err := bw.Write(raw[:])
 		return err
 
 	default:
@@ -125,6 +267,164 @@ func (e *Encoder) WriteUint32(key string, value uint32) error {
 	}
 }
 
+// WriteFloat32 writes a float32 leaf in manual streaming mode.
+// Text output stores the shortest decimal form of value.
+func (e *Encoder) WriteFloat32(key string, value float32) error {
+	switch e.manualFormat() {
+	case FormatText:
+		return e.writeTextLeaf(key, formatFloat32(value), "")
+
+	case FormatBinary:
+		e.manualBinaryUsed = true
+		bw := e.manualBinaryWriter()
+		if err := writeBinaryByte(bw, binaryTypeFloat32); err != nil {
+			return err
+		}
+		if err := writeNullTerminatedString(bw, key); err != nil {
+			return err
+		}
+
+		var raw [4]byte
+		binary.LittleEndian.PutUint32(raw[:], math.Float32bits(value))
+		_, err := bw.Write(raw[:])
+		return err
+
+	default:
+		return fmt.Errorf("%w: %d", ErrInvalidFormat, e.opts.Format)
+	}
+}
+
+// WriteDirective writes an "#include" or "#base" directive in manual streaming mode.
+// Directives exist only in text output and only outside objects.
+func (e *Encoder) WriteDirective(name, path string) error {
+	if e.manualFormat() != FormatText {
+		return fmt.Errorf("%w: directive %q cannot be encoded as binary", ErrInvalidFormat, name)
+	}
+
+	if e.manualDepth != 0 {
+		return fmt.Errorf("%w: directive %q inside an object", ErrInvalidNodeState, name)
+	}
+
+	return writeTextDirective(e.textWriter(), name, path, "", e.opts)
+}
+
+// WriteEvent writes one event from Decoder.NextEvent or Decoder.Token in manual
+// streaming mode, so events can be copied, filtered or renamed on the way
+// without building a Document. EventDocumentEnd finalizes the output like Close,
+// and EventDocumentStart writes nothing.
+func (e *Encoder) WriteEvent(event Event) error {
+	switch event.Type {
+	case EventDocumentStart:
+		return nil
+	case EventDocumentEnd:
+		return e.Close()
+	case EventObjectStart:
+		return e.startObject(event.Key, event.Condition)
+	case EventObjectEnd:
+		return e.EndObject()
+	default:
+		return e.writeEventLeaf(event)
+	}
+}
+
+// writeEventLeaf writes a scalar event as a leaf in manual streaming mode.
+func (e *Encoder) writeEventLeaf(event Event) error {
+	if e.manualFormat() == FormatText && event.Condition != "" {
+		return e.writeConditionalTextLeaf(event)
+	}
+
+	switch event.Type {
+	case EventString:
+		if event.StringValue == nil {
+			return missingEventValue(event, "string")
+		}
+		return e.WriteString(event.Key, *event.StringValue)
+	case EventUint32:
+		if event.Uint32Value == nil {
+			return missingEventValue(event, "uint32")
+		}
+		return e.WriteUint32(event.Key, *event.Uint32Value)
+	case EventFloat32:
+		if event.Float32Value == nil {
+			return missingEventValue(event, "float32")
+		}
+		return e.WriteFloat32(event.Key, *event.Float32Value)
+	case EventPointer:
+		if event.Uint32Value == nil {
+			return missingEventValue(event, "pointer")
+		}
+		return e.WritePointer(event.Key, *event.Uint32Value)
+	case EventWideString:
+		if event.StringValue == nil {
+			return missingEventValue(event, "wide string")
+		}
+		return e.WriteWideString(event.Key, *event.StringValue)
+	case EventColor:
+		if event.ColorValue == nil {
+			return missingEventValue(event, "color")
+		}
+		return e.WriteColor(event.Key, *event.ColorValue)
+	case EventUint64:
+		if event.Uint64Value == nil {
+			return missingEventValue(event, "uint64")
+		}
+		return e.WriteUint64(event.Key, *event.Uint64Value)
+	case EventInt64:
+		if event.Int64Value == nil {
+			return missingEventValue(event, "int64")
+		}
+		return e.WriteInt64(event.Key, *event.Int64Value)
+	case EventDirective:
+		if event.StringValue == nil {
+			return missingEventValue(event, "directive")
+		}
+		return e.WriteDirective(event.Key, *event.StringValue)
+	case EventRaw:
+		return e.writeRaw(event.Key, event.RawType, event.RawValue)
+	default:
+		return fmt.Errorf("%w: event type %d is not a leaf", ErrInvalidNodeState, event.Type)
+	}
+}
+
+// writeRaw writes a preserved raw entry in manual streaming mode.
+// Raw entries exist only in binary output.
+func (e *Encoder) writeRaw(key string, rawType byte, payload []byte) error {
+	if e.manualFormat() != FormatBinary {
+		return fmt.Errorf("%w: raw node %q cannot be encoded as text", ErrInvalidFormat, key)
+	}
+
+	e.manualBinaryUsed = true
+	return writeBinaryRaw(e.manualBinaryWriter(), key, rawType, payload)
+}
+
+// missingEventValue reports a leaf event without its payload.
+func missingEventValue(event Event, kind string) error {
+	return fmt.Errorf("%w: %s event %q missing value", ErrInvalidNodeState, kind, event.Key)
+}
+
+// writeConditionalTextLeaf writes a scalar event with a conditional in manual text mode.
+func (e *Encoder) writeConditionalTextLeaf(event Event) error {
+	node, ok := eventLeafNode(event)
+	if !ok {
+		return fmt.Errorf("%w: event type %d is not a leaf", ErrInvalidNodeState, event.Type)
+	}
+
+	value, err := textValueForNode(node)
+	if err != nil {
+		return err
+	}
+
+	if node.Kind == NodeDirective {
+		if e.manualDepth != 0 {
+			return fmt.Errorf("%w: directive %q inside an object", ErrInvalidNodeState, node.Key)
+		}
+
+		return writeTextDirective(e.textWriter(), node.Key, value, conditionSuffix(node.Condition), e.opts)
+	}
+
+	return e.writeTextLeaf(node.Key, value, conditionSuffix(node.Condition))
+}
+
 // EndObject ends an object in manual streaming mode.
 func (e *Encoder) EndObject() error {
 	switch e.manualFormat() {
@@ -140,7 +440,7 @@ func (e *Encoder) EndObject() error {
 			return fmt.Errorf("%w: no open object", ErrInvalidNodeState)
 		}
 		e.manualDepth--
-		return writeBinaryByte(e.w, binaryTypeMapEnd)
+		return writeBinaryByte(e.manualBinaryWriter(), binaryTypeMapEnd)
 
 	default:
 		return fmt.Errorf("%w: %d", ErrInvalidFormat, e.opts.Format)
@@ -149,6 +449,10 @@ func (e *Encoder) EndObject() error {
 
 // Close finalizes manual streaming state.
 func (e *Encoder) Close() error {
+	if err := e.flushText(); err != nil {
+		return err
+	}
+
 	if e.manualFormat() != FormatBinary || !e.manualBinaryUsed || e.manualBinaryFinished {
 		return nil
 	}
@@ -158,7 +462,15 @@ func (e *Encoder) Close() error {
 	}
 
 	e.manualBinaryFinished = true
-	return writeBinaryByte(e.w, binaryTypeMapEnd)
+	if err := writeBinaryByte(e.manualBinaryWriter(), binaryTypeMapEnd); err != nil {
+		return err
+	}
+
+	if e.checksum != nil {
+		return e.checksum.writeTrailer()
+	}
+
+	return nil
 }
 
 // Write encodes document as text VDF with default options.
@@ -222,7 +534,7 @@ func AppendText(dst []byte, doc *Document, opts EncodeOptions) ([]byte, error) {
 
 // AppendBinary appends binary VDF output to destination byte slice.
 func AppendBinary(dst []byte, doc *Document, opts EncodeOptions) ([]byte, error) {
-	extra := estimateBinaryDocumentSize(doc, opts.Deterministic)
+	extra := estimateBinaryDocumentSize(doc)
 	dst = reserveAppendCapacity(dst, extra)
 
 	writer := &sliceWriter{buf: dst}
@@ -258,6 +570,48 @@ func reserveAppendCapacity(dst []byte, extra int) []byte {
 	return out
 }
 
+// manualBinaryWriter returns the output used by manual binary streaming,
+// wrapping it with a checksum writer when EncodeOptions.Checksum is set.
+func (e *Encoder) manualBinaryWriter() io.Writer {
+	if !e.opts.Checksum {
+		return e.w
+	}
+
+	if e.checksum == nil {
+		e.checksum = &checksumWriter{w: e.w}
+	}
+
+	return e.checksum
+}
+
+// textWriter returns the output used by text encoding, wrapping it with a
+// UTF-16LE transcoder or a UTF-8 byte order mark as EncodeOptions select.
+func (e *Encoder) textWriter() io.Writer {
+	if e.text != nil {
+		return e.text
+	}
+
+	switch {
+	case e.opts.Encoding == EncodingUTF16LE:
+		e.text = &utf16Writer{w: e.w}
+	case e.opts.WriteBOM:
+		e.text = &bomWriter{w: e.w, bom: utf8BOM}
+	default:
+		e.text = e.w
+	}
+
+	return e.text
+}
+
+// flushText writes text output held back by the UTF-16LE transcoder.
+func (e *Encoder) flushText() error {
+	if u, ok := e.text.(*utf16Writer); ok {
+		return u.flush()
+	}
+
+	return nil
+}
+
 // manualFormat resolves effective format for manual streaming calls.
 func (e *Encoder) manualFormat() Format {
 	if e.opts.Format == FormatAuto {
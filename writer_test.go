@@ -3,7 +3,9 @@ package vdf
 import (
 	"bytes"
 	"errors"
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -188,3 +190,289 @@ func TestWriteFileWrappers(t *testing.T) {
 		t.Fatalf("binary file format = %v, want %v", binDoc.Format, FormatBinary)
 	}
 }
+
+func TestEncodeDeeplyNestedDocument(t *testing.T) {
+	t.Parallel()
+
+	const depth = 100000
+
+	root := NewObjectNode("k")
+	current := root
+	for i := 1; i < depth; i++ {
+		child := NewObjectNode("k")
+		current.Add(child)
+		current = child
+	}
+	current.Add(NewUint32Node("leaf", 1))
+
+	doc := NewDocument()
+	doc.AddRoot(root)
+
+	for _, format := range []Format{FormatText, FormatBinary} {
+		var buf bytes.Buffer
+		if err := NewEncoder(&buf, EncodeOptions{Format: format, Compact: true}).EncodeDocument(doc); err != nil {
+			t.Fatalf("EncodeDocument(format=%d) returned error: %v", format, err)
+		}
+
+		decoded, err := ParseBytes(buf.Bytes(), DecodeOptions{Format: format, MaxDepth: Unlimited})
+		if err != nil {
+			t.Fatalf("ParseBytes(format=%d) returned error: %v", format, err)
+		}
+
+		if len(decoded.Roots) != 1 {
+			t.Fatalf("decoded roots (format=%d) = %d, want 1", format, len(decoded.Roots))
+		}
+	}
+}
+
+func TestEncodeCyclicDocumentFails(t *testing.T) {
+	t.Parallel()
+
+	shallow := NewObjectNode("root")
+	shallow.Add(shallow)
+
+	deep := NewObjectNode("root")
+	current := deep
+	for i := 0; i < 2*encodePathScanLimit; i++ {
+		child := NewObjectNode("k")
+		current.Add(child)
+		current = child
+	}
+	current.Add(deep)
+
+	shared := NewObjectNode("shared")
+	shared.Add(NewStringNode("k", "v"))
+	acyclic := NewObjectNode("root")
+	acyclic.Add(shared)
+	acyclic.Add(shared)
+
+	for _, format := range []Format{FormatText, FormatBinary} {
+		for _, root := range []*Node{shallow, deep} {
+			doc := NewDocument()
+			doc.AddRoot(root)
+
+			var buf bytes.Buffer
+			err := NewEncoder(&buf, EncodeOptions{Format: format}).EncodeDocument(doc)
+			if !errors.Is(err, ErrInvalidNodeState) {
+				t.Fatalf("EncodeDocument(cyclic, format=%d) error = %v, want ErrInvalidNodeState", format, err)
+			}
+		}
+
+		doc := NewDocument()
+		doc.AddRoot(acyclic)
+
+		var buf bytes.Buffer
+		if err := NewEncoder(&buf, EncodeOptions{Format: format}).EncodeDocument(doc); err != nil {
+			t.Fatalf("EncodeDocument(shared subtree, format=%d) returned error: %v", format, err)
+		}
+	}
+
+	doc := NewDocument()
+	doc.AddRoot(deep)
+	if _, err := AppendBinary(nil, doc, EncodeOptions{}); !errors.Is(err, ErrInvalidNodeState) {
+		t.Fatalf("AppendBinary(cyclic) error = %v, want ErrInvalidNodeState", err)
+	}
+}
+
+func TestEncodeDeterministicDepth(t *testing.T) {
+	t.Parallel()
+
+	doc, err := ParseString(`"b" { "z" "1" "y" { "d" "1" "c" "2" } } "a" "x"`)
+	if err != nil {
+		t.Fatalf("ParseString() returned error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		depth int
+		want  string
+	}{
+		{name: "all levels", depth: 0, want: `"a" "x" "b" { "y" { "c" "2" "d" "1" } "z" "1" } `},
+		{name: "roots only", depth: 1, want: `"a" "x" "b" { "z" "1" "y" { "d" "1" "c" "2" } } `},
+		{name: "two levels", depth: 2, want: `"a" "x" "b" { "y" { "d" "1" "c" "2" } "z" "1" } `},
+	}
+
+	for _, tt := range tests {
+		out, err := AppendText(nil, doc, EncodeOptions{Compact: true, Deterministic: true, DeterministicDepth: tt.depth})
+		if err != nil {
+			t.Fatalf("AppendText(%s) returned error: %v", tt.name, err)
+		}
+
+		if string(out) != tt.want {
+			t.Fatalf("AppendText(%s) = %q, want %q", tt.name, out, tt.want)
+		}
+	}
+}
+
+func TestAppendToFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "log.vdf")
+	if err := os.WriteFile(path, []byte("// history\n\"first\"\n{\n\t\"a\"\t\t\"1\"\n}\n\n\t\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() returned error: %v", err)
+	}
+
+	second := NewObjectNode("second")
+	second.Add(NewStringNode("b", "2"))
+	if err := AppendToFile(path, second, NewStringNode("third", "3")); err != nil {
+		t.Fatalf("AppendToFile() returned error: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() returned error: %v", err)
+	}
+
+	want := "// history\n\"first\"\n{\n\t\"a\"\t\t\"1\"\n}\n\n\"second\"\n{\n\t\"b\"\t\t\"2\"\n}\n\n\"third\"\t\t\"3\"\n"
+	if string(got) != want {
+		t.Fatalf("appended file = %q, want %q", got, want)
+	}
+
+	empty := filepath.Join(t.TempDir(), "new.vdf")
+	if err := AppendToFile(empty, NewStringNode("k", "v")); err != nil {
+		t.Fatalf("AppendToFile(new) returned error: %v", err)
+	}
+
+	if got, _ := os.ReadFile(empty); string(got) != "\"k\"\t\t\"v\"\n" {
+		t.Fatalf("new file = %q", got)
+	}
+
+	broken := filepath.Join(t.TempDir(), "broken.vdf")
+	if err := os.WriteFile(broken, []byte("\"open\" {\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() returned error: %v", err)
+	}
+
+	if err := AppendToFile(broken, NewStringNode("k", "v")); !errors.Is(err, ErrUnexpectedEOFInObject) {
+		t.Fatalf("AppendToFile(broken) error=%v, want ErrUnexpectedEOFInObject", err)
+	}
+
+	if got, _ := os.ReadFile(broken); string(got) != "\"open\" {\n" {
+		t.Fatalf("broken file modified: %q", got)
+	}
+
+	wide, err := AppendText(nil, mustParseString(t, `"lang" { "x" "y" }`), EncodeOptions{Encoding: EncodingUTF16LE})
+	if err != nil {
+		t.Fatalf("AppendText(UTF-16) returned error: %v", err)
+	}
+
+	utf16Path := filepath.Join(t.TempDir(), "lang.txt")
+	if err := os.WriteFile(utf16Path, wide, 0o600); err != nil {
+		t.Fatalf("WriteFile() returned error: %v", err)
+	}
+
+	if err := AppendToFile(utf16Path, NewStringNode("k", "v")); !errors.Is(err, ErrInvalidFormat) {
+		t.Fatalf("AppendToFile(UTF-16) error=%v, want ErrInvalidFormat", err)
+	}
+
+	if got, _ := os.ReadFile(utf16Path); !bytes.Equal(got, wide) {
+		t.Fatalf("UTF-16 file modified: %q", got)
+	}
+}
+
+func TestEncodeRootBannerAndSeparator(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `"first" { "a" "1" } "second" "2"`)
+	opts := EncodeOptions{
+		RootSeparator: "\n// ----\n\n",
+		RootBanner: func(index int, root *Node) string {
+			return "generated by vdf-test\nroot " + strconv.Itoa(index) + ": " + root.Key
+		},
+	}
+
+	got, err := AppendText(nil, doc, opts)
+	if err != nil {
+		t.Fatalf("AppendText() returned error: %v", err)
+	}
+
+	want := "// generated by vdf-test\n// root 0: first\n\"first\"\n{\n\t\"a\"\t\t\"1\"\n}\n" +
+		"\n// ----\n\n" +
+		"// generated by vdf-test\n// root 1: second\n\"second\"\t\t\"2\"\n"
+	if string(got) != want {
+		t.Fatalf("AppendText() = %q, want %q", got, want)
+	}
+
+	opts.Compact = true
+	compact, err := AppendText(nil, doc, opts)
+	if err != nil {
+		t.Fatalf("AppendText(compact) returned error: %v", err)
+	}
+
+	for _, data := range [][]byte{got, compact} {
+		decoded, err := ParseBytes(data, DecodeOptions{Format: FormatText})
+		if err != nil {
+			t.Fatalf("ParseBytes(%q) returned error: %v", data, err)
+		}
+
+		if paths := ChangedPaths(doc, decoded); len(paths) != 0 {
+			t.Fatalf("bannered output changed paths %v", paths)
+		}
+	}
+}
+
+func TestEncodeQuoteStyle(t *testing.T) {
+	t.Parallel()
+
+	doc := mustParseString(t, `root { name "srv one" port 2302 "quoted" "x" }`)
+	doc.Roots[0].Add(NewStringNode("path", "[tag]"))
+
+	cases := []struct {
+		style QuoteStyle
+		want  string
+	}{
+		{QuoteAlways, `"root" { "name" "srv one" "port" "2302" "quoted" "x" "path" "[tag]" } `},
+		{QuotePreserve, `root { name "srv one" port 2302 "quoted" "x" "path" "[tag]" } `},
+		{QuoteWhenNeeded, `root { name "srv one" port 2302 quoted x path "[tag]" } `},
+	}
+
+	for _, tc := range cases {
+		got, err := AppendText(nil, doc, EncodeOptions{Compact: true, QuoteStyle: tc.style})
+		if err != nil {
+			t.Fatalf("AppendText(%d) returned error: %v", tc.style, err)
+		}
+
+		if string(got) != tc.want {
+			t.Fatalf("AppendText(%d) = %q, want %q", tc.style, got, tc.want)
+		}
+
+		decoded, err := ParseBytes(got, DecodeOptions{Format: FormatText})
+		if err != nil {
+			t.Fatalf("ParseBytes(%q) returned error: %v", got, err)
+		}
+
+		if paths := ChangedPaths(doc, decoded); len(paths) != 0 {
+			t.Fatalf("QuoteStyle %d changed paths %v", tc.style, paths)
+		}
+	}
+}
+
+func TestManualEncoderQuoteWhenNeeded(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, EncodeOptions{Format: FormatText, Compact: true, QuoteStyle: QuoteWhenNeeded})
+	if err := enc.StartObject("Settings"); err != nil {
+		t.Fatalf("StartObject() returned error: %v", err)
+	}
+
+	if err := enc.WriteString("path", `C:\Games`); err != nil {
+		t.Fatalf("WriteString() returned error: %v", err)
+	}
+
+	if err := enc.WriteUint32("port", 27015); err != nil {
+		t.Fatalf("WriteUint32() returned error: %v", err)
+	}
+
+	if err := enc.WriteString("empty", ""); err != nil {
+		t.Fatalf("WriteString() returned error: %v", err)
+	}
+
+	if err := enc.EndObject(); err != nil {
+		t.Fatalf("EndObject() returned error: %v", err)
+	}
+
+	want := `Settings { path "C:\\Games" port 27015 empty "" } `
+	if got := buf.String(); got != want {
+		t.Fatalf("manual output = %q, want %q", got, want)
+	}
+}
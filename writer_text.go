@@ -11,15 +11,21 @@ import (
 )
 
 // startTextObject writes object header in manual text encoding mode.
-func (e *Encoder) startTextObject(key string) error {
+// The suffix is empty or a conditional from conditionSuffix.
+func (e *Encoder) startTextObject(key, suffix string) error {
 	indent := strings.Repeat(e.opts.Indent, e.manualDepth)
+	key, err := quoteText(key, false, e.opts)
+	if err != nil {
+		return err
+	}
+
 	if e.opts.Compact {
-		_, err := fmt.Fprintf(e.w, "\"%s\" { ", escapeString(key))
+		_, err := fmt.Fprintf(e.textWriter(), "%s%s { ", key, suffix)
 		e.manualDepth++
 		return err
 	}
 
-	_, err := fmt.Fprintf(e.w, "%s\"%s\"\n%s{\n", indent, escapeString(key), indent)
+	_, err = fmt.Fprintf(e.textWriter(), "%s%s%s\n%s{\n", indent, key, suffix, indent)
 	if err != nil {
 		return err
 	}
@@ -32,37 +38,62 @@ func (e *Encoder) startTextObject(key string) error {
 func (e *Encoder) endTextObject() error {
 	indent := strings.Repeat(e.opts.Indent, e.manualDepth)
 	if e.opts.Compact {
-		_, err := fmt.Fprint(e.w, "} ")
+		_, err := fmt.Fprint(e.textWriter(), "} ")
 		return err
 	}
 
-	_, err := fmt.Fprintf(e.w, "%s}\n", indent)
+	_, err := fmt.Fprintf(e.textWriter(), "%s}\n", indent)
 	return err
 }
 
 // writeTextLeaf writes one scalar key/value line in manual text mode.
-func (e *Encoder) writeTextLeaf(key, value string) error {
+// The suffix is empty or a conditional from conditionSuffix.
+func (e *Encoder) writeTextLeaf(key, value, suffix string) error {
 	indent := strings.Repeat(e.opts.Indent, e.manualDepth)
+	key, err := quoteText(key, false, e.opts)
+	if err != nil {
+		return err
+	}
+
+	if value, err = quoteText(value, false, e.opts); err != nil {
+		return err
+	}
+
 	if e.opts.Compact {
-		_, err := fmt.Fprintf(e.w, "\"%s\" \"%s\" ", escapeString(key), escapeString(value))
+		_, err := fmt.Fprintf(e.textWriter(), "%s %s%s ", key, value, suffix)
 		return err
 	}
 
-	_, err := fmt.Fprintf(e.w, "%s\"%s\"\t\t\"%s\"\n", indent, escapeString(key), escapeString(value))
+	_, err = fmt.Fprintf(e.textWriter(), "%s%s\t\t%s%s\n", indent, key, value, suffix)
 	return err
 }
 
 // encodeTextDocument writes the full document in text VDF format.
 func encodeTextDocument(w io.Writer, doc *Document, opts EncodeOptions) error {
-	roots := orderedNodes(doc.Roots, opts.Deterministic)
+	if opts.Fidelity {
+		return encodeFidelityDocument(w, doc, opts)
+	}
+
+	roots := orderedNodes(doc.Roots, opts.Deterministic, opts.Collation)
+
+	separator := "\n"
+	if opts.RootSeparator != "" {
+		separator = opts.RootSeparator
+	}
 
 	for i, root := range roots {
+		if opts.RootBanner != nil {
+			if err := writeTextBanner(w, opts.RootBanner(i, root)); err != nil {
+				return err
+			}
+		}
+
 		if err := encodeTextNode(w, root, opts, 0); err != nil {
 			return err
 		}
 
 		if !opts.Compact && i < len(roots)-1 {
-			if _, err := io.WriteString(w, "\n"); err != nil {
+			if _, err := io.WriteString(w, separator); err != nil {
 				return err
 			}
 		}
@@ -71,58 +102,179 @@ func encodeTextDocument(w io.Writer, doc *Document, opts EncodeOptions) error {
 	return nil
 }
 
-// encodeTextNode writes one AST node in text VDF format.
+// writeTextBanner writes banner text as "//" line comments.
+func writeTextBanner(w io.Writer, banner string) error {
+	if banner == "" {
+		return nil
+	}
+
+	for line := range strings.SplitSeq(strings.TrimRight(banner, "\n"), "\n") {
+		comment := "//\n"
+		if line != "" {
+			comment = "// " + line + "\n"
+		}
+
+		if _, err := io.WriteString(w, comment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeTextNode writes one AST node subtree in text VDF format.
+// Traversal uses an explicit stack, so nesting depth is not limited by the goroutine stack,
+// and cyclic object graphs fail with ErrInvalidNodeState instead of looping forever.
 func encodeTextNode(w io.Writer, node *Node, opts EncodeOptions, depth int) error {
-	indent := strings.Repeat(opts.Indent, depth)
+	if err := writeTextNodeOpen(w, node, opts, depth); err != nil {
+		return err
+	}
 
-	switch node.Kind {
-	case NodeObject:
-		if opts.Compact {
-			if _, err := fmt.Fprintf(w, "\"%s\" { ", escapeString(node.Key)); err != nil {
+	if node.Kind != NodeObject {
+		return nil
+	}
+
+	// Reuse the same traversal ordering policy as document-level encode.
+	stack := newEncodeStack(opts.Deterministic, opts.DeterministicDepth, opts.Collation)
+	if err := stack.push(node); err != nil {
+		return err
+	}
+
+	for stack.depth() > 0 {
+		top := stack.top()
+		childDepth := depth + stack.depth()
+
+		if top.index < len(top.children) {
+			child := top.children[top.index]
+			top.index++
+
+			if err := writeTextNodeOpen(w, child, opts, childDepth); err != nil {
 				return err
 			}
 
-			// Reuse the same traversal ordering policy as document-level encode.
-			children := orderedNodes(node.Children, opts.Deterministic)
-			for _, child := range children {
-				if err := encodeTextNode(w, child, opts, depth+1); err != nil {
+			if child.Kind == NodeObject {
+				if err := stack.push(child); err != nil {
 					return err
 				}
 			}
 
-			_, err := io.WriteString(w, "} ")
+			continue
+		}
+
+		stack.pop()
+		if err := writeTextObjectEnd(w, opts, childDepth-1); err != nil {
 			return err
 		}
+	}
 
-		if _, err := fmt.Fprintf(w, "%s\"%s\"\n%s{\n", indent, escapeString(node.Key), indent); err != nil {
+	return nil
+}
+
+// writeTextNodeOpen writes a leaf line or an object header for one node.
+func writeTextNodeOpen(w io.Writer, node *Node, opts EncodeOptions, depth int) error {
+	if node == nil {
+		return fmt.Errorf("%w: nil node", ErrInvalidNodeState)
+	}
+
+	switch node.Kind {
+	case NodeObject:
+		key, err := quoteText(node.Key, node.KeyUnquoted, opts)
+		if err != nil {
 			return err
 		}
 
-		// Keep ordering behavior consistent across compact and pretty branches.
-		children := orderedNodes(node.Children, opts.Deterministic)
-		for _, child := range children {
-			if err := encodeTextNode(w, child, opts, depth+1); err != nil {
-				return err
-			}
+		if opts.Compact {
+			_, err := fmt.Fprintf(w, "%s%s { ", key, conditionSuffix(node.Condition))
+			return err
 		}
 
-		_, err := fmt.Fprintf(w, "%s}\n", indent)
+		indent := strings.Repeat(opts.Indent, depth)
+		_, err = fmt.Fprintf(w, "%s%s%s\n%s{\n", indent, key, conditionSuffix(node.Condition), indent)
 		return err
-	case NodeString, NodeUint32:
+	case NodeDirective:
+		if node.StringValue == nil {
+			return fmt.Errorf("%w: directive node %q missing value", ErrInvalidNodeState, node.Key)
+		}
+
+		return writeTextDirective(w, node.Key, *node.StringValue, conditionSuffix(node.Condition), opts)
+	default:
 		value, err := textValueForNode(node)
 		if err != nil {
 			return err
 		}
 
+		key, err := quoteText(node.Key, node.KeyUnquoted, opts)
+		if err != nil {
+			return err
+		}
+
+		if value, err = quoteText(value, node.ValueUnquoted, opts); err != nil {
+			return err
+		}
+
 		if opts.Compact {
-			_, err := fmt.Fprintf(w, "\"%s\" \"%s\" ", escapeString(node.Key), escapeString(value))
+			_, err := fmt.Fprintf(w, "%s %s%s ", key, value, conditionSuffix(node.Condition))
 			return err
 		}
 
-		_, err = fmt.Fprintf(w, "%s\"%s\"\t\t\"%s\"\n", indent, escapeString(node.Key), escapeString(value))
+		indent := strings.Repeat(opts.Indent, depth)
+		_, err = fmt.Fprintf(w, "%s%s\t\t%s%s\n", indent, key, value, conditionSuffix(node.Condition))
 		return err
+	}
+}
+
+// writeTextDirective writes an "#include" or "#base" line with an unquoted directive name,
+// followed by suffix, which is empty or a conditional from conditionSuffix.
+// The path is always quoted.
+func writeTextDirective(w io.Writer, name, path, suffix string, opts EncodeOptions) error {
+	path, err := quoteText(path, false, EncodeOptions{DisableEscapes: opts.DisableEscapes})
+	if err != nil {
+		return err
+	}
+
+	if opts.Compact {
+		_, err := fmt.Fprintf(w, "%s %s%s ", name, path, suffix)
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s %s%s\n", name, path, suffix)
+	return err
+}
+
+// conditionSuffix returns the " [cond]" text written after an entry with a conditional.
+func conditionSuffix(condition string) string {
+	if condition == "" {
+		return ""
+	}
+
+	return " [" + condition + "]"
+}
+
+// writeTextObjectEnd writes one object footer at the given depth.
+func writeTextObjectEnd(w io.Writer, opts EncodeOptions, depth int) error {
+	if opts.Compact {
+		_, err := io.WriteString(w, "} ")
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "%s}\n", strings.Repeat(opts.Indent, depth))
+	return err
+}
+
+// quoteText renders a key or scalar value token under opts.QuoteStyle and opts.DisableEscapes.
+// unquoted reports whether the token was unquoted in the decoded source.
+func quoteText(value string, unquoted bool, opts EncodeOptions) (string, error) {
+	switch {
+	case opts.QuoteStyle == QuoteWhenNeeded && isSafeUnquoted(value):
+		return value, nil
+	case opts.QuoteStyle == QuotePreserve && unquoted && isSafeUnquoted(value):
+		return value, nil
+	case !opts.DisableEscapes:
+		return `"` + escapeString(value) + `"`, nil
+	case strings.ContainsRune(value, '"'):
+		return "", fmt.Errorf("%w: %q holds a double quote, which needs escapes", ErrValueConversion, value)
 	default:
-		return fmt.Errorf("%w: unsupported node kind %d", ErrInvalidNodeState, node.Kind)
+		return `"` + value + `"`, nil
 	}
 }
 
@@ -135,8 +287,9 @@ func escapeString(value string) string {
 	var sb strings.Builder
 	sb.Grow(len(value) + 8)
 
-	for _, r := range value {
-		switch r {
+	// Escaped runes are ASCII, so bytes are copied as is, keeping invalid UTF-8 intact.
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
 		case '\\':
 			sb.WriteString("\\\\")
 		case '"':
@@ -148,7 +301,7 @@ func escapeString(value string) string {
 		case '\r':
 			sb.WriteString("\\r")
 		default:
-			sb.WriteRune(r)
+			sb.WriteByte(c)
 		}
 	}
 